@@ -47,7 +47,11 @@ func main() {
 
 	// HTTP (Gin)
 	r := gin.Default()
-	handlers.RegisterMovieRoutes(r, movieSvc)
+	r.Use(handlers.RequestID())
+	handlers.RegisterMovieRoutes(r, movieSvc, handlers.CacheControl{
+		Movie: os.Getenv("CACHE_CONTROL_MOVIE"),
+		List:  os.Getenv("CACHE_CONTROL_LIST"),
+	})
 
 	// Swagger UI
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(
@@ -17,45 +17,609 @@ const docTemplate = `{
     "paths": {
         "/movies": {
             "get": {
+                "description": "A próxima página é indicada no header X-Next-Cursor (ausente na última página).",
                 "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Lista filmes (paginação por cursor)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Máximo de itens retornados (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco retornado em X-Next-Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano mínimo (inclusivo)",
+                        "name": "min_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano máximo (inclusivo)",
+                        "name": "max_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Prefixo do título (case-insensitive)",
+                        "name": "title_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "title",
+                            "year"
+                        ],
+                        "type": "string",
+                        "description": "Campo de ordenação",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Direção da ordenação",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de uma resposta anterior",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Movie"
+                            }
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Política de cache (configurável)"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da página"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor da próxima página"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "página não mudou desde o ETag informado",
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Política de cache (configurável)"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da página"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor da próxima página"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Cria um novo filme",
+                "parameters": [
+                    {
+                        "description": "Movie",
+                        "name": "movie",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Movie"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Movie"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão do filme (para If-Match)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "movie already exists (title+year); conflict-trashed if it is in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/events": {
+            "get": {
+                "description": "Server-Sent Events com criação, alteração e remoção de filmes: ` + "`" + `event` + "`" + ` é created, updated ou deleted e ` + "`" + `data` + "`" + ` é o filme (JSON). Ir para a lixeira é deleted (com deleted_at), restaurar é created e a remoção definitiva pela limpeza é deleted de novo. Para retomar sem perder eventos envie Last-Event-ID (ou ?last_event_id=) com o id do último evento recebido; se ele não puder mais ser retomado chega um ` + "`" + `event: reset` + "`" + ` e o stream fecha (recarregue o estado e reconecte sem id). Comentários ` + "`" + `: ping` + "`" + ` são enviados periodicamente. Clientes que não acompanham o ritmo são desconectados e podem retomar com Last-Event-ID.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Stream de mudanças no catálogo (SSE)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id do último evento recebido",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "alternativa ao header Last-Event-ID",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "stream text/event-stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/search": {
+            "get": {
+                "description": "Procura os termos de q em título, título original, diretores, elenco, gêneros e sinopse, sem diferenciar maiúsculas nem acentos (\"lumiere\" encontra \"Lumière\"). Basta um dos termos casar; quem casa mais termos (principalmente no título) vem primeiro. \"frase exata\" e -termo também são aceitos. A próxima página é indicada no header X-Next-Cursor.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Busca textual de filmes, por relevância",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Termos da busca (até 200 caracteres)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de itens retornados (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco retornado em X-Next-Cursor (só vale para a mesma busca)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano mínimo (inclusivo)",
+                        "name": "min_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano máximo (inclusivo)",
+                        "name": "max_year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.SearchResult"
+                            }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor da próxima página"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "missing q or invalid query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/suggest": {
+            "get": {
+                "description": "Sugere filmes com alguma palavra do título começando por prefix, sem diferenciar maiúsculas nem acentos. Títulos que começam pelo prefixo vêm primeiro; depois, os mais recentes.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Autocomplete de títulos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Início da palavra digitada (até 100 caracteres)",
+                        "name": "prefix",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de sugestões (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.TitleSuggestion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "missing prefix",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/trash": {
+            "get": {
+                "description": "Mesmos filtros, ordenação e paginação de GET /movies; cada filme traz deleted_at. Filmes ficam na lixeira até serem restaurados ou removidos de vez pela limpeza periódica (TRASH_RETENTION).",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Lista a lixeira (filmes apagados)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Máximo de itens retornados (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco retornado em X-Next-Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano mínimo (inclusivo)",
+                        "name": "min_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ano máximo (inclusivo)",
+                        "name": "max_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Prefixo do título (case-insensitive)",
+                        "name": "title_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "title",
+                            "year"
+                        ],
+                        "type": "string",
+                        "description": "Campo de ordenação",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Direção da ordenação",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de uma resposta anterior",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Movie"
+                            }
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Política de cache (configurável)"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da página"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor da próxima página"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "página não mudou desde o ETag informado",
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Política de cache (configurável)"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da página"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor da próxima página"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/trash/{id}": {
+            "get": {
+                "description": "O ETag da resposta é o If-Match do POST /movies/{id}/restore.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Busca um filme na lixeira",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de uma resposta anterior",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Movie"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Política de cache (configurável)"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão do filme (para If-Match do restore)"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "filme não mudou desde o ETag informado",
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Política de cache (configurável)"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão do filme (para If-Match do restore)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not in trash",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}": {
+            "get": {
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
                 "tags": [
                     "movies"
                 ],
-                "summary": "Lista todos os filmes",
+                "summary": "Busca um filme por ID",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Máximo de itens retornados (default 50, max 200)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de uma resposta anterior",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Movie"
+                            "$ref": "#/definitions/domain.Movie"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Política de cache (configurável)"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão do filme"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "filme não mudou desde o ETag informado",
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Política de cache (configurável)"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão do filme"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
                     }
                 }
             },
-            "post": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
                     "movies"
                 ],
-                "summary": "Cria um novo filme",
+                "summary": "Substitui os campos editáveis de um filme",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag do filme lido",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Movie",
                         "name": "movie",
@@ -67,30 +631,60 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/domain.Movie"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão do filme"
+                            }
                         }
                     },
                     "400": {
                         "description": "invalid body",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "movie already exists (title+year); conflict-trashed if it is in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "movie changed since the If-Match ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match missing",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/movies/{id}": {
-            "get": {
+            },
+            "delete": {
+                "description": "O filme some de GET /movies, /movies/{id} e da busca, mas pode ser restaurado (POST /movies/{id}/restore) até a limpeza periódica removê-lo de vez. A resposta traz o filme na lixeira e o ETag para o If-Match do restore.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
                     "movies"
                 ],
-                "summary": "Busca um filme por ID",
+                "summary": "Manda um filme para a lixeira",
                 "parameters": [
                     {
                         "type": "string",
@@ -98,6 +692,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag do filme lido",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -105,27 +706,53 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/domain.Movie"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão do filme na lixeira (para If-Match do restore)"
+                            }
                         }
                     },
                     "400": {
                         "description": "invalid id",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.Problem"
                         }
                     },
                     "404": {
                         "description": "movie not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "movie changed since the If-Match ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match missing",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
                         }
                     }
                 }
             },
-            "delete": {
+            "patch": {
+                "description": "Só os campos presentes no corpo são alterados.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
                 "tags": [
                     "movies"
                 ],
-                "summary": "Remove um filme",
+                "summary": "Atualiza parcialmente um filme",
                 "parameters": [
                     {
                         "type": "string",
@@ -133,16 +760,131 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag do filme lido",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos a alterar",
+                        "name": "movie",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.MoviePatch"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Movie"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão do filme"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
                     },
                     "404": {
                         "description": "movie not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "movie already exists (title+year); conflict-trashed if it is in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "movie changed since the If-Match ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match missing",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}/restore": {
+            "post": {
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Restaura um filme da lixeira",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag do filme na lixeira (DELETE ou GET /movies/trash/{id})",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Movie"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão do filme"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "movie not in trash",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "movie changed since the If-Match ETag",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match missing",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
                         }
                     }
                 }
@@ -153,7 +895,118 @@ const docTemplate = `{
         "domain.Movie": {
             "type": "object",
             "properties": {
+                "cast": {
+                    "description": "elenco principal",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Brigitte Helm",
+                        "Alfred Abel"
+                    ]
+                },
+                "deleted_at": {
+                    "description": "Somente leitura: quando o filme foi para a lixeira (só em GET\n/movies/trash e nos eventos).",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2025-03-01T12:00:00Z"
+                },
+                "directors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Fritz Lang"
+                    ]
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Drama",
+                        "Sci-Fi"
+                    ]
+                },
                 "id": {
+                    "type": "string",
+                    "example": "8"
+                },
+                "original_language": {
+                    "description": "ISO 639-1",
+                    "type": "string",
+                    "example": "de"
+                },
+                "original_title": {
+                    "description": "Somente leitura: título como veio na importação, quando o \"(YYYY)\"\nembutido foi removido de title.",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "Metropolis (1927)"
+                },
+                "poster_url": {
+                    "type": "string",
+                    "example": "https://img.example/metropolis.jpg"
+                },
+                "runtime_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 0,
+                    "example": 153
+                },
+                "synopsis": {
+                    "type": "string",
+                    "example": "In a futuristic city sharply divided between the working class and the city planners..."
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Metropolis"
+                },
+                "version": {
+                    "description": "Somente leitura: aumenta a cada alteração; vai no ETag e é conferida\npelo If-Match de PUT, PATCH e DELETE.",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 3
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 1927
+                }
+            }
+        },
+        "domain.MoviePatch": {
+            "type": "object",
+            "properties": {
+                "cast": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "directors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "original_language": {
+                    "type": "string"
+                },
+                "poster_url": {
+                    "type": "string"
+                },
+                "runtime_minutes": {
+                    "type": "integer"
+                },
+                "synopsis": {
                     "type": "string"
                 },
                 "title": {
@@ -163,6 +1016,164 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "domain.SearchResult": {
+            "type": "object",
+            "properties": {
+                "cast": {
+                    "description": "elenco principal",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Brigitte Helm",
+                        "Alfred Abel"
+                    ]
+                },
+                "deleted_at": {
+                    "description": "Somente leitura: quando o filme foi para a lixeira (só em GET\n/movies/trash e nos eventos).",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2025-03-01T12:00:00Z"
+                },
+                "directors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Fritz Lang"
+                    ]
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Drama",
+                        "Sci-Fi"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "8"
+                },
+                "original_language": {
+                    "description": "ISO 639-1",
+                    "type": "string",
+                    "example": "de"
+                },
+                "original_title": {
+                    "description": "Somente leitura: título como veio na importação, quando o \"(YYYY)\"\nembutido foi removido de title.",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "Metropolis (1927)"
+                },
+                "poster_url": {
+                    "type": "string",
+                    "example": "https://img.example/metropolis.jpg"
+                },
+                "runtime_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 0,
+                    "example": 153
+                },
+                "score": {
+                    "description": "maior = mais relevante; só comparável na mesma busca",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "synopsis": {
+                    "type": "string",
+                    "example": "In a futuristic city sharply divided between the working class and the city planners..."
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Metropolis"
+                },
+                "version": {
+                    "description": "Somente leitura: aumenta a cada alteração; vai no ETag e é conferida\npelo If-Match de PUT, PATCH e DELETE.",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 3
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 1927
+                }
+            }
+        },
+        "domain.TitleSuggestion": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "370"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The Great Train Robbery"
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 1903
+                }
+            }
+        },
+        "handlers.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "year"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "year must be between 1800 and 3000"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "RANGE"
+                }
+            }
+        },
+        "handlers.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "year must be between 1800 and 3000"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/movies"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "4f6c2a9e0d1b7c3a"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Validation failed"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "/problems/validation-error"
+                }
+            }
         }
     }
 }`
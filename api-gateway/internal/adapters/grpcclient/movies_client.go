@@ -3,13 +3,15 @@ package grpcclient
 import (
 	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
 	"github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/ports"
 	moviespb "github.com/caiqueborghese/sipubtech-challenge/proto/moviespb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 var _ ports.MoviesClient = (*Client)(nil)
@@ -35,15 +37,64 @@ func New(conn *grpc.ClientConn) ports.MoviesClient {
 	return &Client{cli: moviespb.NewMovieServiceClient(conn)}
 }
 
-func (c *Client) List(ctx context.Context) ([]domain.Movie, error) {
-	res, err := c.cli.ListMovies(ctx, &emptypb.Empty{})
+func (c *Client) List(ctx context.Context, p domain.ListParams) (domain.MoviePage, error) {
+	return toPage(c.cli.ListMovies(ctx, listRequest(p)))
+}
+
+func (c *Client) ListDeleted(ctx context.Context, p domain.ListParams) (domain.MoviePage, error) {
+	return toPage(c.cli.ListDeletedMovies(ctx, listRequest(p)))
+}
+
+func listRequest(p domain.ListParams) *moviespb.ListMoviesRequest {
+	return &moviespb.ListMoviesRequest{
+		PageSize:    int32(p.Limit),
+		PageToken:   p.Cursor,
+		MinYear:     int32(p.MinYear),
+		MaxYear:     int32(p.MaxYear),
+		TitlePrefix: p.TitlePrefix,
+		SortBy:      p.SortBy,
+		SortOrder:   p.SortOrder,
+	}
+}
+
+func toPage(res *moviespb.ListMoviesResponse, err error) (domain.MoviePage, error) {
 	if err != nil {
-		return nil, err
+		return domain.MoviePage{}, err
 	}
 	out := make([]domain.Movie, 0, len(res.Movies))
 	for _, m := range res.Movies {
 		out = append(out, fromPB(m))
 	}
+	return domain.MoviePage{Movies: out, NextCursor: res.NextPageToken}, nil
+}
+
+func (c *Client) Search(ctx context.Context, p domain.SearchParams) (domain.SearchPage, error) {
+	res, err := c.cli.SearchMovies(ctx, &moviespb.SearchMoviesRequest{
+		Query:     p.Query,
+		PageSize:  int32(p.Limit),
+		PageToken: p.Cursor,
+		MinYear:   int32(p.MinYear),
+		MaxYear:   int32(p.MaxYear),
+	})
+	if err != nil {
+		return domain.SearchPage{}, err
+	}
+	out := make([]domain.SearchResult, 0, len(res.Results))
+	for _, r := range res.Results {
+		out = append(out, domain.SearchResult{Movie: fromPB(r.Movie), Score: r.Score})
+	}
+	return domain.SearchPage{Results: out, NextCursor: res.NextPageToken}, nil
+}
+
+func (c *Client) Suggest(ctx context.Context, prefix string, limit int) ([]domain.TitleSuggestion, error) {
+	res, err := c.cli.SuggestTitles(ctx, &moviespb.SuggestTitlesRequest{Prefix: prefix, Limit: int32(limit)})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.TitleSuggestion, 0, len(res.Suggestions))
+	for _, t := range res.Suggestions {
+		out = append(out, domain.TitleSuggestion{ID: t.Id, Title: t.Title, Year: int(t.Year)})
+	}
 	return out, nil
 }
 
@@ -58,8 +109,15 @@ func (c *Client) Get(ctx context.Context, id string) (*domain.Movie, error) {
 
 func (c *Client) Create(ctx context.Context, m domain.Movie) (*domain.Movie, error) {
 	res, err := c.cli.CreateMovie(ctx, &moviespb.CreateMovieRequest{
-		Title: m.Title,
-		Year:  int32(m.Year),
+		Title:            m.Title,
+		Year:             int32(m.Year),
+		Genres:           m.Genres,
+		RuntimeMinutes:   int32(m.RuntimeMinutes),
+		Directors:        m.Directors,
+		Cast:             m.Cast,
+		Synopsis:         m.Synopsis,
+		OriginalLanguage: m.OriginalLanguage,
+		PosterUrl:        m.PosterURL,
 	})
 	if err != nil {
 		return nil, err
@@ -68,9 +126,75 @@ func (c *Client) Create(ctx context.Context, m domain.Movie) (*domain.Movie, err
 	return &dm, nil
 }
 
-func (c *Client) Delete(ctx context.Context, id string) error {
-	_, err := c.cli.DeleteMovie(ctx, &moviespb.DeleteMovieRequest{Id: id})
-	return err
+func (c *Client) Update(ctx context.Context, id string, m domain.Movie, fields []string, expectedVersion int64) (*domain.Movie, error) {
+	res, err := c.cli.UpdateMovie(ctx, &moviespb.UpdateMovieRequest{
+		Id:              id,
+		Movie:           toPB(m),
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: fields},
+		ExpectedVersion: expectedVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dm := fromPB(res.Movie)
+	return &dm, nil
+}
+
+func (c *Client) Delete(ctx context.Context, id string, expectedVersion int64) (*domain.Movie, error) {
+	res, err := c.cli.DeleteMovie(ctx, &moviespb.DeleteMovieRequest{Id: id, ExpectedVersion: expectedVersion})
+	if err != nil {
+		return nil, err
+	}
+	dm := fromPB(res.Movie)
+	return &dm, nil
+}
+
+func (c *Client) GetDeleted(ctx context.Context, id string) (*domain.Movie, error) {
+	res, err := c.cli.GetDeletedMovie(ctx, &moviespb.GetMovieRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	dm := fromPB(res.Movie)
+	return &dm, nil
+}
+
+func (c *Client) Restore(ctx context.Context, id string, expectedVersion int64) (*domain.Movie, error) {
+	res, err := c.cli.RestoreMovie(ctx, &moviespb.RestoreMovieRequest{Id: id, ExpectedVersion: expectedVersion})
+	if err != nil {
+		return nil, err
+	}
+	dm := fromPB(res.Movie)
+	return &dm, nil
+}
+
+var changeTypes = map[moviespb.MovieChange_Type]string{
+	moviespb.MovieChange_CREATED: domain.ChangeCreated,
+	moviespb.MovieChange_UPDATED: domain.ChangeUpdated,
+	moviespb.MovieChange_DELETED: domain.ChangeDeleted,
+}
+
+func (c *Client) Watch(ctx context.Context, lastEventID string, fn func(domain.MovieChange) error) error {
+	stream, err := c.cli.WatchMovies(ctx, &moviespb.WatchMoviesRequest{ResumeToken: lastEventID})
+	if err != nil {
+		return err
+	}
+	for {
+		ch, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = fn(domain.MovieChange{
+			ID:    ch.GetResumeToken(),
+			Type:  changeTypes[ch.GetType()],
+			Movie: fromPB(ch.GetMovie()),
+		})
+		if err != nil {
+			return err
+		}
+	}
 }
 
 func fromPB(m *moviespb.Movie) domain.Movie {
@@ -78,8 +202,41 @@ func fromPB(m *moviespb.Movie) domain.Movie {
 		return domain.Movie{}
 	}
 	return domain.Movie{
-		ID:    m.Id,
-		Title: m.Title,
-		Year:  int(m.Year),
+		ID:               m.Id,
+		Title:            m.Title,
+		Year:             int(m.Year),
+		Genres:           m.Genres,
+		RuntimeMinutes:   int(m.RuntimeMinutes),
+		Directors:        m.Directors,
+		Cast:             m.Cast,
+		Synopsis:         m.Synopsis,
+		OriginalLanguage: m.OriginalLanguage,
+		PosterURL:        m.PosterUrl,
+		OriginalTitle:    m.OriginalTitle,
+		Version:          m.Version,
+		DeletedAt:        deletedAt(m),
+	}
+}
+
+func deletedAt(m *moviespb.Movie) *time.Time {
+	if m.DeletedAt == nil {
+		return nil
+	}
+	t := m.DeletedAt.AsTime()
+	return &t
+}
+
+func toPB(m domain.Movie) *moviespb.Movie {
+	return &moviespb.Movie{
+		Id:               m.ID,
+		Title:            m.Title,
+		Year:             int32(m.Year),
+		Genres:           m.Genres,
+		RuntimeMinutes:   int32(m.RuntimeMinutes),
+		Directors:        m.Directors,
+		Cast:             m.Cast,
+		Synopsis:         m.Synopsis,
+		OriginalLanguage: m.OriginalLanguage,
+		PosterUrl:        m.PosterURL,
 	}
 }
@@ -3,12 +3,33 @@ package domain
 import (
 	"errors"
 	"strings"
+	"time"
 )
 
+// Movie filme do catálogo. Só title e year são obrigatórios; os demais
+// campos são opcionais e omitidos do JSON quando vazios.
 type Movie struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
-	Year  int    `json:"year"`
+	ID    string `json:"id" example:"8"`
+	Title string `json:"title" example:"Metropolis"`
+	Year  int    `json:"year" example:"1927"`
+
+	Genres           []string `json:"genres,omitempty" example:"Drama,Sci-Fi"`
+	RuntimeMinutes   int      `json:"runtime_minutes,omitempty" example:"153" minimum:"0" maximum:"1440"`
+	Directors        []string `json:"directors,omitempty" example:"Fritz Lang"`
+	Cast             []string `json:"cast,omitempty" example:"Brigitte Helm,Alfred Abel"` // elenco principal
+	Synopsis         string   `json:"synopsis,omitempty" example:"In a futuristic city sharply divided between the working class and the city planners..."`
+	OriginalLanguage string   `json:"original_language,omitempty" example:"de"` // ISO 639-1
+	PosterURL        string   `json:"poster_url,omitempty" example:"https://img.example/metropolis.jpg"`
+
+	// Somente leitura: título como veio na importação, quando o "(YYYY)"
+	// embutido foi removido de title.
+	OriginalTitle string `json:"original_title,omitempty" example:"Metropolis (1927)" readonly:"true"`
+	// Somente leitura: aumenta a cada alteração; vai no ETag e é conferida
+	// pelo If-Match de PUT, PATCH e DELETE.
+	Version int64 `json:"version,omitempty" example:"3" readonly:"true"`
+	// Somente leitura: quando o filme foi para a lixeira (só em GET
+	// /movies/trash e nos eventos).
+	DeletedAt *time.Time `json:"deleted_at,omitempty" example:"2025-03-01T12:00:00Z" readonly:"true"`
 }
 
 var (
@@ -17,16 +38,126 @@ var (
 	ErrValidation = errors.New("validation error")
 )
 
+// Tipos de MovieChange (nome do evento SSE em GET /movies/events).
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated"
+	ChangeDeleted = "deleted"
+)
+
+// MovieChange mudança no catálogo. ID é o resume token do serviço movies,
+// usado como id do evento SSE (Last-Event-ID).
+type MovieChange struct {
+	ID    string
+	Type  string
+	Movie Movie
+}
+
+// MoviePatch corpo do PATCH: só os campos presentes no JSON são alterados.
+// Para limpar um campo opcional envie "" (texto), 0 (runtime) ou [] (listas).
+type MoviePatch struct {
+	Title            *string   `json:"title,omitempty"`
+	Year             *int      `json:"year,omitempty"`
+	Genres           *[]string `json:"genres,omitempty"`
+	RuntimeMinutes   *int      `json:"runtime_minutes,omitempty"`
+	Directors        *[]string `json:"directors,omitempty"`
+	Cast             *[]string `json:"cast,omitempty"`
+	Synopsis         *string   `json:"synopsis,omitempty"`
+	OriginalLanguage *string   `json:"original_language,omitempty"`
+	PosterURL        *string   `json:"poster_url,omitempty"`
+}
+
+// Fields devolve os valores do patch e a máscara (nomes JSON) dos campos presentes.
+func (p MoviePatch) Fields() (Movie, []string) {
+	var m Movie
+	var fields []string
+	set := func(present bool, name string, apply func()) {
+		if present {
+			apply()
+			fields = append(fields, name)
+		}
+	}
+	set(p.Title != nil, "title", func() { m.Title = *p.Title })
+	set(p.Year != nil, "year", func() { m.Year = *p.Year })
+	set(p.Genres != nil, "genres", func() { m.Genres = *p.Genres })
+	set(p.RuntimeMinutes != nil, "runtime_minutes", func() { m.RuntimeMinutes = *p.RuntimeMinutes })
+	set(p.Directors != nil, "directors", func() { m.Directors = *p.Directors })
+	set(p.Cast != nil, "cast", func() { m.Cast = *p.Cast })
+	set(p.Synopsis != nil, "synopsis", func() { m.Synopsis = *p.Synopsis })
+	set(p.OriginalLanguage != nil, "original_language", func() { m.OriginalLanguage = *p.OriginalLanguage })
+	set(p.PosterURL != nil, "poster_url", func() { m.PosterURL = *p.PosterURL })
+	return m, fields
+}
+
+// ListParams filtros, ordenação e paginação do List (repassados ao gRPC).
+type ListParams struct {
+	Limit       int
+	Cursor      string
+	MinYear     int
+	MaxYear     int
+	TitlePrefix string
+	SortBy      string // id | title | year
+	SortOrder   string // asc | desc
+}
+
+// MoviePage página do List; NextCursor vazio indica que não há mais itens.
+type MoviePage struct {
+	Movies     []Movie
+	NextCursor string
+}
+
+// SearchParams busca textual (repassada ao gRPC SearchMovies).
+type SearchParams struct {
+	Query   string
+	Limit   int
+	Cursor  string
+	MinYear int
+	MaxYear int
+}
+
+// SearchResult filme encontrado e sua relevância para a query.
+type SearchResult struct {
+	Movie
+	Score float64 `json:"score" example:"12.5"` // maior = mais relevante; só comparável na mesma busca
+}
+
+// SearchPage página da busca, por relevância; NextCursor vazio indica o fim.
+type SearchPage struct {
+	Results    []SearchResult
+	NextCursor string
+}
+
+// TitleSuggestion item do autocomplete (GET /movies/suggest).
+type TitleSuggestion struct {
+	ID    string `json:"id" example:"370"`
+	Title string `json:"title" example:"The Great Train Robbery"`
+	Year  int    `json:"year" example:"1903"`
+}
+
 func (m *Movie) Normalize() {
 	m.Title = strings.TrimSpace(m.Title)
 }
 
+// ValidationError falha de validação local de um campo (nome JSON).
+// errors.Is(err, ErrValidation) continua valendo.
+type ValidationError struct {
+	Field   string
+	Reason  string // mesmo vocabulário do serviço movies: REQUIRED, RANGE...
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+func (e *ValidationError) Is(target error) bool { return target == ErrValidation }
+
+// Validate checa localmente só os campos obrigatórios; os opcionais são
+// validados pelo serviço movies (erros voltam com violações por campo).
 func (m *Movie) Validate() error {
 	if strings.TrimSpace(m.Title) == "" {
-		return errors.Join(ErrValidation, errors.New("title is required"))
+		return &ValidationError{Field: "title", Reason: "REQUIRED", Message: "title is required"}
 	}
 	if m.Year < 1878 || m.Year > 3000 {
-		return errors.Join(ErrValidation, errors.New("year is out of range"))
+		return &ValidationError{Field: "year", Reason: "RANGE", Message: "year is out of range"}
 	}
 	return nil
 }
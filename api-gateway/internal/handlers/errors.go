@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const problemContentType = "application/problem+json"
+
+// Tipos de problema (campo "type" da RFC 7807), relativos à raiz da API.
+const (
+	problemInvalidRequest = "/problems/invalid-request"
+	problemValidation     = "/problems/validation-error"
+	problemNotFound       = "/problems/not-found"
+	problemConflict       = "/problems/conflict"
+	problemConflictTrash  = "/problems/conflict-trashed"
+	problemPrecondition   = "/problems/precondition-failed"
+	problemNeedsIfMatch   = "/problems/precondition-required"
+	problemInvalidState   = "/problems/invalid-state"
+	problemNotImplemented = "/problems/not-implemented"
+	problemUnavailable    = "/problems/service-unavailable"
+	problemTimeout        = "/problems/upstream-timeout"
+	problemUpstream       = "/problems/upstream-error"
+)
+
+// problemTitles resumo fixo de cada tipo (o detalhe vai em "detail").
+var problemTitles = map[string]string{
+	problemInvalidRequest: "Invalid request",
+	problemValidation:     "Validation failed",
+	problemNotFound:       "Movie not found",
+	problemConflict:       "Movie already exists",
+	problemConflictTrash:  "Movie already exists in the trash",
+	problemPrecondition:   "Movie was modified",
+	problemNeedsIfMatch:   "If-Match required",
+	problemInvalidState:   "Operation not allowed in the movie's current state",
+	problemNotImplemented: "Operation not supported by the movies service",
+	problemUnavailable:    "Movies service unavailable",
+	problemTimeout:        "Movies service timeout",
+	problemUpstream:       "Upstream error",
+}
+
+// Problem corpo application/problem+json (RFC 7807) de toda resposta de
+// erro do gateway.
+type Problem struct {
+	Type      string       `json:"type" example:"/problems/validation-error"`
+	Title     string       `json:"title" example:"Validation failed"`
+	Status    int          `json:"status" example:"400"`
+	Detail    string       `json:"detail,omitempty" example:"year must be between 1800 and 3000"`
+	Instance  string       `json:"instance,omitempty" example:"/movies"`
+	RequestID string       `json:"request_id,omitempty" example:"4f6c2a9e0d1b7c3a"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError violação de um campo: validação local, erro de tipo no corpo
+// ou errdetails.BadRequest do serviço movies.
+type FieldError struct {
+	Field   string `json:"field" example:"year"`
+	Reason  string `json:"reason,omitempty" example:"RANGE"`
+	Message string `json:"message" example:"year must be between 1800 and 3000"`
+}
+
+// newProblem monta um Problem do tipo typ; status e title vêm do tipo.
+func newProblem(typ string, status int, detail string, fields ...FieldError) Problem {
+	return Problem{Type: typ, Title: problemTitles[typ], Status: status, Detail: detail, Errors: fields}
+}
+
+// grpcProblems traduz os códigos gRPC do serviço movies. Para erros do
+// cliente (4xx) a mensagem do serviço vira o detail; para falhas de
+// infraestrutura o detail é omitido, sem expor detalhes internos.
+var grpcProblems = map[codes.Code]Problem{
+	codes.InvalidArgument:    newProblem(problemValidation, http.StatusBadRequest, ""),
+	codes.NotFound:           newProblem(problemNotFound, http.StatusNotFound, ""),
+	codes.AlreadyExists:      newProblem(problemConflict, http.StatusConflict, ""),
+	codes.Aborted:            newProblem(problemPrecondition, http.StatusPreconditionFailed, ""),
+	codes.FailedPrecondition: newProblem(problemInvalidState, http.StatusBadRequest, ""), // 400 como no gRPC→HTTP padrão; 412 é só do If-Match
+	codes.Unimplemented:      newProblem(problemNotImplemented, http.StatusNotImplemented, ""),
+	codes.Unavailable:        newProblem(problemUnavailable, http.StatusServiceUnavailable, ""),
+	codes.DeadlineExceeded:   newProblem(problemTimeout, http.StatusGatewayTimeout, ""),
+}
+
+// toProblem é o ponto único de tradução erro → HTTP: erros de domínio do
+// gateway, status gRPC do serviço movies e, por fim, 502.
+func toProblem(err error) Problem {
+	var ve *domain.ValidationError
+	switch {
+	case errors.As(err, &ve):
+		return newProblem(problemValidation, http.StatusBadRequest, ve.Message,
+			FieldError{Field: ve.Field, Reason: ve.Reason, Message: ve.Message})
+	case errors.Is(err, domain.ErrValidation), errors.Is(err, domain.ErrInvalidID):
+		return newProblem(problemInvalidRequest, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		return newProblem(problemNotFound, http.StatusNotFound, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return grpcProblems[codes.DeadlineExceeded]
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return newProblem(problemUpstream, http.StatusBadGateway, "")
+	}
+	p, known := grpcProblems[st.Code()]
+	if !known {
+		return newProblem(problemUpstream, http.StatusBadGateway, "")
+	}
+	if p.Status < http.StatusInternalServerError {
+		p.Detail = st.Message()
+		p.Errors = fieldErrors(st)
+	}
+	if id, ok := trashedConflict(st); ok {
+		p.Type, p.Title = problemConflictTrash, problemTitles[problemConflictTrash]
+		p.Detail += "; restore it with POST /movies/" + url.PathEscape(id) + "/restore"
+	}
+	return p
+}
+
+// trashedConflict devolve o id do filme que ocupa título/ano (ou id
+// externo) quando o conflito é com um filme na lixeira: restaurá-lo é o
+// caminho, não criar de novo.
+func trashedConflict(st *status.Status) (string, bool) {
+	if st.Code() != codes.AlreadyExists {
+		return "", false
+	}
+	for _, d := range st.Details() {
+		ei, ok := d.(*errdetails.ErrorInfo)
+		if ok && ei.GetMetadata()["trashed"] == "true" && ei.GetMetadata()["conflicting_id"] != "" {
+			return ei.GetMetadata()["conflicting_id"], true
+		}
+	}
+	return "", false
+}
+
+// fieldErrors extrai as violações de campo anexadas ao status.
+func fieldErrors(st *status.Status) []FieldError {
+	var out []FieldError
+	for _, d := range st.Details() {
+		br, ok := d.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, v := range br.GetFieldViolations() {
+			out = append(out, FieldError{Field: v.GetField(), Reason: v.GetReason(), Message: v.GetDescription()})
+		}
+	}
+	return out
+}
+
+// bindProblem descreve a falha do ShouldBindJSON; erro de tipo aponta o campo.
+func bindProblem(err error) Problem {
+	var syn *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		return newProblem(problemInvalidRequest, http.StatusBadRequest, "request body is empty")
+	case errors.As(err, &syn):
+		return newProblem(problemInvalidRequest, http.StatusBadRequest, fmt.Sprintf("malformed JSON at offset %d", syn.Offset))
+	case errors.As(err, &typ):
+		msg := fmt.Sprintf("must be a JSON %s", typ.Type.Kind())
+		return newProblem(problemValidation, http.StatusBadRequest, "invalid value for "+typ.Field,
+			FieldError{Field: typ.Field, Reason: "TYPE", Message: msg})
+	default:
+		return newProblem(problemInvalidRequest, http.StatusBadRequest, "invalid body")
+	}
+}
+
+// writeProblem responde p como application/problem+json, completando
+// instance e request id.
+func writeProblem(c *gin.Context, p Problem) {
+	p.Instance = c.Request.URL.Path
+	p.RequestID = requestID(c)
+	body, err := json.Marshal(p)
+	if err != nil {
+		c.AbortWithStatus(p.Status)
+		return
+	}
+	c.Abort()
+	c.Data(p.Status, problemContentType, body)
+}
+
+// writeError responde err como Problem (ver toProblem).
+func writeError(c *gin.Context, err error) {
+	p := toProblem(err)
+	if p.Status >= http.StatusInternalServerError {
+		_ = c.Error(err) // detalhe original só no log
+	}
+	writeProblem(c, p)
+}
+
+// badRequest responde 400 (invalid-request) com detail.
+func badRequest(c *gin.Context, detail string) {
+	writeProblem(c, newProblem(problemInvalidRequest, http.StatusBadRequest, detail))
+}
+
+// badBody responde a falha de bind do corpo JSON.
+func badBody(c *gin.Context, err error) {
+	writeProblem(c, bindProblem(err))
+}
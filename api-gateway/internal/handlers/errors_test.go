@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gdomain "github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToProblem(t *testing.T) {
+	cases := []struct {
+		err        error
+		wantType   string
+		wantStatus int
+		wantDetail string
+	}{
+		{&gdomain.ValidationError{Field: "title", Reason: "REQUIRED", Message: "title is required"}, problemValidation, http.StatusBadRequest, "title is required"},
+		{gdomain.ErrInvalidID, problemInvalidRequest, http.StatusBadRequest, "invalid id"},
+		{fmt.Errorf("get: %w", gdomain.ErrNotFound), problemNotFound, http.StatusNotFound, "get: movie not found"},
+		{status.Error(codes.InvalidArgument, "invalid sort field"), problemValidation, http.StatusBadRequest, "invalid sort field"},
+		{status.Error(codes.NotFound, "movie not found"), problemNotFound, http.StatusNotFound, "movie not found"},
+		{status.Error(codes.AlreadyExists, "movie already exists"), problemConflict, http.StatusConflict, "movie already exists"},
+		{status.Error(codes.FailedPrecondition, "movie is in the trash"), problemInvalidState, http.StatusBadRequest, "movie is in the trash"},
+		{status.Error(codes.Unimplemented, "unknown method Suggest"), problemNotImplemented, http.StatusNotImplemented, ""},
+		{status.Error(codes.Unavailable, "connection refused 10.0.0.3"), problemUnavailable, http.StatusServiceUnavailable, ""},
+		{status.Error(codes.DeadlineExceeded, "deadline"), problemTimeout, http.StatusGatewayTimeout, ""},
+		{context.DeadlineExceeded, problemTimeout, http.StatusGatewayTimeout, ""},
+		{status.Error(codes.Internal, "mongo: boom"), problemUpstream, http.StatusBadGateway, ""},
+		{errors.New("boom"), problemUpstream, http.StatusBadGateway, ""},
+	}
+	for _, tc := range cases {
+		got := toProblem(tc.err)
+		require.Equal(t, tc.wantType, got.Type, tc.err.Error())
+		require.Equal(t, tc.wantStatus, got.Status, tc.err.Error())
+		require.Equal(t, tc.wantDetail, got.Detail, tc.err.Error())
+		require.NotEmpty(t, got.Title, tc.err.Error())
+	}
+}
+
+func TestProblem_ConflictWithTrashedMovie(t *testing.T) {
+	st, err := status.New(codes.AlreadyExists, "movie already exists (title+year): id 7 (in trash)").WithDetails(
+		&errdetails.ErrorInfo{Reason: "MOVIE_ALREADY_EXISTS", Metadata: map[string]string{"conflicting_id": "7", "trashed": "true"}},
+	)
+	require.NoError(t, err)
+	p := toProblem(st.Err())
+	require.Equal(t, problemConflictTrash, p.Type)
+	require.Equal(t, http.StatusConflict, p.Status)
+	require.Equal(t, "movie already exists (title+year): id 7 (in trash); restore it with POST /movies/7/restore", p.Detail)
+
+	// conflito com filme no catálogo continua /problems/conflict
+	st, err = status.New(codes.AlreadyExists, "movie already exists (title+year): id 8").WithDetails(
+		&errdetails.ErrorInfo{Reason: "MOVIE_ALREADY_EXISTS", Metadata: map[string]string{"conflicting_id": "8"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, problemConflict, toProblem(st.Err()).Type)
+}
+
+func TestProblem_FieldViolationsFromStatus(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "validation error: year: year must be between 1800 and 3000").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "year", Reason: "RANGE", Description: "year must be between 1800 and 3000"},
+		},
+	})
+	require.NoError(t, err)
+
+	r := setupRouter(&fakeSvc{err: st.Err()})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/movies", strings.NewReader(`{"title":"X","year":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(RequestIDHeader, "req-1")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+	require.Equal(t, "req-1", w.Header().Get(RequestIDHeader))
+	require.JSONEq(t, `{
+		"type": "/problems/validation-error",
+		"title": "Validation failed",
+		"status": 400,
+		"detail": "validation error: year: year must be between 1800 and 3000",
+		"instance": "/movies",
+		"request_id": "req-1",
+		"errors": [{"field": "year", "reason": "RANGE", "message": "year must be between 1800 and 3000"}]
+	}`, w.Body.String())
+}
+
+func TestHandlers_ProblemResponses(t *testing.T) {
+	cases := []struct {
+		method, path, body string
+		err                error
+		wantStatus         int
+		wantType           string
+		wantErrors         []FieldError
+	}{
+		{"POST", "/movies", `{"title":"","year":2000}`, &gdomain.ValidationError{Field: "title", Reason: "REQUIRED", Message: "title is required"},
+			http.StatusBadRequest, problemValidation, []FieldError{{Field: "title", Reason: "REQUIRED", Message: "title is required"}}},
+		{"POST", "/movies", `{"title":"X","year":2000}`, status.Error(codes.AlreadyExists, "movie already exists"),
+			http.StatusConflict, problemConflict, nil},
+		{"DELETE", "/movies/8", "", status.Error(codes.NotFound, "movie not found"), http.StatusNotFound, problemNotFound, nil},
+		{"GET", "/movies/8", "", status.Error(codes.NotFound, "movie not found"), http.StatusNotFound, problemNotFound, nil},
+		{"GET", "/movies", "", status.Error(codes.Unavailable, "dial tcp"), http.StatusServiceUnavailable, problemUnavailable, nil},
+		{"GET", "/movies?min_year=abc", "", nil, http.StatusBadRequest, problemValidation,
+			[]FieldError{{Field: "min_year", Reason: "TYPE", Message: "must be an integer"}}},
+		{"PATCH", "/movies/8", `{}`, nil, http.StatusBadRequest, problemInvalidRequest, nil},
+		// falhas do ShouldBindJSON
+		{"POST", "/movies", ``, nil, http.StatusBadRequest, problemInvalidRequest, nil},
+		{"POST", "/movies", `{`, nil, http.StatusBadRequest, problemInvalidRequest, nil},
+		{"PUT", "/movies/8", `{"title":"X","year":"2000"}`, nil, http.StatusBadRequest, problemValidation,
+			[]FieldError{{Field: "year", Reason: "TYPE", Message: "must be a JSON int"}}},
+	}
+	for _, tc := range cases {
+		name := tc.method + " " + tc.path + " " + tc.body
+		r := setupRouter(&fakeSvc{err: tc.err})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1-0123abcd"`) // só as escritas leem
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, tc.wantStatus, w.Code, name)
+		require.Equal(t, problemContentType, w.Header().Get("Content-Type"), name)
+
+		var p Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p), name)
+		require.Equal(t, tc.wantType, p.Type, name)
+		require.Equal(t, tc.wantStatus, p.Status, name)
+		require.Equal(t, strings.SplitN(tc.path, "?", 2)[0], p.Instance, name)
+		require.NotEmpty(t, p.RequestID, name)
+		require.Equal(t, p.RequestID, w.Header().Get(RequestIDHeader), name)
+		require.Equal(t, tc.wantErrors, p.Errors, name)
+	}
+}
+
+func TestProblem_HidesUpstreamDetail(t *testing.T) {
+	r := setupRouter(&fakeSvc{err: status.Error(codes.Internal, "mongo: connection reset by 10.0.0.3")})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies/8", nil)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadGateway, w.Code)
+	require.NotContains(t, w.Body.String(), "10.0.0.3")
+}
+
+func TestRequestID_RejectsUnsafeValues(t *testing.T) {
+	r := setupRouter(&fakeSvc{err: gdomain.ErrNotFound})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies/8", nil)
+	req.Header.Set(RequestIDHeader, "has space")
+	r.ServeHTTP(w, req)
+
+	got := w.Header().Get(RequestIDHeader)
+	require.NotEmpty(t, got)
+	require.NotEqual(t, "has space", got)
+}
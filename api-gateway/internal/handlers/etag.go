@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCacheControl deixa guardar a resposta, mas obriga a revalidar
+// (If-None-Match) antes de usá-la: sem dado velho e, sem mudança, só um 304.
+const DefaultCacheControl = "no-cache"
+
+// CacheControl valores do header Cache-Control das leituras; campo vazio
+// usa DefaultCacheControl.
+type CacheControl struct {
+	Movie string // GET /movies/{id} e /movies/trash/{id}
+	List  string // GET /movies
+}
+
+func (cc CacheControl) withDefaults() CacheControl {
+	if cc.Movie == "" {
+		cc.Movie = DefaultCacheControl
+	}
+	if cc.List == "" {
+		cc.List = DefaultCacheControl
+	}
+	return cc
+}
+
+// writeCached responde v como JSON com ETag forte (prefix seguido do hash
+// do corpo e de extra, que entra nos headers da resposta) e o
+// Cache-Control dado. Se o If-None-Match da requisição casa com o ETag,
+// responde 304 sem corpo.
+func writeCached(c *gin.Context, cacheControl, prefix string, v any, extra ...string) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	etag := `"` + prefix + contentHash(body, extra...) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", cacheControl)
+	if etagMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// writeMovie responde m (resultado de uma escrita) com o ETag da nova
+// versão, pronto para o If-Match da próxima alteração.
+func writeMovie(c *gin.Context, status int, m *domain.Movie) {
+	body, err := json.Marshal(m)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Header("ETag", `"`+versionPrefix(m)+contentHash(body)+`"`)
+	c.Data(status, "application/json; charset=utf-8", body)
+}
+
+// versionPrefix início do ETag de um filme: "<versão>-<hash do corpo>". O
+// If-Match só precisa da versão; o hash muda o ETag também quando a
+// representação muda sem nova versão.
+func versionPrefix(m *domain.Movie) string {
+	return strconv.FormatInt(m.Version, 10) + "-"
+}
+
+func contentHash(body []byte, extra ...string) string {
+	h := sha256.New()
+	h.Write(body)
+	for _, s := range extra {
+		h.Write([]byte{0})
+		h.Write([]byte(s))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// etagMatch compara o If-None-Match com etag (comparação fraca, RFC 9110
+// 13.1.2): "*" casa com qualquer um e W/ é ignorado.
+func etagMatch(ifNoneMatch, etag string) bool {
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion traduz o If-Match de uma escrita na versão esperada.
+// Toda alteração exige o ETag do filme lido: ausente ou "*" responde 428
+// (RFC 6585), para que nenhum cliente sobrescreva sem querer a edição de
+// outro. Aceita um único ETag forte de filme; ETag fraco ou que não é de
+// filme nunca casa (412, RFC 9110 13.1.1) e lista responde 400. Em erro,
+// responde e devolve false.
+func ifMatchVersion(c *gin.Context) (int64, bool) {
+	h := strings.TrimSpace(c.GetHeader("If-Match"))
+	if h == "" || h == "*" {
+		writeProblem(c, newProblem(problemNeedsIfMatch, http.StatusPreconditionRequired,
+			"send the ETag of the movie you read in If-Match"))
+		return 0, false
+	}
+	if strings.Contains(h, ",") {
+		badRequest(c, "If-Match accepts a single ETag")
+		return 0, false
+	}
+	tag, ok := strings.CutPrefix(h, `"`)
+	if ok {
+		tag, ok = strings.CutSuffix(tag, `"`)
+	}
+	if ok {
+		var s string
+		s, _, ok = strings.Cut(tag, "-")
+		if v, err := strconv.ParseInt(s, 10, 64); ok && err == nil && v > 0 {
+			return v, true
+		}
+	}
+	writeProblem(c, newProblem(problemPrecondition, http.StatusPreconditionFailed,
+		"If-Match does not match the current movie ETag"))
+	return 0, false
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gdomain "github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func get(r http.Handler, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetHandler_ETag(t *testing.T) {
+	svc := &fakeSvc{get: &gdomain.Movie{ID: "1", Title: "Metropolis", Year: 1927, Version: 3}}
+	r := setupRouter(svc)
+
+	w := get(r, "/movies/1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.Regexp(t, `^"3-[0-9a-f]{32}"$`, etag)
+	require.Equal(t, DefaultCacheControl, w.Header().Get("Cache-Control"))
+
+	for _, inm := range []string{etag, `"x", ` + etag, "W/" + etag, "*"} {
+		w = get(r, "/movies/1", inm)
+		require.Equal(t, http.StatusNotModified, w.Code, inm)
+		require.Empty(t, w.Body.String(), inm)
+		require.Equal(t, etag, w.Header().Get("ETag"), inm)
+		require.Equal(t, DefaultCacheControl, w.Header().Get("Cache-Control"), inm)
+	}
+
+	// filme alterado: ETag muda e o corpo volta
+	svc.get = &gdomain.Movie{ID: "1", Title: "Metropolis", Year: 1926, Version: 4}
+	w = get(r, "/movies/1", etag)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotEqual(t, etag, w.Header().Get("ETag"))
+	require.JSONEq(t, `{"id":"1","title":"Metropolis","year":1926,"version":4}`, w.Body.String())
+}
+
+func TestListHandler_ETag(t *testing.T) {
+	svc := &fakeSvc{list: []gdomain.Movie{{ID: "8", Title: "X", Year: 2000}}, next: "abc"}
+	r := setupRouter(svc)
+
+	w := get(r, "/movies", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	w = get(r, "/movies", etag)
+	require.Equal(t, http.StatusNotModified, w.Code)
+	require.Equal(t, "abc", w.Header().Get(nextCursorHeader))
+
+	// mesma página, outro cursor: ETag diferente
+	svc.next = "def"
+	w = get(r, "/movies", etag)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotEqual(t, etag, w.Header().Get("ETag"))
+}
+
+func TestCacheControl_Configurable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterMovieRoutes(r, &fakeSvc{get: &gdomain.Movie{ID: "1"}}, CacheControl{
+		Movie: "public, max-age=60",
+		List:  "private, max-age=5",
+	})
+
+	require.Equal(t, "public, max-age=60", get(r, "/movies/1", "").Header().Get("Cache-Control"))
+	require.Equal(t, "private, max-age=5", get(r, "/movies", "").Header().Get("Cache-Control"))
+}
+
+func TestGetHandler_ErrorsNotCached(t *testing.T) {
+	r := setupRouter(&fakeSvc{err: gdomain.ErrNotFound})
+	w := get(r, "/movies/1", "*")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Empty(t, w.Header().Get("ETag"))
+	require.Empty(t, w.Header().Get("Cache-Control"))
+}
+
+func TestWriteHandlers_IfMatch(t *testing.T) {
+	cases := []struct {
+		ifMatch     string
+		wantStatus  int
+		wantVersion int64
+	}{
+		{"", http.StatusPreconditionRequired, 0},
+		{"*", http.StatusPreconditionRequired, 0},
+		{`"3-0123abcd"`, http.StatusOK, 3},
+		{`W/"3-0123abcd"`, http.StatusPreconditionFailed, 0},
+		{`"0123abcd"`, http.StatusPreconditionFailed, 0},
+		{`"0-0123abcd"`, http.StatusPreconditionFailed, 0},
+		{`"3-a", "4-b"`, http.StatusBadRequest, 0},
+	}
+	for _, method := range []string{"PUT", "PATCH", "DELETE"} {
+		for _, tc := range cases {
+			svc := &fakeSvc{gotVersion: -1}
+			r := setupRouter(svc)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, "/movies/8", strings.NewReader(`{"title":"Sneeze","year":1894}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			r.ServeHTTP(w, req)
+
+			name := method + " " + tc.ifMatch
+			if tc.wantStatus != http.StatusOK {
+				require.Equal(t, tc.wantStatus, w.Code, name)
+				require.Equal(t, problemContentType, w.Header().Get("Content-Type"), name)
+				require.Equal(t, int64(-1), svc.gotVersion, name) // nem chegou ao serviço
+				if tc.wantStatus == http.StatusPreconditionRequired {
+					require.Contains(t, w.Body.String(), problemNeedsIfMatch, name)
+				}
+				continue
+			}
+			require.Equal(t, tc.wantVersion, svc.gotVersion, name)
+			require.Equal(t, http.StatusOK, w.Code, name)
+			require.Regexp(t, fmt.Sprintf(`^"%d-[0-9a-f]{32}"$`, tc.wantVersion+1), w.Header().Get("ETag"), name)
+		}
+	}
+}
+
+func TestWriteHandlers_VersionMismatch(t *testing.T) {
+	r := setupRouter(&fakeSvc{err: status.Error(codes.Aborted, "movie version mismatch")})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/movies/8", nil)
+	req.Header.Set("If-Match", `"3-0123abcd"`)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusPreconditionFailed, w.Code)
+	var p Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.Equal(t, problemPrecondition, p.Type)
+}
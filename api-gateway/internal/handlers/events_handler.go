@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Parâmetros do stream SSE (variáveis para os testes ajustarem).
+var (
+	sseHeartbeat    = 15 * time.Second
+	sseRetry        = 3 * time.Second
+	sseWriteTimeout = 10 * time.Second
+	sseBuffer       = 64
+)
+
+var errSlowConsumer = errors.New("sse: slow consumer")
+
+// Events godoc
+// @Summary Stream de mudanças no catálogo (SSE)
+// @Description Server-Sent Events com criação, alteração e remoção de filmes: `event` é created, updated ou deleted e `data` é o filme (JSON). Ir para a lixeira é deleted (com deleted_at), restaurar é created e a remoção definitiva pela limpeza é deleted de novo. Para retomar sem perder eventos envie Last-Event-ID (ou ?last_event_id=) com o id do último evento recebido; se ele não puder mais ser retomado chega um `event: reset` e o stream fecha (recarregue o estado e reconecte sem id). Comentários `: ping` são enviados periodicamente. Clientes que não acompanham o ritmo são desconectados e podem retomar com Last-Event-ID.
+// @Tags movies
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "id do último evento recebido"
+// @Param last_event_id query string false "alternativa ao header Last-Event-ID"
+// @Success 200 {string} string "stream text/event-stream"
+// @Router /movies/events [get]
+func (h *MovieHandler) Events(c *gin.Context) {
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("last_event_id")
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// o Watch nunca bloqueia no cliente HTTP: buffer cheio = cliente lento
+	changes := make(chan domain.MovieChange, sseBuffer)
+	done := make(chan error, 1)
+	go func() {
+		done <- h.svc.Watch(ctx, lastID, func(ch domain.MovieChange) error {
+			select {
+			case changes <- ch:
+				return nil
+			default:
+				return errSlowConsumer
+			}
+		})
+	}()
+
+	hdr := c.Writer.Header()
+	hdr.Set("Content-Type", "text/event-stream")
+	hdr.Set("Cache-Control", "no-cache")
+	hdr.Set("Connection", "keep-alive")
+	hdr.Set("X-Accel-Buffering", "no") // sem buffer em proxies (nginx)
+	c.Status(http.StatusOK)
+
+	sse := &sseWriter{c: c, rc: http.NewResponseController(c.Writer)}
+	if sse.write(fmt.Sprintf("retry: %d\n\n", sseRetry.Milliseconds())) != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case ch := <-changes:
+			err = sse.change(ch)
+		case <-heartbeat.C:
+			err = sse.write(": ping\n\n")
+		case werr := <-done:
+			// entrega o que já chegou antes de encerrar
+			for len(changes) > 0 && err == nil {
+				err = sse.change(<-changes)
+			}
+			if err == nil {
+				sse.end(werr)
+			}
+			return
+		}
+		if err != nil {
+			return // cliente foi embora ou travou (write deadline)
+		}
+	}
+}
+
+type sseWriter struct {
+	c  *gin.Context
+	rc *http.ResponseController
+}
+
+func (w *sseWriter) write(s string) error {
+	// nem todo ResponseWriter suporta deadline (ex.: httptest)
+	_ = w.rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+	if _, err := w.c.Writer.WriteString(s); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+func (w *sseWriter) change(ch domain.MovieChange) error {
+	data, err := json.Marshal(ch.Movie)
+	if err != nil {
+		return err
+	}
+	return w.write(fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", ch.ID, ch.Type, data))
+}
+
+// end encerra o stream conforme o motivo do fim do Watch. Cliente lento e
+// fim normal só fecham (o EventSource reconecta com Last-Event-ID). Token
+// inválido vira "reset" com id vazio, que limpa o Last-Event-ID do
+// navegador para a reconexão seguinte começar do zero.
+func (w *sseWriter) end(err error) {
+	switch {
+	case err == nil, errors.Is(err, errSlowConsumer):
+	case status.Code(err) == codes.InvalidArgument:
+		_ = w.write(fmt.Sprintf("event: reset\nid\ndata: %s\n\n", w.problem(err)))
+	default:
+		_ = w.write(fmt.Sprintf("event: error\ndata: %s\n\n", w.problem(err)))
+	}
+}
+
+// problem serializa err como Problem (mesmo corpo das respostas de erro).
+func (w *sseWriter) problem(err error) []byte {
+	p := toProblem(err)
+	p.Instance = w.c.Request.URL.Path
+	p.RequestID = requestID(w.c)
+	b, _ := json.Marshal(p)
+	return b
+}
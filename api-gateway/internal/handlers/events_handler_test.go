@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gdomain "github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEventsHandler_StreamsChanges(t *testing.T) {
+	svc := &fakeSvc{changes: []gdomain.MovieChange{
+		{ID: "1", Type: gdomain.ChangeCreated, Movie: gdomain.Movie{ID: "a", Title: "A", Year: 2001}},
+		{ID: "2", Type: gdomain.ChangeDeleted, Movie: gdomain.Movie{ID: "a", Title: "A", Year: 2001}},
+	}}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies/events", nil)
+	req.Header.Set("Last-Event-ID", "7")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	require.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	require.Equal(t, "7", svc.gotLastID)
+	require.Equal(t, "retry: 3000\n\n"+
+		"id: 1\nevent: created\ndata: {\"id\":\"a\",\"title\":\"A\",\"year\":2001}\n\n"+
+		"id: 2\nevent: deleted\ndata: {\"id\":\"a\",\"title\":\"A\",\"year\":2001}\n\n",
+		w.Body.String())
+}
+
+func TestEventsHandler_LastEventIDQuery(t *testing.T) {
+	svc := &fakeSvc{}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies/events?last_event_id=42", nil)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, "42", svc.gotLastID)
+}
+
+func TestEventsHandler_ResetOnInvalidToken(t *testing.T) {
+	svc := &fakeSvc{watchErr: status.Error(codes.InvalidArgument, "resume token expired")}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies/events", nil)
+	req.Header.Set("Last-Event-ID", "old")
+	r.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	require.Contains(t, body, "event: reset\nid\ndata: {")
+	require.Contains(t, body, `"detail":"resume token expired"`)
+}
+
+func TestEventsHandler_ErrorEvent(t *testing.T) {
+	svc := &fakeSvc{watchErr: status.Error(codes.Unavailable, "down")}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies/events", nil)
+	r.ServeHTTP(w, req)
+
+	require.Contains(t, w.Body.String(), "event: error\n")
+	require.NotContains(t, w.Body.String(), "down")
+}
+
+func TestEventsHandler_SlowConsumerDisconnected(t *testing.T) {
+	defer func(n int) { sseBuffer = n }(sseBuffer)
+	sseBuffer = 1
+
+	changes := make([]gdomain.MovieChange, 10)
+	for i := range changes {
+		changes[i] = gdomain.MovieChange{ID: "x", Type: gdomain.ChangeUpdated}
+	}
+	svc := &fakeSvc{changes: changes, block: true}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies/events", nil)
+	done := make(chan struct{})
+	go func() { r.ServeHTTP(w, req); close(done) }()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("slow consumer was not disconnected")
+	}
+	require.NotContains(t, w.Body.String(), "event: error")
+}
+
+func TestEventsHandler_Heartbeat(t *testing.T) {
+	defer func(d time.Duration) { sseHeartbeat = d }(sseHeartbeat)
+	sseHeartbeat = 10 * time.Millisecond
+
+	r := setupRouter(&fakeSvc{block: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/movies/events", nil)
+	r.ServeHTTP(w, req)
+
+	require.True(t, strings.Contains(w.Body.String(), ": ping\n\n"), w.Body.String())
+}
@@ -3,40 +3,213 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
 	"github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
 
+// Header com o cursor da próxima página do List.
+const nextCursorHeader = "X-Next-Cursor"
+
 type MovieHandler struct {
-	svc usecase.MovieService
+	svc   usecase.MovieService
+	cache CacheControl
 }
 
-func RegisterMovieRoutes(r *gin.Engine, svc usecase.MovieService) {
-	h := &MovieHandler{svc: svc}
+func RegisterMovieRoutes(r *gin.Engine, svc usecase.MovieService, cache CacheControl) {
+	h := &MovieHandler{svc: svc, cache: cache.withDefaults()}
 	g := r.Group("/movies")
 	g.GET("", h.List)
+	g.GET("/events", h.Events)
+	g.GET("/search", h.Search)
+	g.GET("/suggest", h.Suggest)
+	g.GET("/trash", h.Trash)
+	g.GET("/trash/:id", h.GetTrashed)
 	g.GET("/:id", h.Get)
 	g.POST("", h.Create)
+	g.PUT("/:id", h.Replace)
+	g.PATCH("/:id", h.Patch)
 	g.DELETE("/:id", h.Delete)
+	g.POST("/:id/restore", h.Restore)
 }
 
 // List godoc
-// @Summary Lista todos os filmes
+// @Summary Lista filmes (paginação por cursor)
+// @Description A próxima página é indicada no header X-Next-Cursor (ausente na última página).
 // @Tags movies
-// @Produce json
+// @Produce json,application/problem+json
 // @Param limit query int false "Máximo de itens retornados (default 50, max 200)"
+// @Param cursor query string false "Cursor opaco retornado em X-Next-Cursor"
+// @Param min_year query int false "Ano mínimo (inclusivo)"
+// @Param max_year query int false "Ano máximo (inclusivo)"
+// @Param title_prefix query string false "Prefixo do título (case-insensitive)"
+// @Param sort_by query string false "Campo de ordenação" Enums(id, title, year)
+// @Param sort_order query string false "Direção da ordenação" Enums(asc, desc)
+// @Param If-None-Match header string false "ETag de uma resposta anterior"
 // @Success 200 {array} domain.Movie
+// @Success 304 "página não mudou desde o ETag informado"
+// @Header 200,304 {string} X-Next-Cursor "Cursor da próxima página"
+// @Header 200,304 {string} ETag "Versão da página"
+// @Header 200,304 {string} Cache-Control "Política de cache (configurável)"
+// @Failure 400 {object} Problem "invalid query parameter"
 // @Router /movies [get]
 func (h *MovieHandler) List(c *gin.Context) {
-	movies, err := h.svc.List()
+	h.list(c, h.svc.List)
+}
+
+// Trash godoc
+// @Summary Lista a lixeira (filmes apagados)
+// @Description Mesmos filtros, ordenação e paginação de GET /movies; cada filme traz deleted_at. Filmes ficam na lixeira até serem restaurados ou removidos de vez pela limpeza periódica (TRASH_RETENTION).
+// @Tags movies
+// @Produce json,application/problem+json
+// @Param limit query int false "Máximo de itens retornados (default 50, max 200)"
+// @Param cursor query string false "Cursor opaco retornado em X-Next-Cursor"
+// @Param min_year query int false "Ano mínimo (inclusivo)"
+// @Param max_year query int false "Ano máximo (inclusivo)"
+// @Param title_prefix query string false "Prefixo do título (case-insensitive)"
+// @Param sort_by query string false "Campo de ordenação" Enums(id, title, year)
+// @Param sort_order query string false "Direção da ordenação" Enums(asc, desc)
+// @Param If-None-Match header string false "ETag de uma resposta anterior"
+// @Success 200 {array} domain.Movie
+// @Success 304 "página não mudou desde o ETag informado"
+// @Header 200,304 {string} X-Next-Cursor "Cursor da próxima página"
+// @Header 200,304 {string} ETag "Versão da página"
+// @Header 200,304 {string} Cache-Control "Política de cache (configurável)"
+// @Failure 400 {object} Problem "invalid query parameter"
+// @Router /movies/trash [get]
+func (h *MovieHandler) Trash(c *gin.Context) {
+	h.list(c, h.svc.ListDeleted)
+}
+
+// GetTrashed godoc
+// @Summary Busca um filme na lixeira
+// @Description O ETag da resposta é o If-Match do POST /movies/{id}/restore.
+// @Tags movies
+// @Produce json,application/problem+json
+// @Param id path string true "Movie ID"
+// @Param If-None-Match header string false "ETag de uma resposta anterior"
+// @Success 200 {object} domain.Movie
+// @Success 304 "filme não mudou desde o ETag informado"
+// @Header 200,304 {string} ETag "Versão do filme (para If-Match do restore)"
+// @Header 200,304 {string} Cache-Control "Política de cache (configurável)"
+// @Failure 400 {object} Problem "invalid id"
+// @Failure 404 {object} Problem "movie not in trash"
+// @Router /movies/trash/{id} [get]
+func (h *MovieHandler) GetTrashed(c *gin.Context) {
+	m, err := h.svc.GetDeleted(c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	writeCached(c, h.cache.Movie, versionPrefix(m), m)
+}
+
+// list responde uma página de List ou ListDeleted a partir da query.
+func (h *MovieHandler) list(c *gin.Context, fetch func(domain.ListParams) (domain.MoviePage, error)) {
+	p := domain.ListParams{
+		Limit:       queryLimit(c),
+		Cursor:      c.Query("cursor"),
+		TitlePrefix: c.Query("title_prefix"),
+		SortBy:      c.Query("sort_by"),
+		SortOrder:   c.Query("sort_order"),
+	}
+	if !queryYears(c, &p.MinYear, &p.MaxYear) {
+		return
+	}
+
+	page, err := fetch(p)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	if page.NextCursor != "" {
+		c.Header(nextCursorHeader, page.NextCursor)
+	}
+	movies := page.Movies
+	if movies == nil {
+		movies = []domain.Movie{}
+	}
+	writeCached(c, h.cache.List, "", movies, page.NextCursor)
+}
+
+// Search godoc
+// @Summary Busca textual de filmes, por relevância
+// @Description Procura os termos de q em título, título original, diretores, elenco, gêneros e sinopse, sem diferenciar maiúsculas nem acentos ("lumiere" encontra "Lumière"). Basta um dos termos casar; quem casa mais termos (principalmente no título) vem primeiro. "frase exata" e -termo também são aceitos. A próxima página é indicada no header X-Next-Cursor.
+// @Tags movies
+// @Produce json,application/problem+json
+// @Param q query string true "Termos da busca (até 200 caracteres)"
+// @Param limit query int false "Máximo de itens retornados (default 50, max 200)"
+// @Param cursor query string false "Cursor opaco retornado em X-Next-Cursor (só vale para a mesma busca)"
+// @Param min_year query int false "Ano mínimo (inclusivo)"
+// @Param max_year query int false "Ano máximo (inclusivo)"
+// @Success 200 {array} domain.SearchResult
+// @Header 200 {string} X-Next-Cursor "Cursor da próxima página"
+// @Failure 400 {object} Problem "missing q or invalid query parameter"
+// @Router /movies/search [get]
+func (h *MovieHandler) Search(c *gin.Context) {
+	p := domain.SearchParams{
+		Query:  strings.TrimSpace(c.Query("q")),
+		Limit:  queryLimit(c),
+		Cursor: c.Query("cursor"),
+	}
+	if p.Query == "" {
+		writeProblem(c, newProblem(problemValidation, http.StatusBadRequest, "q is required",
+			FieldError{Field: "q", Reason: "REQUIRED", Message: "q is required"}))
+		return
+	}
+	if !queryYears(c, &p.MinYear, &p.MaxYear) {
+		return
+	}
+
+	page, err := h.svc.Search(p)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
+	if page.NextCursor != "" {
+		c.Header(nextCursorHeader, page.NextCursor)
+	}
+	results := page.Results
+	if results == nil {
+		results = []domain.SearchResult{}
+	}
+	c.JSON(http.StatusOK, results)
+}
 
-	// limit (default=50, max=200)
+// Suggest godoc
+// @Summary Autocomplete de títulos
+// @Description Sugere filmes com alguma palavra do título começando por prefix, sem diferenciar maiúsculas nem acentos. Títulos que começam pelo prefixo vêm primeiro; depois, os mais recentes.
+// @Tags movies
+// @Produce json,application/problem+json
+// @Param prefix query string true "Início da palavra digitada (até 100 caracteres)"
+// @Param limit query int false "Máximo de sugestões (default 10, max 50)"
+// @Success 200 {array} domain.TitleSuggestion
+// @Failure 400 {object} Problem "missing prefix"
+// @Router /movies/suggest [get]
+func (h *MovieHandler) Suggest(c *gin.Context) {
+	prefix := strings.TrimSpace(c.Query("prefix"))
+	if prefix == "" {
+		writeProblem(c, newProblem(problemValidation, http.StatusBadRequest, "prefix is required",
+			FieldError{Field: "prefix", Reason: "REQUIRED", Message: "prefix is required"}))
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit")) // inválido/ausente = default do serviço
+
+	out, err := h.svc.Suggest(prefix, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	if out == nil {
+		out = []domain.TitleSuggestion{}
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// queryLimit lê ?limit (default 50, limitado a 1..200; inválido = default).
+func queryLimit(c *gin.Context) int {
 	limit := 50
 	if s := c.Query("limit"); s != "" {
 		if v, err := strconv.Atoi(s); err == nil {
@@ -49,71 +222,200 @@ func (h *MovieHandler) List(c *gin.Context) {
 			limit = v
 		}
 	}
-	if len(movies) > limit {
-		movies = movies[:limit]
-	}
+	return limit
+}
 
-	c.JSON(http.StatusOK, movies)
+// queryYears lê ?min_year e ?max_year; valor não numérico responde 400 e
+// devolve false.
+func queryYears(c *gin.Context, minYear, maxYear *int) bool {
+	for _, q := range []struct {
+		name string
+		dst  *int
+	}{{"min_year", minYear}, {"max_year", maxYear}} {
+		if s := c.Query(q.name); s != "" {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				writeProblem(c, newProblem(problemValidation, http.StatusBadRequest, "invalid "+q.name,
+					FieldError{Field: q.name, Reason: "TYPE", Message: "must be an integer"}))
+				return false
+			}
+			*q.dst = v
+		}
+	}
+	return true
 }
 
 // Get godoc
 // @Summary Busca um filme por ID
 // @Tags movies
-// @Produce json
+// @Produce json,application/problem+json
 // @Param id path string true "Movie ID"
+// @Param If-None-Match header string false "ETag de uma resposta anterior"
 // @Success 200 {object} domain.Movie
-// @Failure 404 {string} string "movie not found"
-// @Failure 400 {string} string "invalid id"
+// @Success 304 "filme não mudou desde o ETag informado"
+// @Header 200,304 {string} ETag "Versão do filme"
+// @Header 200,304 {string} Cache-Control "Política de cache (configurável)"
+// @Failure 404 {object} Problem "movie not found"
+// @Failure 400 {object} Problem "invalid id"
 // @Router /movies/{id} [get]
 func (h *MovieHandler) Get(c *gin.Context) {
 	id := c.Param("id")
 	m, err := h.svc.Get(id)
 	if err != nil {
-		status := http.StatusBadGateway
-		if err == domain.ErrNotFound {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, m)
+	writeCached(c, h.cache.Movie, versionPrefix(m), m)
 }
 
 // Create godoc
 // @Summary Cria um novo filme
 // @Tags movies
 // @Accept json
-// @Produce json
+// @Produce json,application/problem+json
 // @Param movie body domain.Movie true "Movie"
 // @Success 201 {object} domain.Movie
-// @Failure 400 {string} string "invalid body"
+// @Header 201 {string} ETag "Versão do filme (para If-Match)"
+// @Failure 400 {object} Problem "invalid body"
+// @Failure 409 {object} Problem "movie already exists (title+year); conflict-trashed if it is in the trash"
 // @Router /movies [post]
 func (h *MovieHandler) Create(c *gin.Context) {
 	var in domain.Movie
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		badBody(c, err)
 		return
 	}
 	m, err := h.svc.Create(&in)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		writeError(c, err)
+		return
+	}
+	writeMovie(c, http.StatusCreated, m)
+}
+
+// Replace godoc
+// @Summary Substitui os campos editáveis de um filme
+// @Tags movies
+// @Accept json
+// @Produce json,application/problem+json
+// @Param id path string true "Movie ID"
+// @Param If-Match header string true "ETag do filme lido"
+// @Param movie body domain.Movie true "Movie"
+// @Success 200 {object} domain.Movie
+// @Header 200 {string} ETag "Nova versão do filme"
+// @Failure 400 {object} Problem "invalid body"
+// @Failure 404 {object} Problem "movie not found"
+// @Failure 409 {object} Problem "movie already exists (title+year); conflict-trashed if it is in the trash"
+// @Failure 412 {object} Problem "movie changed since the If-Match ETag"
+// @Failure 428 {object} Problem "If-Match missing"
+// @Router /movies/{id} [put]
+func (h *MovieHandler) Replace(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	var in domain.Movie
+	if err := c.ShouldBindJSON(&in); err != nil {
+		badBody(c, err)
+		return
+	}
+	m, err := h.svc.Update(c.Param("id"), &in, nil, version)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	writeMovie(c, http.StatusOK, m)
+}
+
+// Patch godoc
+// @Summary Atualiza parcialmente um filme
+// @Description Só os campos presentes no corpo são alterados.
+// @Tags movies
+// @Accept json
+// @Produce json,application/problem+json
+// @Param id path string true "Movie ID"
+// @Param If-Match header string true "ETag do filme lido"
+// @Param movie body domain.MoviePatch true "Campos a alterar"
+// @Success 200 {object} domain.Movie
+// @Header 200 {string} ETag "Nova versão do filme"
+// @Failure 400 {object} Problem "invalid body"
+// @Failure 404 {object} Problem "movie not found"
+// @Failure 409 {object} Problem "movie already exists (title+year); conflict-trashed if it is in the trash"
+// @Failure 412 {object} Problem "movie changed since the If-Match ETag"
+// @Failure 428 {object} Problem "If-Match missing"
+// @Router /movies/{id} [patch]
+func (h *MovieHandler) Patch(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	var p domain.MoviePatch
+	if err := c.ShouldBindJSON(&p); err != nil {
+		badBody(c, err)
+		return
+	}
+	in, fields := p.Fields()
+	if len(fields) == 0 {
+		badRequest(c, "no fields to update")
+		return
+	}
+	m, err := h.svc.Update(c.Param("id"), &in, fields, version)
+	if err != nil {
+		writeError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, m)
+	writeMovie(c, http.StatusOK, m)
 }
 
 // Delete godoc
-// @Summary Remove um filme
+// @Summary Manda um filme para a lixeira
+// @Description O filme some de GET /movies, /movies/{id} e da busca, mas pode ser restaurado (POST /movies/{id}/restore) até a limpeza periódica removê-lo de vez. A resposta traz o filme na lixeira e o ETag para o If-Match do restore.
 // @Tags movies
+// @Produce json,application/problem+json
 // @Param id path string true "Movie ID"
-// @Success 204
-// @Failure 404 {string} string "movie not found"
+// @Param If-Match header string true "ETag do filme lido"
+// @Success 200 {object} domain.Movie
+// @Header 200 {string} ETag "Versão do filme na lixeira (para If-Match do restore)"
+// @Failure 400 {object} Problem "invalid id"
+// @Failure 404 {object} Problem "movie not found"
+// @Failure 412 {object} Problem "movie changed since the If-Match ETag"
+// @Failure 428 {object} Problem "If-Match missing"
 // @Router /movies/{id} [delete]
 func (h *MovieHandler) Delete(c *gin.Context) {
-	id := c.Param("id")
-	if err := h.svc.Delete(id); err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	m, err := h.svc.Delete(c.Param("id"), version)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	writeMovie(c, http.StatusOK, m)
+}
+
+// Restore godoc
+// @Summary Restaura um filme da lixeira
+// @Tags movies
+// @Produce json,application/problem+json
+// @Param id path string true "Movie ID"
+// @Param If-Match header string true "ETag do filme na lixeira (DELETE ou GET /movies/trash/{id})"
+// @Success 200 {object} domain.Movie
+// @Header 200 {string} ETag "Nova versão do filme"
+// @Failure 400 {object} Problem "invalid id"
+// @Failure 404 {object} Problem "movie not in trash"
+// @Failure 412 {object} Problem "movie changed since the If-Match ETag"
+// @Failure 428 {object} Problem "If-Match missing"
+// @Router /movies/{id}/restore [post]
+func (h *MovieHandler) Restore(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	m, err := h.svc.Restore(c.Param("id"), version)
+	if err != nil {
+		writeError(c, err)
 		return
 	}
-	c.Status(http.StatusNoContent)
+	writeMovie(c, http.StatusOK, m)
 }
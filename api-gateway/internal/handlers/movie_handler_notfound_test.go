@@ -20,7 +20,7 @@ func (nf nfSvc) Get(id string) (*gdomain.Movie, error) {
 func setupNFRouter(svc usecase.MovieService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
-	RegisterMovieRoutes(r, svc)
+	RegisterMovieRoutes(r, svc, CacheControl{})
 	return r
 }
 
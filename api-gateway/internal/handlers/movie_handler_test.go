@@ -1,25 +1,59 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	gdomain "github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
 	"github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type fakeSvc struct {
 	list []gdomain.Movie
+	next string
 	get  *gdomain.Movie
 	err  error
+
+	changes  []gdomain.MovieChange
+	watchErr error
+	block    bool // Watch só retorna quando ctx for cancelado
+
+	results     []gdomain.SearchResult
+	suggestions []gdomain.TitleSuggestion
+
+	gotList    gdomain.ListParams
+	gotSearch  gdomain.SearchParams
+	gotPrefix  string
+	gotLimit   int
+	gotUpdate  *gdomain.Movie
+	gotFields  []string
+	gotVersion int64
+	gotLastID  string
+	gotTrash   bool
+	gotRestore string
 }
 
-func (f *fakeSvc) List() ([]gdomain.Movie, error) { return f.list, f.err }
+func (f *fakeSvc) List(p gdomain.ListParams) (gdomain.MoviePage, error) {
+	f.gotList = p
+	return gdomain.MoviePage{Movies: f.list, NextCursor: f.next}, f.err
+}
+func (f *fakeSvc) Search(p gdomain.SearchParams) (gdomain.SearchPage, error) {
+	f.gotSearch = p
+	return gdomain.SearchPage{Results: f.results, NextCursor: f.next}, f.err
+}
+func (f *fakeSvc) Suggest(prefix string, limit int) ([]gdomain.TitleSuggestion, error) {
+	f.gotPrefix, f.gotLimit = prefix, limit
+	return f.suggestions, f.err
+}
 func (f *fakeSvc) Get(id string) (*gdomain.Movie, error) {
 	if f.err != nil {
 		return nil, f.err
@@ -33,14 +67,62 @@ func (f *fakeSvc) Create(m *gdomain.Movie) (*gdomain.Movie, error) {
 	m.ID = "new"
 	return m, nil
 }
-func (f *fakeSvc) Delete(id string) error { return f.err }
+func (f *fakeSvc) Update(id string, m *gdomain.Movie, fields []string, expectedVersion int64) (*gdomain.Movie, error) {
+	f.gotUpdate, f.gotFields, f.gotVersion = m, fields, expectedVersion
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := *m
+	out.ID = id
+	out.Version = expectedVersion + 1
+	return &out, nil
+}
+func (f *fakeSvc) Delete(id string, expectedVersion int64) (*gdomain.Movie, error) {
+	f.gotVersion = expectedVersion
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &gdomain.Movie{ID: id, Title: "Gone", Year: 1999, Version: expectedVersion + 1, DeletedAt: &trashedAt}, nil
+}
+func (f *fakeSvc) GetDeleted(id string) (*gdomain.Movie, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.get, nil
+}
+func (f *fakeSvc) ListDeleted(p gdomain.ListParams) (gdomain.MoviePage, error) {
+	f.gotList, f.gotTrash = p, true
+	return gdomain.MoviePage{Movies: f.list, NextCursor: f.next}, f.err
+}
+func (f *fakeSvc) Restore(id string, expectedVersion int64) (*gdomain.Movie, error) {
+	f.gotRestore, f.gotVersion = id, expectedVersion
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &gdomain.Movie{ID: id, Title: "Back", Year: 1999, Version: expectedVersion + 1}, nil
+}
+func (f *fakeSvc) Watch(ctx context.Context, lastEventID string, fn func(gdomain.MovieChange) error) error {
+	f.gotLastID = lastEventID
+	for _, c := range f.changes {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	if f.block {
+		<-ctx.Done()
+		return nil
+	}
+	return f.watchErr
+}
 
 var _ usecase.MovieService = (*fakeSvc)(nil)
 
+var trashedAt = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
 func setupRouter(svc usecase.MovieService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
-	RegisterMovieRoutes(r, svc)
+	RegisterMovieRoutes(r, svc, CacheControl{})
 	return r
 }
 
@@ -58,6 +140,117 @@ func TestListHandler_OK(t *testing.T) {
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
 	require.Len(t, got, 1)
 	require.Equal(t, "8", got[0].ID)
+	require.Equal(t, 50, svc.gotList.Limit)
+	require.Empty(t, w.Header().Get("X-Next-Cursor"))
+}
+
+func TestListHandler_Cursor(t *testing.T) {
+	svc := &fakeSvc{list: []gdomain.Movie{{ID: "8", Title: "X", Year: 2000}}, next: "abc"}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies?limit=500&cursor=prev", nil)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 200, svc.gotList.Limit)
+	require.Equal(t, "prev", svc.gotList.Cursor)
+	require.Equal(t, "abc", w.Header().Get("X-Next-Cursor"))
+}
+
+func TestListHandler_FiltersAndSort(t *testing.T) {
+	svc := &fakeSvc{}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies?min_year=1920&max_year=1929&title_prefix=The&sort_by=title&sort_order=desc", nil)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, gdomain.ListParams{
+		Limit:       50,
+		MinYear:     1920,
+		MaxYear:     1929,
+		TitlePrefix: "The",
+		SortBy:      "title",
+		SortOrder:   "desc",
+	}, svc.gotList)
+	require.JSONEq(t, `[]`, w.Body.String())
+}
+
+func TestListHandler_InvalidParams(t *testing.T) {
+	r := setupRouter(&fakeSvc{})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies?min_year=abc", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	r = setupRouter(&fakeSvc{err: status.Error(codes.InvalidArgument, "invalid sort field")})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/movies?sort_by=rating", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSearchHandler_OK(t *testing.T) {
+	svc := &fakeSvc{
+		results: []gdomain.SearchResult{{Movie: gdomain.Movie{ID: "7", Title: "The Great Train Robbery", Year: 1903}, Score: 11.5}},
+		next:    "abc",
+	}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies/search?q=+train+robbery+&limit=5&cursor=prev&max_year=1910", nil)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, gdomain.SearchParams{Query: "train robbery", Limit: 5, Cursor: "prev", MaxYear: 1910}, svc.gotSearch)
+	require.Equal(t, "abc", w.Header().Get("X-Next-Cursor"))
+	require.JSONEq(t, `[{"id":"7","title":"The Great Train Robbery","year":1903,"score":11.5}]`, w.Body.String())
+}
+
+func TestSearchHandler_InvalidParams(t *testing.T) {
+	for _, path := range []string{"/movies/search", "/movies/search?q=%20", "/movies/search?q=x&min_year=abc"} {
+		r := setupRouter(&fakeSvc{})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, path)
+		require.Equal(t, problemContentType, w.Header().Get("Content-Type"), path)
+	}
+
+	r := setupRouter(&fakeSvc{})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies/search?q=nothing", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `[]`, w.Body.String())
+}
+
+func TestSuggestHandler(t *testing.T) {
+	svc := &fakeSvc{suggestions: []gdomain.TitleSuggestion{{ID: "4", Title: "Lumière and Company", Year: 1995}}}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/movies/suggest?prefix=lumi&limit=5", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "lumi", svc.gotPrefix)
+	require.Equal(t, 5, svc.gotLimit)
+	require.JSONEq(t, `[{"id":"4","title":"Lumière and Company","year":1995}]`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/movies/suggest?prefix=%20", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `"field":"prefix"`)
+
+	r = setupRouter(&fakeSvc{})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/movies/suggest?prefix=zz", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `[]`, w.Body.String())
 }
 
 func TestCreateHandler_Valid(t *testing.T) {
@@ -75,3 +268,165 @@ func TestCreateHandler_Valid(t *testing.T) {
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
 	require.Equal(t, "new", m.ID)
 }
+
+func TestReplaceHandler_FullUpdate(t *testing.T) {
+	svc := &fakeSvc{}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/movies/8", strings.NewReader(`{"title":"Sneeze","year":1894}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1-0123abcd"`)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Nil(t, svc.gotFields)
+	require.Equal(t, "Sneeze", svc.gotUpdate.Title)
+}
+
+func TestPatchHandler_OnlyPresentFields(t *testing.T) {
+	svc := &fakeSvc{}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/movies/8", strings.NewReader(`{"title":"Sneeze"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1-0123abcd"`)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, []string{"title"}, svc.gotFields)
+
+	// opcionais: [] e "" presentes entram na máscara (limpam o campo)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PATCH", "/movies/8", strings.NewReader(`{"genres":["Drama"],"runtime_minutes":90,"cast":[],"poster_url":""}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1-0123abcd"`)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, []string{"genres", "runtime_minutes", "cast", "poster_url"}, svc.gotFields)
+	require.Equal(t, []string{"Drama"}, svc.gotUpdate.Genres)
+	require.Equal(t, 90, svc.gotUpdate.RuntimeMinutes)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PATCH", "/movies/8", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1-0123abcd"`)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateHandlers_ErrorMapping(t *testing.T) {
+	cases := map[codes.Code]int{
+		codes.NotFound:        http.StatusNotFound,
+		codes.AlreadyExists:   http.StatusConflict,
+		codes.InvalidArgument: http.StatusBadRequest,
+		codes.Aborted:         http.StatusPreconditionFailed,
+	}
+	for code, want := range cases {
+		r := setupRouter(&fakeSvc{err: status.Error(code, code.String())})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/movies/8", strings.NewReader(`{"year":2000}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1-0123abcd"`)
+		r.ServeHTTP(w, req)
+		require.Equal(t, want, w.Code, code.String())
+	}
+}
+
+func TestTrashHandler(t *testing.T) {
+	deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := &fakeSvc{list: []gdomain.Movie{{ID: "8", Title: "X", Year: 2000, Version: 2, DeletedAt: &deletedAt}}, next: "abc"}
+	r := setupRouter(svc)
+
+	w := get(r, "/movies/trash?min_year=1990&sort_by=title", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.True(t, svc.gotTrash)
+	require.Equal(t, gdomain.ListParams{Limit: 50, MinYear: 1990, SortBy: "title"}, svc.gotList)
+	require.Equal(t, "abc", w.Header().Get(nextCursorHeader))
+	require.JSONEq(t, `[{"id":"8","title":"X","year":2000,"version":2,"deleted_at":"2025-03-01T12:00:00Z"}]`, w.Body.String())
+
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.Equal(t, http.StatusNotModified, get(r, "/movies/trash?min_year=1990&sort_by=title", etag).Code)
+
+	require.Equal(t, http.StatusBadRequest, get(r, "/movies/trash?max_year=x", "").Code)
+}
+
+func TestGetTrashedHandler(t *testing.T) {
+	svc := &fakeSvc{get: &gdomain.Movie{ID: "8", Title: "X", Year: 2000, Version: 4, DeletedAt: &trashedAt}}
+	r := setupRouter(svc)
+
+	w := get(r, "/movies/trash/8", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"id":"8","title":"X","year":2000,"version":4,"deleted_at":"2025-03-01T12:00:00Z"}`, w.Body.String())
+	etag := w.Header().Get("ETag")
+	require.Regexp(t, `^"4-[0-9a-f]{32}"$`, etag)
+	require.Equal(t, http.StatusNotModified, get(r, "/movies/trash/8", etag).Code)
+
+	// o ETag servido é o If-Match do restore
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/movies/8/restore", nil)
+	req.Header.Set("If-Match", etag)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.EqualValues(t, 4, svc.gotVersion)
+
+	r = setupRouter(&fakeSvc{err: status.Error(codes.NotFound, "movie not found")})
+	require.Equal(t, http.StatusNotFound, get(r, "/movies/trash/8", "").Code)
+}
+
+func TestDeleteHandler(t *testing.T) {
+	r := setupRouter(&fakeSvc{})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/movies/8", nil)
+	req.Header.Set("If-Match", `"3-0123abcd"`)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"id":"8","title":"Gone","year":1999,"version":4,"deleted_at":"2025-03-01T12:00:00Z"}`, w.Body.String())
+	etag := w.Header().Get("ETag")
+	require.Regexp(t, `^"4-[0-9a-f]{32}"$`, etag)
+
+	// mesmo ETag do GET /movies/trash/{id}
+	var m gdomain.Movie
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+	require.Equal(t, etag, get(setupRouter(&fakeSvc{get: &m}), "/movies/trash/8", "").Header().Get("ETag"))
+}
+
+func TestRestoreHandler(t *testing.T) {
+	svc := &fakeSvc{gotVersion: -1}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/movies/8/restore", nil)
+	req.Header.Set("If-Match", `"2-0123abcd"`)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "8", svc.gotRestore)
+	require.EqualValues(t, 2, svc.gotVersion)
+	require.Regexp(t, `^"3-[0-9a-f]{32}"$`, w.Header().Get("ETag"))
+	require.JSONEq(t, `{"id":"8","title":"Back","year":1999,"version":3}`, w.Body.String())
+
+	for code, want := range map[codes.Code]int{
+		codes.NotFound: http.StatusNotFound,
+		codes.Aborted:  http.StatusPreconditionFailed,
+	} {
+		r := setupRouter(&fakeSvc{err: status.Error(code, code.String())})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/movies/8/restore", nil)
+		req.Header.Set("If-Match", `"2-0123abcd"`)
+		r.ServeHTTP(w, req)
+		require.Equal(t, want, w.Code, code.String())
+	}
+
+	// sem If-Match não chega ao serviço
+	svc = &fakeSvc{gotVersion: -1}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/movies/8/restore", nil)
+	setupRouter(svc).ServeHTTP(w, req)
+	require.Equal(t, http.StatusPreconditionRequired, w.Code)
+	require.Empty(t, svc.gotRestore)
+}
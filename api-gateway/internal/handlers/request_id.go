@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader header de correlação aceito na entrada e ecoado na resposta.
+const RequestIDHeader = "X-Request-ID"
+
+const (
+	requestIDKey    = "request_id"
+	maxRequestIDLen = 128
+)
+
+// RequestID reaproveita o X-Request-ID recebido (se razoável) ou gera um
+// novo; o id vai no header da resposta e nos Problem de erro.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID(c)
+		c.Next()
+	}
+}
+
+// requestID devolve o id da requisição, criando-o na primeira chamada
+// (rotas registradas sem o middleware também ganham id).
+func requestID(c *gin.Context) string {
+	if id := c.GetString(requestIDKey); id != "" {
+		return id
+	}
+	id := c.GetHeader(RequestIDHeader)
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+	c.Set(requestIDKey, id)
+	c.Header(RequestIDHeader, id)
+	return id
+}
+
+// validRequestID aceita só ASCII imprimível sem espaços e com tamanho
+// limitado (o valor é ecoado em header e log).
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -8,8 +8,15 @@ import (
 
 // MoviesClient porta de saída ( gRPC implementa)
 type MoviesClient interface {
-	List(ctx context.Context) ([]domain.Movie, error)
+	List(ctx context.Context, p domain.ListParams) (domain.MoviePage, error)
 	Get(ctx context.Context, id string) (*domain.Movie, error)
 	Create(ctx context.Context, m domain.Movie) (*domain.Movie, error)
-	Delete(ctx context.Context, id string) error
+	Update(ctx context.Context, id string, m domain.Movie, fields []string, expectedVersion int64) (*domain.Movie, error)
+	Delete(ctx context.Context, id string, expectedVersion int64) (*domain.Movie, error)
+	ListDeleted(ctx context.Context, p domain.ListParams) (domain.MoviePage, error)
+	GetDeleted(ctx context.Context, id string) (*domain.Movie, error)
+	Restore(ctx context.Context, id string, expectedVersion int64) (*domain.Movie, error)
+	Search(ctx context.Context, p domain.SearchParams) (domain.SearchPage, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]domain.TitleSuggestion, error)
+	Watch(ctx context.Context, lastEventID string, fn func(domain.MovieChange) error) error
 }
@@ -3,17 +3,38 @@ package usecase
 import (
 	"context"
 	"errors"
+	"io"
+	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
 	moviespb "github.com/caiqueborghese/sipubtech-challenge/proto/moviespb"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 type MovieService interface {
-	List() ([]domain.Movie, error)
+	List(p domain.ListParams) (domain.MoviePage, error)
 	Get(id string) (*domain.Movie, error)
 	Create(m *domain.Movie) (*domain.Movie, error)
-	Delete(id string) error
+	// Update altera só os campos em fields (nomes JSON); vazio = substituição
+	// completa. Só grava se o filme ainda estiver em expectedVersion (senão,
+	// status Aborted do serviço; 0 é InvalidArgument).
+	Update(id string, m *domain.Movie, fields []string, expectedVersion int64) (*domain.Movie, error)
+	// Delete manda id para a lixeira e devolve o filme apagado, com a versão
+	// que o Restore espera; expectedVersion como no Update.
+	Delete(id string, expectedVersion int64) (*domain.Movie, error)
+	// ListDeleted lista a lixeira, com os mesmos filtros e paginação do List.
+	ListDeleted(p domain.ListParams) (domain.MoviePage, error)
+	// GetDeleted busca um filme na lixeira.
+	GetDeleted(id string) (*domain.Movie, error)
+	// Restore tira id da lixeira; expectedVersion como no Update.
+	Restore(id string, expectedVersion int64) (*domain.Movie, error)
+	// Search busca textual por relevância (sem diferenciar maiúsculas e acentos).
+	Search(p domain.SearchParams) (domain.SearchPage, error)
+	// Suggest autocomplete de títulos por prefixo (limit 0 = default do serviço).
+	Suggest(prefix string, limit int) ([]domain.TitleSuggestion, error)
+	// Watch repassa a fn as mudanças do catálogo até ctx ser cancelado, o
+	// stream acabar ou fn falhar. lastEventID retoma depois daquele evento.
+	Watch(ctx context.Context, lastEventID string, fn func(domain.MovieChange) error) error
 }
 
 type movieService struct {
@@ -24,69 +45,246 @@ func NewMovieService(client moviespb.MovieServiceClient) MovieService {
 	return &movieService{client: client}
 }
 
-func (s *movieService) List() ([]domain.Movie, error) {
-	res, err := s.client.ListMovies(context.Background(), &emptypb.Empty{})
+func (s *movieService) List(p domain.ListParams) (domain.MoviePage, error) {
+	return toPage(s.client.ListMovies(context.Background(), listRequest(p)))
+}
+
+func (s *movieService) ListDeleted(p domain.ListParams) (domain.MoviePage, error) {
+	return toPage(s.client.ListDeletedMovies(context.Background(), listRequest(p)))
+}
+
+func listRequest(p domain.ListParams) *moviespb.ListMoviesRequest {
+	return &moviespb.ListMoviesRequest{
+		PageSize:    int32(p.Limit),
+		PageToken:   p.Cursor,
+		MinYear:     int32(p.MinYear),
+		MaxYear:     int32(p.MaxYear),
+		TitlePrefix: p.TitlePrefix,
+		SortBy:      p.SortBy,
+		SortOrder:   p.SortOrder,
+	}
+}
+
+func toPage(res *moviespb.ListMoviesResponse, err error) (domain.MoviePage, error) {
+	if err != nil {
+		return domain.MoviePage{}, err
+	}
+	out := make([]domain.Movie, 0, len(res.GetMovies()))
+	for _, m := range res.GetMovies() {
+		out = append(out, fromPB(m))
+	}
+	return domain.MoviePage{Movies: out, NextCursor: res.GetNextPageToken()}, nil
+}
+
+func (s *movieService) Search(p domain.SearchParams) (domain.SearchPage, error) {
+	res, err := s.client.SearchMovies(context.Background(), &moviespb.SearchMoviesRequest{
+		Query:     p.Query,
+		PageSize:  int32(p.Limit),
+		PageToken: p.Cursor,
+		MinYear:   int32(p.MinYear),
+		MaxYear:   int32(p.MaxYear),
+	})
+	if err != nil {
+		return domain.SearchPage{}, err
+	}
+	out := make([]domain.SearchResult, 0, len(res.GetResults()))
+	for _, r := range res.GetResults() {
+		out = append(out, domain.SearchResult{Movie: fromPB(r.GetMovie()), Score: r.GetScore()})
+	}
+	return domain.SearchPage{Results: out, NextCursor: res.GetNextPageToken()}, nil
+}
+
+func (s *movieService) Suggest(prefix string, limit int) ([]domain.TitleSuggestion, error) {
+	res, err := s.client.SuggestTitles(context.Background(), &moviespb.SuggestTitlesRequest{
+		Prefix: prefix,
+		Limit:  int32(limit),
+	})
 	if err != nil {
 		return nil, err
 	}
-	out := make([]domain.Movie, 0, len(res.Movies))
-	for _, m := range res.Movies {
-		out = append(out, domain.Movie{
-			ID:    m.GetId(),
-			Title: m.GetTitle(),
-			Year:  int(m.GetYear()),
-		})
+	out := make([]domain.TitleSuggestion, 0, len(res.GetSuggestions()))
+	for _, t := range res.GetSuggestions() {
+		out = append(out, domain.TitleSuggestion{ID: t.GetId(), Title: t.GetTitle(), Year: int(t.GetYear())})
 	}
 	return out, nil
 }
 
 func (s *movieService) Get(id string) (*domain.Movie, error) {
 	if id == "" {
-		return nil, errors.New("id required")
+		return nil, domain.ErrInvalidID
 	}
 	res, err := s.client.GetMovie(context.Background(), &moviespb.GetMovieRequest{Id: id})
 	if err != nil {
 		return nil, err
 	}
-	m := res.GetMovie()
-	if m == nil {
+	if res.GetMovie() == nil {
 		return nil, domain.ErrNotFound
 	}
-	return &domain.Movie{
-		ID:    m.GetId(),
-		Title: m.GetTitle(),
-		Year:  int(m.GetYear()),
-	}, nil
+	m := fromPB(res.GetMovie())
+	return &m, nil
 }
 
 func (s *movieService) Create(in *domain.Movie) (*domain.Movie, error) {
 	if in == nil {
-		return nil, errors.New("movie required")
+		return nil, errors.Join(domain.ErrValidation, errors.New("movie required"))
 	}
 	in.Normalize()
 	if err := in.Validate(); err != nil {
 		return nil, err
 	}
 	req := &moviespb.CreateMovieRequest{
-		Title: in.Title,
-		Year:  int32(in.Year),
+		Title:            in.Title,
+		Year:             int32(in.Year),
+		Genres:           in.Genres,
+		RuntimeMinutes:   int32(in.RuntimeMinutes),
+		Directors:        in.Directors,
+		Cast:             in.Cast,
+		Synopsis:         in.Synopsis,
+		OriginalLanguage: in.OriginalLanguage,
+		PosterUrl:        in.PosterURL,
 	}
 	res, err := s.client.CreateMovie(context.Background(), req)
 	if err != nil {
 		return nil, err
 	}
-	m := res.GetMovie()
-	return &domain.Movie{
-		ID:    m.GetId(),
-		Title: m.GetTitle(),
-		Year:  int(m.GetYear()),
-	}, nil
+	m := fromPB(res.GetMovie())
+	return &m, nil
+}
+
+func (s *movieService) Update(id string, in *domain.Movie, fields []string, expectedVersion int64) (*domain.Movie, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidID
+	}
+	if in == nil {
+		return nil, errors.Join(domain.ErrValidation, errors.New("movie required"))
+	}
+	in.Normalize()
+	if len(fields) == 0 {
+		// substituição completa: valida aqui; parcial é validado no serviço após o merge
+		if err := in.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	res, err := s.client.UpdateMovie(context.Background(), &moviespb.UpdateMovieRequest{
+		Id:              id,
+		Movie:           toPB(*in),
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: fields},
+		ExpectedVersion: expectedVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	m := fromPB(res.GetMovie())
+	return &m, nil
 }
 
-func (s *movieService) Delete(id string) error {
+func (s *movieService) Delete(id string, expectedVersion int64) (*domain.Movie, error) {
 	if id == "" {
-		return errors.New("id required")
+		return nil, domain.ErrInvalidID
+	}
+	res, err := s.client.DeleteMovie(context.Background(), &moviespb.DeleteMovieRequest{Id: id, ExpectedVersion: expectedVersion})
+	if err != nil {
+		return nil, err
+	}
+	m := fromPB(res.GetMovie())
+	return &m, nil
+}
+
+func (s *movieService) GetDeleted(id string) (*domain.Movie, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidID
+	}
+	res, err := s.client.GetDeletedMovie(context.Background(), &moviespb.GetMovieRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	if res.GetMovie() == nil {
+		return nil, domain.ErrNotFound
+	}
+	m := fromPB(res.GetMovie())
+	return &m, nil
+}
+
+func (s *movieService) Restore(id string, expectedVersion int64) (*domain.Movie, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidID
+	}
+	res, err := s.client.RestoreMovie(context.Background(), &moviespb.RestoreMovieRequest{Id: id, ExpectedVersion: expectedVersion})
+	if err != nil {
+		return nil, err
+	}
+	m := fromPB(res.GetMovie())
+	return &m, nil
+}
+
+var changeTypes = map[moviespb.MovieChange_Type]string{
+	moviespb.MovieChange_CREATED: domain.ChangeCreated,
+	moviespb.MovieChange_UPDATED: domain.ChangeUpdated,
+	moviespb.MovieChange_DELETED: domain.ChangeDeleted,
+}
+
+func (s *movieService) Watch(ctx context.Context, lastEventID string, fn func(domain.MovieChange) error) error {
+	stream, err := s.client.WatchMovies(ctx, &moviespb.WatchMoviesRequest{ResumeToken: lastEventID})
+	if err != nil {
+		return err
+	}
+	for {
+		c, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = fn(domain.MovieChange{
+			ID:    c.GetResumeToken(),
+			Type:  changeTypes[c.GetType()],
+			Movie: fromPB(c.GetMovie()),
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func fromPB(m *moviespb.Movie) domain.Movie {
+	return domain.Movie{
+		ID:               m.GetId(),
+		Title:            m.GetTitle(),
+		Year:             int(m.GetYear()),
+		Genres:           m.GetGenres(),
+		RuntimeMinutes:   int(m.GetRuntimeMinutes()),
+		Directors:        m.GetDirectors(),
+		Cast:             m.GetCast(),
+		Synopsis:         m.GetSynopsis(),
+		OriginalLanguage: m.GetOriginalLanguage(),
+		PosterURL:        m.GetPosterUrl(),
+		OriginalTitle:    m.GetOriginalTitle(),
+		Version:          m.GetVersion(),
+		DeletedAt:        deletedAt(m),
+	}
+}
+
+func deletedAt(m *moviespb.Movie) *time.Time {
+	ts := m.GetDeletedAt()
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func toPB(m domain.Movie) *moviespb.Movie {
+	return &moviespb.Movie{
+		Id:               m.ID,
+		Title:            m.Title,
+		Year:             int32(m.Year),
+		Genres:           m.Genres,
+		RuntimeMinutes:   int32(m.RuntimeMinutes),
+		Directors:        m.Directors,
+		Cast:             m.Cast,
+		Synopsis:         m.Synopsis,
+		OriginalLanguage: m.OriginalLanguage,
+		PosterUrl:        m.PosterURL,
 	}
-	_, err := s.client.DeleteMovie(context.Background(), &moviespb.DeleteMovieRequest{Id: id})
-	return err
 }
@@ -2,36 +2,109 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"io"
 	"testing"
+	"time"
 
 	gdomain "github.com/caiqueborghese/sipubtech-challenge/api-gateway/internal/domain"
 	moviespb "github.com/caiqueborghese/sipubtech-challenge/proto/moviespb"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type fakeClient struct {
 	list   []*moviespb.Movie
+	next   string
 	get    *moviespb.GetMovieResponse
 	create *moviespb.CreateMovieResponse
 	delErr error
+	update *moviespb.UpdateMovieResponse
+
+	results     []*moviespb.SearchResult
+	suggestions []*moviespb.TitleSuggestion
+
+	changes  []*moviespb.MovieChange
+	watchErr error
+
+	listReq    *moviespb.ListMoviesRequest
+	trashReq   *moviespb.ListMoviesRequest
+	restoreReq *moviespb.RestoreMovieRequest
+	createReq  *moviespb.CreateMovieRequest
+	updateReq  *moviespb.UpdateMovieRequest
+	watchReq   *moviespb.WatchMoviesRequest
+	searchReq  *moviespb.SearchMoviesRequest
+	suggestReq *moviespb.SuggestTitlesRequest
 }
 
-func (f *fakeClient) ListMovies(ctx context.Context, _ *emptypb.Empty, _ ...grpc.CallOption) (*moviespb.ListMoviesResponse, error) {
-	return &moviespb.ListMoviesResponse{Movies: f.list}, nil
+func (f *fakeClient) ListMovies(ctx context.Context, in *moviespb.ListMoviesRequest, _ ...grpc.CallOption) (*moviespb.ListMoviesResponse, error) {
+	f.listReq = in
+	return &moviespb.ListMoviesResponse{Movies: f.list, NextPageToken: f.next}, nil
+}
+func (f *fakeClient) ListDeletedMovies(ctx context.Context, in *moviespb.ListMoviesRequest, _ ...grpc.CallOption) (*moviespb.ListMoviesResponse, error) {
+	f.trashReq = in
+	return &moviespb.ListMoviesResponse{Movies: f.list, NextPageToken: f.next}, nil
+}
+func (f *fakeClient) RestoreMovie(ctx context.Context, in *moviespb.RestoreMovieRequest, _ ...grpc.CallOption) (*moviespb.RestoreMovieResponse, error) {
+	f.restoreReq = in
+	return &moviespb.RestoreMovieResponse{Movie: &moviespb.Movie{Id: in.GetId(), Title: "Back", Year: 1999, Version: in.GetExpectedVersion() + 1}}, nil
 }
 func (f *fakeClient) GetMovie(ctx context.Context, in *moviespb.GetMovieRequest, _ ...grpc.CallOption) (*moviespb.GetMovieResponse, error) {
 	return f.get, nil
 }
 func (f *fakeClient) CreateMovie(ctx context.Context, in *moviespb.CreateMovieRequest, _ ...grpc.CallOption) (*moviespb.CreateMovieResponse, error) {
+	f.createReq = in
 	return f.create, nil
 }
+func (f *fakeClient) UpdateMovie(ctx context.Context, in *moviespb.UpdateMovieRequest, _ ...grpc.CallOption) (*moviespb.UpdateMovieResponse, error) {
+	f.updateReq = in
+	return f.update, nil
+}
 func (f *fakeClient) DeleteMovie(ctx context.Context, in *moviespb.DeleteMovieRequest, _ ...grpc.CallOption) (*moviespb.DeleteMovieResponse, error) {
 	if f.delErr != nil {
 		return nil, f.delErr
 	}
-	return &moviespb.DeleteMovieResponse{Success: true}, nil
+	return &moviespb.DeleteMovieResponse{Success: true, Movie: &moviespb.Movie{Id: in.GetId(), Title: "Gone", Year: 1999, Version: in.GetExpectedVersion() + 1, DeletedAt: timestamppb.Now()}}, nil
+}
+func (f *fakeClient) GetDeletedMovie(ctx context.Context, in *moviespb.GetMovieRequest, _ ...grpc.CallOption) (*moviespb.GetMovieResponse, error) {
+	if in.GetId() != "8" {
+		return nil, status.Error(codes.NotFound, "movie not found")
+	}
+	return &moviespb.GetMovieResponse{Movie: &moviespb.Movie{Id: "8", Title: "X", Year: 1999, Version: 2, DeletedAt: timestamppb.New(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))}}, nil
+}
+func (f *fakeClient) SearchMovies(ctx context.Context, in *moviespb.SearchMoviesRequest, _ ...grpc.CallOption) (*moviespb.SearchMoviesResponse, error) {
+	f.searchReq = in
+	return &moviespb.SearchMoviesResponse{Results: f.results, NextPageToken: f.next}, nil
+}
+func (f *fakeClient) SuggestTitles(ctx context.Context, in *moviespb.SuggestTitlesRequest, _ ...grpc.CallOption) (*moviespb.SuggestTitlesResponse, error) {
+	f.suggestReq = in
+	return &moviespb.SuggestTitlesResponse{Suggestions: f.suggestions}, nil
+}
+func (f *fakeClient) WatchMovies(ctx context.Context, in *moviespb.WatchMoviesRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[moviespb.MovieChange], error) {
+	f.watchReq = in
+	return &fakeStream{changes: f.changes, err: f.watchErr}, nil
+}
+
+// fakeStream entrega changes e depois err (io.EOF se nil).
+type fakeStream struct {
+	grpc.ClientStream
+	changes []*moviespb.MovieChange
+	err     error
+}
+
+func (s *fakeStream) Recv() (*moviespb.MovieChange, error) {
+	if len(s.changes) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	c := s.changes[0]
+	s.changes = s.changes[1:]
+	return c, nil
 }
 
 func TestGatewayUsecase_List_MapsFields(t *testing.T) {
@@ -39,12 +112,49 @@ func TestGatewayUsecase_List_MapsFields(t *testing.T) {
 		list: []*moviespb.Movie{
 			{Id: "8", Title: "X", Year: 1999},
 		},
+		next: "tok",
 	}
 	svc := NewMovieService(cli)
 
-	got, err := svc.List()
+	got, err := svc.List(gdomain.ListParams{Limit: 10, Cursor: "cur", MinYear: 1990, SortBy: "year"})
 	require.NoError(t, err)
-	require.Equal(t, []gdomain.Movie{{ID: "8", Title: "X", Year: 1999}}, got)
+	require.Equal(t, []gdomain.Movie{{ID: "8", Title: "X", Year: 1999}}, got.Movies)
+	require.Equal(t, "tok", got.NextCursor)
+	require.Equal(t, int32(10), cli.listReq.GetPageSize())
+	require.Equal(t, "cur", cli.listReq.GetPageToken())
+	require.Equal(t, int32(1990), cli.listReq.GetMinYear())
+	require.Equal(t, "year", cli.listReq.GetSortBy())
+}
+
+func TestGatewayUsecase_Search_MapsFields(t *testing.T) {
+	cli := &fakeClient{
+		results: []*moviespb.SearchResult{{Movie: &moviespb.Movie{Id: "4", Title: "La sortie des usines Lumière", Year: 1895}, Score: 3.5}},
+		next:    "tok",
+	}
+	svc := NewMovieService(cli)
+
+	got, err := svc.Search(gdomain.SearchParams{Query: "lumiere", Limit: 10, Cursor: "cur", MinYear: 1890, MaxYear: 1900})
+	require.NoError(t, err)
+	require.Equal(t, []gdomain.SearchResult{
+		{Movie: gdomain.Movie{ID: "4", Title: "La sortie des usines Lumière", Year: 1895}, Score: 3.5},
+	}, got.Results)
+	require.Equal(t, "tok", got.NextCursor)
+	require.Equal(t, "lumiere", cli.searchReq.GetQuery())
+	require.Equal(t, int32(10), cli.searchReq.GetPageSize())
+	require.Equal(t, "cur", cli.searchReq.GetPageToken())
+	require.Equal(t, int32(1890), cli.searchReq.GetMinYear())
+	require.Equal(t, int32(1900), cli.searchReq.GetMaxYear())
+}
+
+func TestGatewayUsecase_Suggest_MapsFields(t *testing.T) {
+	cli := &fakeClient{suggestions: []*moviespb.TitleSuggestion{{Id: "1", Title: "The Great Train Robbery", Year: 1903}}}
+	svc := NewMovieService(cli)
+
+	got, err := svc.Suggest("gre", 5)
+	require.NoError(t, err)
+	require.Equal(t, []gdomain.TitleSuggestion{{ID: "1", Title: "The Great Train Robbery", Year: 1903}}, got)
+	require.Equal(t, "gre", cli.suggestReq.GetPrefix())
+	require.Equal(t, int32(5), cli.suggestReq.GetLimit())
 }
 
 func TestGatewayUsecase_Create_Validate(t *testing.T) {
@@ -64,3 +174,143 @@ func TestGatewayUsecase_Create_Validate(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "new", out.ID)
 }
+
+func TestGatewayUsecase_OptionalFields(t *testing.T) {
+	full := gdomain.Movie{
+		ID: "new", Title: "Metropolis", Year: 1927,
+		Genres: []string{"Drama", "Sci-Fi"}, RuntimeMinutes: 153,
+		Directors: []string{"Fritz Lang"}, Cast: []string{"Brigitte Helm"},
+		Synopsis: "A city of the future.", OriginalLanguage: "de", PosterURL: "https://img.example/m.jpg",
+	}
+	pb := &moviespb.Movie{
+		Id: "new", Title: "Metropolis", Year: 1927,
+		Genres: []string{"Drama", "Sci-Fi"}, RuntimeMinutes: 153,
+		Directors: []string{"Fritz Lang"}, Cast: []string{"Brigitte Helm"},
+		Synopsis: "A city of the future.", OriginalLanguage: "de", PosterUrl: "https://img.example/m.jpg",
+	}
+	cli := &fakeClient{
+		create: &moviespb.CreateMovieResponse{Movie: pb},
+		update: &moviespb.UpdateMovieResponse{Movie: pb},
+	}
+	svc := NewMovieService(cli)
+
+	in := full
+	out, err := svc.Create(&in)
+	require.NoError(t, err)
+	require.Equal(t, &full, out)
+	require.Equal(t, pb.GetGenres(), cli.createReq.GetGenres())
+	require.Equal(t, pb.GetRuntimeMinutes(), cli.createReq.GetRuntimeMinutes())
+	require.Equal(t, pb.GetDirectors(), cli.createReq.GetDirectors())
+	require.Equal(t, pb.GetCast(), cli.createReq.GetCast())
+	require.Equal(t, pb.GetSynopsis(), cli.createReq.GetSynopsis())
+	require.Equal(t, pb.GetOriginalLanguage(), cli.createReq.GetOriginalLanguage())
+	require.Equal(t, pb.GetPosterUrl(), cli.createReq.GetPosterUrl())
+
+	in = full
+	_, err = svc.Update("new", &in, []string{"genres", "poster_url"}, 1)
+	require.NoError(t, err)
+	require.Equal(t, pb.GetGenres(), cli.updateReq.GetMovie().GetGenres())
+	require.Equal(t, pb.GetPosterUrl(), cli.updateReq.GetMovie().GetPosterUrl())
+}
+
+func TestGatewayUsecase_Update_MaskAndValidation(t *testing.T) {
+	cli := &fakeClient{
+		update: &moviespb.UpdateMovieResponse{
+			Movie: &moviespb.Movie{Id: "8", Title: "Sneeze", Year: 1894, Version: 5},
+		},
+	}
+	svc := NewMovieService(cli)
+
+	// substituição completa valida localmente
+	_, err := svc.Update("8", &gdomain.Movie{Title: "Sneeze"}, nil, 4)
+	require.ErrorIs(t, err, gdomain.ErrValidation)
+
+	// parcial repassa a máscara
+	out, err := svc.Update("8", &gdomain.Movie{Title: " Sneeze "}, []string{"title"}, 4)
+	require.NoError(t, err)
+	require.Equal(t, "8", out.ID)
+	require.Equal(t, int64(5), out.Version)
+	require.Equal(t, int64(4), cli.updateReq.GetExpectedVersion())
+	require.Equal(t, "Sneeze", cli.updateReq.GetMovie().GetTitle())
+	require.Equal(t, []string{"title"}, cli.updateReq.GetUpdateMask().GetPaths())
+}
+
+func TestGatewayUsecase_Watch(t *testing.T) {
+	cli := &fakeClient{changes: []*moviespb.MovieChange{
+		{Type: moviespb.MovieChange_CREATED, ResumeToken: "1", Movie: &moviespb.Movie{Id: "a", Title: "A", Year: 2001}},
+		{Type: moviespb.MovieChange_DELETED, ResumeToken: "2", Movie: &moviespb.Movie{Id: "b"}},
+	}}
+	svc := NewMovieService(cli)
+
+	var got []gdomain.MovieChange
+	err := svc.Watch(context.Background(), "0", func(c gdomain.MovieChange) error {
+		got = append(got, c)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "0", cli.watchReq.GetResumeToken())
+	require.Equal(t, []gdomain.MovieChange{
+		{ID: "1", Type: gdomain.ChangeCreated, Movie: gdomain.Movie{ID: "a", Title: "A", Year: 2001}},
+		{ID: "2", Type: gdomain.ChangeDeleted, Movie: gdomain.Movie{ID: "b"}},
+	}, got)
+}
+
+func TestGatewayUsecase_Watch_Errors(t *testing.T) {
+	boom := errors.New("boom")
+	cli := &fakeClient{
+		changes:  []*moviespb.MovieChange{{Type: moviespb.MovieChange_UPDATED, ResumeToken: "1", Movie: &moviespb.Movie{Id: "a"}}},
+		watchErr: boom,
+	}
+	svc := NewMovieService(cli)
+
+	// erro do stream
+	err := svc.Watch(context.Background(), "", func(gdomain.MovieChange) error { return nil })
+	require.ErrorIs(t, err, boom)
+
+	// erro do callback interrompe o stream
+	cli.changes = []*moviespb.MovieChange{{Type: moviespb.MovieChange_UPDATED, ResumeToken: "1"}}
+	stop := errors.New("stop")
+	err = svc.Watch(context.Background(), "", func(gdomain.MovieChange) error { return stop })
+	require.ErrorIs(t, err, stop)
+}
+
+func TestGatewayUsecase_Trash(t *testing.T) {
+	deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cli := &fakeClient{
+		list: []*moviespb.Movie{{Id: "8", Title: "X", Year: 1999, Version: 2, DeletedAt: timestamppb.New(deletedAt)}},
+		next: "tok",
+	}
+	svc := NewMovieService(cli)
+
+	page, err := svc.ListDeleted(gdomain.ListParams{Limit: 10, Cursor: "c", SortBy: "year"})
+	require.NoError(t, err)
+	require.Nil(t, cli.listReq)
+	require.Equal(t, int32(10), cli.trashReq.GetPageSize())
+	require.Equal(t, "c", cli.trashReq.GetPageToken())
+	require.Equal(t, "year", cli.trashReq.GetSortBy())
+	require.Equal(t, "tok", page.NextCursor)
+	require.Len(t, page.Movies, 1)
+	require.Equal(t, &deletedAt, page.Movies[0].DeletedAt)
+
+	trashed, err := svc.GetDeleted("8")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, trashed.Version)
+	require.Equal(t, &deletedAt, trashed.DeletedAt)
+	_, err = svc.GetDeleted("9")
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	deleted, err := svc.Delete("9", 4)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, deleted.Version)
+	require.NotNil(t, deleted.DeletedAt)
+
+	m, err := svc.Restore("8", 2)
+	require.NoError(t, err)
+	require.Equal(t, "8", cli.restoreReq.GetId())
+	require.Equal(t, int64(2), cli.restoreReq.GetExpectedVersion())
+	require.EqualValues(t, 3, m.Version)
+	require.Nil(t, m.DeletedAt)
+
+	_, err = svc.Restore("", 2)
+	require.ErrorIs(t, err, gdomain.ErrInvalidID)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/consumer"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/projection"
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func env(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// projector consome os eventos de filmes do JetStream e os repassa aos
+// handlers: log + read model no Mongo.
+func main() {
+	mongoURI := env("MONGODB_URI", "mongodb://mongo:27017/moviesdb")
+	dbName := env("MONGODB_DB", "moviesdb")
+	readCollection := env("PROJECTION_COLLECTION", "movies_read")
+
+	natsURL := env("NATS_URL", "nats://nats:4222")
+	stream := env("NATS_STREAM", "MOVIES")
+	durable := env("NATS_CONSUMER", "movies-projector")
+	dlqSubject := env("NATS_DLQ_SUBJECT", "dlq.movies")
+	maxDeliver, err := strconv.Atoi(env("CONSUMER_MAX_DELIVER", "5"))
+	if err != nil {
+		log.Fatalf("CONSUMER_MAX_DELIVER: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(connectCtx, options.Client().ApplyURI(mongoURI))
+	if err != nil {
+		log.Fatalf("mongo connect: %v", err)
+	}
+	if err := client.Ping(connectCtx, nil); err != nil {
+		log.Fatalf("mongo ping: %v", err)
+	}
+	projector, err := projection.NewMongoProjector(client.Database(dbName).Collection(readCollection))
+	if err != nil {
+		log.Fatalf("new projector: %v", err)
+	}
+
+	nc, err := nats.Connect(natsURL, nats.Name("movies-projector"))
+	if err != nil {
+		log.Fatalf("nats connect: %v", err)
+	}
+	defer nc.Close()
+
+	c, err := consumer.NewConsumer(nc, consumer.Config{
+		Stream:            stream,
+		Durable:           durable,
+		DeadLetterSubject: dlqSubject,
+		MaxDeliver:        maxDeliver,
+	})
+	if err != nil {
+		log.Fatalf("new consumer: %v", err)
+	}
+	c.Register(projection.LogHandler{}, projector)
+
+	log.Printf("📥 projector consuming %s/%s -> %s.%s (dlq=%s, max_deliver=%d)", stream, durable, dbName, readCollection, dlqSubject, maxDeliver)
+	if err := c.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("projector stopped")
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/cache"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/consumer"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/nats-io/nats.go"
+)
+
+// withCache decora repo com o cache do Get configurado por env
+// (CACHE_ENABLED=false desliga). Com NATS, os eventos de qualquer réplica
+// invalidam o cache desta. Devolve também o cache (nil se desligado), que o
+// serviço invalida de novo depois de cada commit.
+func withCache(repo ports.MovieRepository, nc *nats.Conn) (ports.MovieRepository, *cache.Repository) {
+	if env("CACHE_ENABLED", "true") != "true" {
+		return repo, nil
+	}
+	cfg := cache.Config{
+		MaxEntries:  envInt("CACHE_MAX_ENTRIES", cache.DefaultMaxEntries),
+		TTL:         envDuration("CACHE_TTL", cache.DefaultTTL),
+		NegativeTTL: envDuration("CACHE_NEGATIVE_TTL", cache.DefaultNegativeTTL),
+	}
+	c := cache.NewRepository(repo, cfg)
+	go c.Report(context.Background(), envDuration("CACHE_REPORT_INTERVAL", time.Minute))
+
+	coherence := "TTL only"
+	if nc != nil {
+		s := natsSubjects()
+		if _, err := consumer.Subscribe(nc, []string{s.Created, s.Updated, s.Deleted}, c); err != nil {
+			log.Fatalf("cache invalidation: %v", err)
+		}
+		coherence = "NATS events"
+	}
+	log.Printf("cache: max=%d ttl=%s negative_ttl=%s invalidation=%s", cfg.MaxEntries, cfg.TTL, cfg.NegativeTTL, coherence)
+	return c, c
+}
+
+func envInt(key string, def int) int {
+	v, err := strconv.Atoi(env(key, strconv.Itoa(def)))
+	if err != nil {
+		log.Fatalf("%s: %v", key, err)
+	}
+	return v
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(env(key, def.String()))
+	if err != nil {
+		log.Fatalf("%s: %v", key, err)
+	}
+	return v
+}
@@ -6,12 +6,15 @@ import (
 	"os"
 	"time"
 
-	ae "github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/events"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/broadcast"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/grpcserver"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/outbox"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/repository"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/suggest"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/seed"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/usecase"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/nats-io/nats.go"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -24,36 +27,87 @@ func env(key, def string) string {
 	return def
 }
 
+func connectMongo(ctx context.Context, uri string) *mongo.Client {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		log.Fatalf("mongo connect: %v", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		log.Fatalf("mongo ping: %v", err)
+	}
+	return client
+}
+
+func connectPostgres(ctx context.Context, url string) *pgxpool.Pool {
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		log.Fatalf("postgres connect: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		log.Fatalf("postgres ping: %v", err)
+	}
+	return pool
+}
+
+// watchMongo escolhe o MovieWatcher do Mongo: change streams quando o
+// servidor oferece; senão broadcaster em memória alimentado pelas escritas
+// deste processo.
+func watchMongo(ctx context.Context, repo ports.MovieRepository, col *mongo.Collection) (ports.MovieRepository, ports.MovieWatcher) {
+	if repository.SupportsChangeStreams(ctx, col.Database().Client()) {
+		log.Printf("watch: mongo change streams")
+		return repo, repository.NewMongoWatcher(ctx, col)
+	}
+	log.Printf("watch: change streams unavailable (standalone mongo); using in-process broadcaster")
+	b := broadcast.NewBroadcaster(broadcast.DefaultHistory)
+	return broadcast.NewRepository(repo, b), b
+}
+
 func main() {
-	mongoURI := env("MONGODB_URI", "mongodb://mongo:27017/moviesdb")
-	dbName := env("MONGODB_DB", "moviesdb")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "replay":
+			runReplay(os.Args[2:])
+			return
+		case "migrate-titles":
+			runMigrateTitles(os.Args[2:])
+			return
+		}
+	}
+
 	grpcAddr := ":" + env("GRPC_PORT", "50051")
 	seedFile := os.Getenv("SEED_FILE")
 
-	// ---- NATS (opcional) ----
+	// ---- NATS (opcional; subjects/formato em publisher.go) ----
 	natsEnabled := env("NATS_ENABLED", "false")
 	natsURL := env("NATS_URL", "nats://nats:4222")
-	subjCreated := env("NATS_SUBJECT_CREATED", "movies.created")
-	subjDeleted := env("NATS_SUBJECT_DELETED", "movies.deleted")
 
-	// conecta no Mongo com timeout e ping
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	// ---- Outbox (só faz sentido com NATS ligado) ----
+	outboxEnabled := env("OUTBOX_ENABLED", "true")
+	outboxInterval, err := time.ParseDuration(env("OUTBOX_POLL_INTERVAL", "1s"))
 	if err != nil {
-		log.Fatalf("mongo connect: %v", err)
-	}
-	if err := client.Ping(ctx, nil); err != nil {
-		log.Fatalf("mongo ping: %v", err)
+		log.Fatalf("OUTBOX_POLL_INTERVAL: %v", err)
 	}
+	outboxMaxAttempts := envInt("OUTBOX_MAX_ATTEMPTS", 20)
 
-	col := client.Database(dbName).Collection("movies")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	// repositório
-	repo, err := repository.NewMongoRepository(col)
-	if err != nil {
-		log.Fatalf("new repo: %v", err)
+	// repositório (STORAGE, ver storage.go) e WatchMovies
+	st := openStorage(ctx)
+	defer st.close()
+	var (
+		client  *mongo.Client // só com STORAGE=mongo
+		repo    ports.MovieRepository
+		watcher ports.MovieWatcher
+	)
+	if st.col != nil {
+		client = st.col.Database().Client()
+		repo, watcher = watchMongo(ctx, st.repo, st.col)
+	} else {
+		// sem change streams: WatchMovies vê as escritas deste processo
+		b := broadcast.NewBroadcaster(broadcast.DefaultHistory)
+		repo, watcher = broadcast.NewRepository(st.repo, b), b
+		log.Printf("storage: %s; watch: in-process broadcaster", st.kind)
 	}
 
 	// publisher de eventos (pode ser nil)
@@ -64,18 +118,47 @@ func main() {
 		if err != nil {
 			log.Printf("NATS disabled (connect error): %v", err)
 		} else {
-			pub = ae.NewNatsPublisher(nc, subjCreated, subjDeleted)
-			log.Printf("NATS connected at %s (created=%s, deleted=%s)", natsURL, subjCreated, subjDeleted)
 			defer nc.Close()
+			if pub, err = newPublisher(ctx, nc); err != nil {
+				log.Fatalf("nats publisher: %v", err)
+			}
 		}
 	}
 
-	// serviço (com ou sem publisher)
+	// cache do Get por id (GET /movies/{id})
+	repo, getCache := withCache(repo, nc)
+
+	// autocomplete: índice de títulos em memória, carregado depois do seed
+	titles := suggest.NewIndex()
+	opts := []usecase.Option{usecase.WithWatcher(watcher), usecase.WithTitleIndex(titles)}
+	if getCache != nil {
+		opts = append(opts, usecase.WithCacheInvalidator(getCache))
+	}
+
+	// serviço (sem publisher, com publisher best-effort ou com outbox)
 	var svc ports.MovieService
-	if pub != nil {
-		svc = usecase.NewMovieServiceWithPublisher(repo, pub)
-	} else {
-		svc = usecase.NewMovieService(repo)
+	switch {
+	case pub != nil && outboxEnabled == "true" && client == nil:
+		log.Printf("outbox requires STORAGE=mongo; publishing events best-effort")
+		svc = usecase.NewMovieServiceWithPublisher(repo, pub, opts...)
+	case pub != nil && outboxEnabled == "true":
+		store, err := outbox.NewMongoStore(st.col.Database().Collection("outbox"))
+		if err != nil {
+			log.Fatalf("new outbox: %v", err)
+		}
+		tx, err := repository.NewMongoTransactor(ctx, client)
+		if err != nil {
+			log.Fatalf("outbox: %v (set OUTBOX_ENABLED=false to publish best-effort)", err)
+		}
+		svc = usecase.NewMovieServiceWithOutbox(repo, outbox.NewPublisher(store), tx, opts...)
+
+		// relay: outbox -> NATS, roda enquanto o processo viver
+		go outbox.NewRelay(store, pub, outboxInterval, outboxMaxAttempts).Run(context.Background())
+		log.Printf("outbox enabled (poll=%s max_attempts=%d)", outboxInterval, outboxMaxAttempts)
+	case pub != nil:
+		svc = usecase.NewMovieServiceWithPublisher(repo, pub, opts...)
+	default:
+		svc = usecase.NewMovieService(repo, opts...)
 	}
 
 	// seed opcional
@@ -93,7 +176,23 @@ func main() {
 		}
 	}
 
-	log.Printf("🎬 gRPC listening on %s | mongo=%s db=%s", grpcAddr, mongoURI, dbName)
+	// lixeira: DELETE só marca deleted_at; o purger remove de vez depois de
+	// TRASH_RETENTION (0 = nunca)
+	if retention := envDuration("TRASH_RETENTION", 30*24*time.Hour); retention > 0 {
+		interval := envDuration("TRASH_PURGE_INTERVAL", time.Hour)
+		go usecase.NewPurger(svc, retention, interval).Run(context.Background())
+		log.Printf("trash: retention=%s purge_interval=%s", retention, interval)
+	} else {
+		log.Printf("trash: purge disabled (TRASH_RETENTION=0)")
+	}
+
+	indexed, err := titles.Load(context.Background(), repo)
+	if err != nil {
+		log.Fatalf("load title index: %v", err)
+	}
+	log.Printf("suggest: %d titles indexed", indexed)
+
+	log.Printf("🎬 gRPC listening on %s | %s", grpcAddr, st.desc)
 	if err := grpcserver.RunGRPCServer(svc, grpcAddr); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/usecase"
+	"github.com/nats-io/nats.go"
+)
+
+// runMigrateTitles implementa `movies-server migrate-titles`: remove o
+// "(YYYY)" embutido nos títulos já importados do seed, guardando o título
+// original em original_title, no backend de STORAGE. Com NATS_ENABLED=true
+// publica movies.updated de cada filme alterado.
+func runMigrateTitles(args []string) {
+	fs := flag.NewFlagSet("migrate-titles", flag.ExitOnError)
+	batch := fs.Int("batch", 200, "filmes lidos por página")
+	dryRun := fs.Bool("dry-run", false, "só relata o que seria alterado")
+	_ = fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	st := openPersistentStorage(connectCtx, "migrate-titles")
+	defer st.close()
+	repo := st.repo
+
+	var pub ports.EventPublisher
+	if env("NATS_ENABLED", "false") == "true" && !*dryRun {
+		nc, err := nats.Connect(env("NATS_URL", "nats://nats:4222"), nats.Name("movies-migrate"))
+		if err != nil {
+			log.Fatalf("nats connect: %v", err)
+		}
+		defer func() {
+			_ = nc.Flush()
+			nc.Close()
+		}()
+		if pub, err = newPublisher(ctx, nc); err != nil {
+			log.Fatalf("nats publisher: %v", err)
+		}
+	}
+
+	rep, err := usecase.NewTitleMigrator(repo, pub).Run(ctx, *batch, *dryRun)
+	verb := "updated"
+	if *dryRun {
+		verb = "would update"
+	}
+	log.Printf("migrate-titles: scanned=%d %s=%d mismatches=%d conflicts=%d",
+		rep.Scanned, verb, rep.Updated, len(rep.Mismatch), len(rep.Conflicts))
+	if len(rep.Mismatch) > 0 {
+		log.Printf("migrate-titles: year in title differs from year (not changed): ids=%v", rep.Mismatch)
+	}
+	if len(rep.Conflicts) > 0 {
+		log.Printf("migrate-titles: clean title already taken for the same year (not changed): ids=%v", rep.Conflicts)
+	}
+	if len(rep.Changed) > 0 {
+		log.Printf("migrate-titles: changed concurrently, run again (not changed): ids=%v", rep.Changed)
+	}
+	if err != nil {
+		log.Fatalf("migrate-titles: %v", err)
+	}
+}
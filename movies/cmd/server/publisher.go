@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	ae "github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/events"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/nats-io/nats.go"
+)
+
+// newPublisher monta o EventPublisher configurado por env: NATS core ou
+// JetStream, no formato de envelope escolhido. Usado pelo servidor e pelo
+// subcomando replay.
+func newPublisher(ctx context.Context, nc *nats.Conn) (ports.EventPublisher, error) {
+	subjects := natsSubjects()
+	format, err := ae.ParseFormat(env("NATS_EVENT_FORMAT", string(ae.FormatLegacy)))
+	if err != nil {
+		return nil, fmt.Errorf("NATS_EVENT_FORMAT: %w", err)
+	}
+
+	if env("NATS_JETSTREAM", "false") == "true" {
+		// JetStream: PubAck + Nats-Msg-Id (at-least-once, retries deduplicados)
+		stream := env("NATS_STREAM", "MOVIES")
+		pub, err := ae.NewJetStreamPublisher(ctx, nc, format, stream, subjects)
+		if err != nil {
+			return nil, fmt.Errorf("jetstream: %w", err)
+		}
+		log.Printf("NATS JetStream at %s (format=%s, stream=%s, subjects=%+v)", nc.ConnectedUrl(), format, stream, subjects)
+		return pub, nil
+	}
+	log.Printf("NATS connected at %s (format=%s, subjects=%+v)", nc.ConnectedUrl(), format, subjects)
+	return ae.NewNatsPublisher(nc, format, subjects), nil
+}
+
+// natsSubjects subjects dos eventos, configurados por env.
+func natsSubjects() ae.Subjects {
+	return ae.Subjects{
+		Created:  env("NATS_SUBJECT_CREATED", "movies.created"),
+		Updated:  env("NATS_SUBJECT_UPDATED", "movies.updated"),
+		Deleted:  env("NATS_SUBJECT_DELETED", "movies.deleted"),
+		Snapshot: env("NATS_SUBJECT_SNAPSHOT", "movies.snapshot"),
+	}
+}
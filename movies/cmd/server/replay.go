@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/usecase"
+	"github.com/nats-io/nats.go"
+)
+
+// runReplay implementa `movies-server replay`: publica um movies.snapshot por
+// filme do catálogo pelo EventPublisher configurado (mesmas envs STORAGE e
+// NATS_* do servidor), para popular consumidores novos.
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	rate := fs.Int("rate", 100, "eventos por segundo (0 = sem limite)")
+	batch := fs.Int("batch", 200, "filmes lidos por página")
+	fromID := fs.String("from-id", "", "retoma logo depois deste id (tem precedência sobre -checkpoint)")
+	checkpointFile := fs.String("checkpoint", "", "arquivo com o último id publicado; lido na partida e atualizado a cada página")
+	dryRun := fs.Bool("dry-run", false, "só conta quantos eventos seriam publicados")
+	_ = fs.Parse(args)
+
+	natsURL := env("NATS_URL", "nats://nats:4222")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	st := openPersistentStorage(connectCtx, "replay")
+	defer st.close()
+	repo := st.repo
+
+	start := *fromID
+	if start == "" && *checkpointFile != "" {
+		b, err := os.ReadFile(*checkpointFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Fatalf("read checkpoint: %v", err)
+		}
+		start = strings.TrimSpace(string(b))
+	}
+
+	opts := usecase.ReplayOptions{FromID: start, Rate: *rate, BatchSize: *batch, DryRun: *dryRun}
+	if *checkpointFile != "" {
+		opts.Checkpoint = func(id string) error {
+			return os.WriteFile(*checkpointFile, []byte(id+"\n"), 0o644)
+		}
+	}
+
+	var (
+		replayer *usecase.Replayer
+		nc       *nats.Conn
+		err      error
+	)
+	if *dryRun {
+		replayer = usecase.NewReplayer(repo, nil)
+	} else {
+		nc, err = nats.Connect(natsURL, nats.Name("movies-replay"))
+		if err != nil {
+			log.Fatalf("nats connect: %v", err)
+		}
+		defer nc.Close()
+		pub, err := newPublisher(ctx, nc)
+		if err != nil {
+			log.Fatalf("nats publisher: %v", err)
+		}
+		replayer = usecase.NewReplayer(repo, pub)
+	}
+
+	log.Printf("replay: from=%q rate=%d/s batch=%d dry-run=%t", start, *rate, *batch, *dryRun)
+	n, err := replayer.Run(ctx, opts)
+	if nc != nil {
+		// NATS core é fire-and-forget: garante que o buffer foi enviado
+		if ferr := nc.Flush(); ferr != nil && err == nil {
+			err = ferr
+		}
+	}
+	if *dryRun {
+		log.Printf("replay (dry-run): %d movies would be published", n)
+	} else {
+		log.Printf("replay: %d snapshots published", n)
+	}
+	if err != nil {
+		log.Fatalf("replay: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+)
+
+const (
+	DefaultHistory = 1024
+	subBuffer      = 64
+)
+
+// ErrSlowSubscriber encerra um Watch que não acompanhou o ritmo das
+// mudanças; o cliente pode retomar com o último resume token recebido.
+var ErrSlowSubscriber = errors.New("watch: subscriber too slow")
+
+var _ ports.MovieWatcher = (*Broadcaster)(nil)
+
+// Broadcaster é o MovieWatcher em memória, usado quando o Mongo não oferece
+// change streams (standalone). Cada mudança recebe um número de sequência
+// (o resume token) e as últimas history ficam guardadas para retomada.
+// Só enxerga mudanças feitas por este processo.
+type Broadcaster struct {
+	mu      sync.Mutex
+	seq     uint64
+	history []domain.MovieChange // ordenado; no máximo cap(history)
+	subs    map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	ch      chan domain.MovieChange
+	dropped bool
+}
+
+func NewBroadcaster(history int) *Broadcaster {
+	if history <= 0 {
+		history = DefaultHistory
+	}
+	return &Broadcaster{
+		history: make([]domain.MovieChange, 0, history),
+		subs:    make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish registra e distribui uma mudança. Nunca bloqueia: assinante com
+// buffer cheio é desligado (ErrSlowSubscriber).
+func (b *Broadcaster) Publish(typ string, m domain.Movie) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	c := domain.MovieChange{Type: typ, Movie: m, ResumeToken: strconv.FormatUint(b.seq, 10)}
+	if len(b.history) == cap(b.history) {
+		copy(b.history, b.history[1:])
+		b.history = b.history[:len(b.history)-1]
+	}
+	b.history = append(b.history, c)
+
+	for s := range b.subs {
+		select {
+		case s.ch <- c:
+		default:
+			s.dropped = true
+			close(s.ch)
+			delete(b.subs, s)
+		}
+	}
+}
+
+func (b *Broadcaster) Watch(ctx context.Context, resumeToken string, fn func(domain.MovieChange) error) error {
+	backlog, s, err := b.subscribe(resumeToken)
+	if err != nil {
+		return err
+	}
+	defer b.unsubscribe(s)
+
+	for _, c := range backlog {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case c, ok := <-s.ch:
+			if !ok {
+				return ErrSlowSubscriber
+			}
+			if err := fn(c); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// subscribe devolve, atomicamente, as mudanças guardadas depois do token e
+// um assinante que recebe as seguintes.
+func (b *Broadcaster) subscribe(token string) ([]domain.MovieChange, *subscriber, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []domain.MovieChange
+	if token != "" {
+		after, err := strconv.ParseUint(token, 10, 64)
+		if err != nil || after > b.seq {
+			return nil, nil, domain.ErrInvalidResumeToken
+		}
+		// a primeira mudança guardada precisa ser no máximo a seguinte ao
+		// token; senão houve mudanças que já saíram do histórico
+		first := b.seq + 1 - uint64(len(b.history))
+		if after+1 < first {
+			return nil, nil, domain.ErrInvalidResumeToken
+		}
+		backlog = append(backlog, b.history[after+1-first:]...)
+	}
+
+	s := &subscriber{ch: make(chan domain.MovieChange, subBuffer)}
+	b.subs[s] = struct{}{}
+	return backlog, s, nil
+}
+
+func (b *Broadcaster) unsubscribe(s *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !s.dropped {
+		delete(b.subs, s)
+	}
+}
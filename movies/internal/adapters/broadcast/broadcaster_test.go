@@ -0,0 +1,159 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/repository"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/repository/repotest"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/stretchr/testify/require"
+)
+
+var errStop = errors.New("stop")
+
+// collect roda Watch até receber n mudanças.
+func collect(t *testing.T, b *Broadcaster, token string, n int, during func()) []domain.MovieChange {
+	t.Helper()
+	var got []domain.MovieChange
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Watch(context.Background(), token, func(c domain.MovieChange) error {
+			got = append(got, c)
+			if len(got) == n {
+				return errStop
+			}
+			return nil
+		})
+	}()
+	// espera o assinante registrar (ou o histórico já bastar) antes de publicar
+	require.Eventually(t, func() bool { return subscribers(b) > 0 || len(done) > 0 }, time.Second, time.Millisecond)
+	if during != nil {
+		during()
+	}
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, errStop)
+	case <-time.After(2 * time.Second):
+		t.Fatal("watch did not receive changes")
+	}
+	return got
+}
+
+func subscribers(b *Broadcaster) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+func TestBroadcaster_LiveAndResume(t *testing.T) {
+	b := NewBroadcaster(10)
+	got := collect(t, b, "", 2, func() {
+		b.Publish(domain.EventMovieCreated, domain.Movie{ID: "1"})
+		b.Publish(domain.EventMovieUpdated, domain.Movie{ID: "1", Title: "x"})
+	})
+	require.Equal(t, domain.EventMovieCreated, got[0].Type)
+	require.Equal(t, domain.EventMovieUpdated, got[1].Type)
+
+	// reconecta com o token da primeira: recebe a segunda (histórico) e a próxima ao vivo
+	got = collect(t, b, got[0].ResumeToken, 2, func() {
+		b.Publish(domain.EventMovieDeleted, domain.Movie{ID: "1"})
+	})
+	require.Equal(t, domain.EventMovieUpdated, got[0].Type)
+	require.Equal(t, domain.EventMovieDeleted, got[1].Type)
+}
+
+func TestBroadcaster_InvalidOrExpiredToken(t *testing.T) {
+	b := NewBroadcaster(2)
+	for i := 0; i < 5; i++ {
+		b.Publish(domain.EventMovieCreated, domain.Movie{ID: "m"})
+	}
+	noop := func(domain.MovieChange) error { return nil }
+	require.ErrorIs(t, b.Watch(context.Background(), "abc", noop), domain.ErrInvalidResumeToken)
+	require.ErrorIs(t, b.Watch(context.Background(), "9", noop), domain.ErrInvalidResumeToken) // futuro
+	require.ErrorIs(t, b.Watch(context.Background(), "2", noop), domain.ErrInvalidResumeToken) // 3 saiu do histórico
+
+	got := collect(t, b, "3", 2, nil) // 4 e 5 ainda no histórico
+	require.Equal(t, "4", got[0].ResumeToken)
+	require.Equal(t, "5", got[1].ResumeToken)
+}
+
+func TestBroadcaster_DropsSlowSubscriber(t *testing.T) {
+	b := NewBroadcaster(0)
+	block := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Watch(context.Background(), "", func(domain.MovieChange) error {
+			<-block
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool { return subscribers(b) == 1 }, time.Second, time.Millisecond)
+
+	for i := 0; i < subBuffer+2; i++ {
+		b.Publish(domain.EventMovieCreated, domain.Movie{ID: "m"})
+	}
+	close(block)
+	require.ErrorIs(t, <-done, ErrSlowSubscriber)
+}
+
+func TestRepository_PublishesSuccessfulWrites(t *testing.T) {
+	b := NewBroadcaster(10)
+	repo := NewRepository(failingRepo{}, b)
+
+	_, err := repo.Delete(context.Background(), "x", domain.AnyVersion)
+	require.Error(t, err)
+	require.Zero(t, b.seq)
+
+	_, err = repo.Create(context.Background(), &domain.Movie{Title: "A", Year: 2000})
+	require.NoError(t, err)
+	require.Equal(t, domain.EventMovieCreated, b.history[0].Type)
+	require.Equal(t, "new", b.history[0].Movie.ID)
+}
+
+func TestRepository_TrashEvents(t *testing.T) {
+	b := NewBroadcaster(10)
+	repo := NewRepository(repository.NewMemoryRepository(), b)
+	ctx := context.Background()
+
+	m, err := repo.Create(ctx, &domain.Movie{Title: "A", Year: 2000})
+	require.NoError(t, err)
+	_, err = repo.Delete(ctx, m.ID, domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = repo.Restore(ctx, m.ID, domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = repo.Delete(ctx, m.ID, domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = repo.HardDelete(ctx, m.ID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	var types []string
+	for _, c := range b.history {
+		types = append(types, c.Type)
+	}
+	require.Equal(t, []string{domain.EventMovieCreated, domain.EventMovieDeleted, domain.EventMovieCreated,
+		domain.EventMovieDeleted, domain.EventMovieDeleted}, types)
+	require.NotNil(t, b.history[1].Movie.DeletedAt)
+}
+
+// o decorator não muda o contrato do repositório decorado
+func TestRepository_Conformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) ports.MovieRepository {
+		return NewRepository(repository.NewMemoryRepository(), NewBroadcaster(DefaultHistory))
+	})
+}
+
+// failingRepo: Create funciona, Delete falha; o resto não é usado.
+type failingRepo struct{ ports.MovieRepository }
+
+func (failingRepo) Create(ctx context.Context, m *domain.Movie) (*domain.Movie, error) {
+	cp := *m
+	cp.ID = "new"
+	return &cp, nil
+}
+func (failingRepo) Delete(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	return nil, domain.ErrNotFound
+}
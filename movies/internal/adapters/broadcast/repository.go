@@ -0,0 +1,66 @@
+package broadcast
+
+import (
+	"context"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+)
+
+var _ ports.MovieRepository = (*Repository)(nil)
+
+// Repository decora um MovieRepository e publica no Broadcaster cada
+// escrita bem-sucedida. O seed em lote (BulkInsertIgnoreDuplicates) não
+// gera mudanças.
+type Repository struct {
+	ports.MovieRepository
+	b *Broadcaster
+}
+
+func NewRepository(repo ports.MovieRepository, b *Broadcaster) *Repository {
+	return &Repository{MovieRepository: repo, b: b}
+}
+
+func (r *Repository) Create(ctx context.Context, m *domain.Movie) (*domain.Movie, error) {
+	created, err := r.MovieRepository.Create(ctx, m)
+	if err == nil {
+		r.b.Publish(domain.EventMovieCreated, *created)
+	}
+	return created, err
+}
+
+func (r *Repository) Update(ctx context.Context, id string, m *domain.Movie, version int64) (*domain.Movie, error) {
+	updated, err := r.MovieRepository.Update(ctx, id, m, version)
+	if err == nil {
+		r.b.Publish(domain.EventMovieUpdated, *updated)
+	}
+	return updated, err
+}
+
+// Delete tira o filme do catálogo (deleted, com deleted_at) e Restore o
+// devolve (created), como o serviço publica e o change stream do Mongo
+// traduz. O HardDelete do purge é deleted de novo.
+func (r *Repository) Delete(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	deleted, err := r.MovieRepository.Delete(ctx, id, version)
+	if err == nil {
+		r.b.Publish(domain.EventMovieDeleted, *deleted)
+	}
+	return deleted, err
+}
+
+func (r *Repository) Restore(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	restored, err := r.MovieRepository.Restore(ctx, id, version)
+	if err == nil {
+		r.b.Publish(domain.EventMovieCreated, *restored)
+	}
+	return restored, err
+}
+
+func (r *Repository) HardDelete(ctx context.Context, id string, deletedBefore time.Time) (*domain.Movie, error) {
+	purged, err := r.MovieRepository.HardDelete(ctx, id, deletedBefore)
+	if err == nil {
+		r.b.Publish(domain.EventMovieDeleted, *purged)
+	}
+	return purged, err
+}
@@ -0,0 +1,282 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+)
+
+var (
+	_ ports.MovieRepository = (*Repository)(nil)
+	_ ports.EventHandler    = (*Repository)(nil)
+)
+
+const (
+	DefaultMaxEntries  = 10000
+	DefaultTTL         = 30 * time.Second
+	DefaultNegativeTTL = 5 * time.Second
+)
+
+// Config do cache; campos zerados usam os Default*.
+type Config struct {
+	MaxEntries  int           // filmes (e not found) guardados; o menos usado sai primeiro
+	TTL         time.Duration // validade de um filme encontrado
+	NegativeTTL time.Duration // validade de um not found
+}
+
+// Stats contadores desde a criação do cache.
+type Stats struct {
+	Hits          uint64 // inclui NegativeHits
+	NegativeHits  uint64
+	Misses        uint64
+	Evictions     uint64 // saídas por MaxEntries
+	Invalidations uint64
+	Entries       int
+}
+
+// HitRate fração de Gets servidos pelo cache (0 sem Gets).
+func (s Stats) HitRate() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+// Repository decora um MovieRepository com cache read-through do Get: LRU
+// em memória, com TTL e cache negativo de not found. Escritas por este
+// decorator invalidam as chaves afetadas; escritas de outras réplicas
+// chegam como eventos (Handle) ou expiram pelo TTL.
+type Repository struct {
+	ports.MovieRepository
+	cfg Config
+	now func() time.Time
+
+	mu    sync.Mutex
+	lru   *list.List // de *entry; frente = usado mais recentemente
+	items map[string]*list.Element
+	// gen muda a cada invalidação: um Get que leu do banco antes dela não
+	// grava o valor (possivelmente velho) no cache
+	gen uint64
+
+	hits, negativeHits, misses, evictions, invalidations atomic.Uint64
+}
+
+type entry struct {
+	key     string
+	movie   *domain.Movie // nil = not found
+	expires time.Time
+}
+
+func NewRepository(repo ports.MovieRepository, cfg Config) *Repository {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = DefaultMaxEntries
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+	if cfg.NegativeTTL <= 0 {
+		cfg.NegativeTTL = DefaultNegativeTTL
+	}
+	return &Repository{
+		MovieRepository: repo,
+		cfg:             cfg,
+		now:             time.Now,
+		lru:             list.New(),
+		items:           make(map[string]*list.Element),
+	}
+}
+
+func (r *Repository) Get(ctx context.Context, id string) (*domain.Movie, error) {
+	r.mu.Lock()
+	if el, ok := r.items[id]; ok {
+		e := el.Value.(*entry)
+		if r.now().Before(e.expires) {
+			r.lru.MoveToFront(el)
+			r.mu.Unlock()
+			r.hits.Add(1)
+			if e.movie == nil {
+				r.negativeHits.Add(1)
+				return nil, domain.ErrNotFound
+			}
+			return clone(e.movie), nil
+		}
+		r.removeElement(el)
+	}
+	gen := r.gen
+	r.mu.Unlock()
+	r.misses.Add(1)
+
+	m, err := r.MovieRepository.Get(ctx, id)
+	switch {
+	case err == nil && m.ID == id:
+		// só pelo id externo: o mesmo filme buscado pelo ObjectID interno
+		// não seria alcançado pela invalidação
+		r.store(gen, id, clone(m), r.cfg.TTL)
+	case errors.Is(err, domain.ErrNotFound):
+		r.store(gen, id, nil, r.cfg.NegativeTTL)
+	}
+	return m, err
+}
+
+func (r *Repository) Create(ctx context.Context, m *domain.Movie) (*domain.Movie, error) {
+	created, err := r.MovieRepository.Create(ctx, m)
+	if err == nil {
+		r.Invalidate(created.ID) // pode haver um not found guardado
+	}
+	return created, err
+}
+
+// Update e Delete também invalidam id em ErrVersionMismatch: a versão
+// esperada veio de um Get, possivelmente do cache, que está velho.
+func (r *Repository) Update(ctx context.Context, id string, m *domain.Movie, version int64) (*domain.Movie, error) {
+	updated, err := r.MovieRepository.Update(ctx, id, m, version)
+	switch {
+	case err == nil:
+		r.Invalidate(id, updated.ID)
+	case errors.Is(err, domain.ErrVersionMismatch):
+		r.Invalidate(id)
+	}
+	return updated, err
+}
+
+func (r *Repository) Delete(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	deleted, err := r.MovieRepository.Delete(ctx, id, version)
+	switch {
+	case err == nil:
+		r.Invalidate(id, deleted.ID)
+	case errors.Is(err, domain.ErrVersionMismatch):
+		r.Invalidate(id)
+	}
+	return deleted, err
+}
+
+// Restore invalida como o Delete: o id guardado como not found volta a existir.
+func (r *Repository) Restore(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	restored, err := r.MovieRepository.Restore(ctx, id, version)
+	switch {
+	case err == nil:
+		r.Invalidate(id, restored.ID)
+	case errors.Is(err, domain.ErrVersionMismatch):
+		r.Invalidate(id)
+	}
+	return restored, err
+}
+
+// BulkInsertIgnoreDuplicates descarta o cache inteiro quando insere algo:
+// qualquer not found guardado pode ter deixado de valer.
+func (r *Repository) BulkInsertIgnoreDuplicates(ctx context.Context, ms []domain.Movie) (int, error) {
+	n, err := r.MovieRepository.BulkInsertIgnoreDuplicates(ctx, ms)
+	if n > 0 {
+		r.Purge()
+	}
+	return n, err
+}
+
+// Invalidate remove os ids do cache.
+func (r *Repository) Invalidate(ids ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gen++
+	for _, id := range ids {
+		if el, ok := r.items[id]; ok {
+			r.removeElement(el)
+		}
+	}
+	r.invalidations.Add(1)
+}
+
+// Purge esvazia o cache.
+func (r *Repository) Purge() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gen++
+	r.lru.Init()
+	clear(r.items)
+	r.invalidations.Add(1)
+}
+
+func (r *Repository) Stats() Stats {
+	r.mu.Lock()
+	n := r.lru.Len()
+	r.mu.Unlock()
+	return Stats{
+		Hits:          r.hits.Load(),
+		NegativeHits:  r.negativeHits.Load(),
+		Misses:        r.misses.Load(),
+		Evictions:     r.evictions.Load(),
+		Invalidations: r.invalidations.Load(),
+		Entries:       n,
+	}
+}
+
+// Report registra Stats no log a cada every, até ctx ser cancelado.
+func (r *Repository) Report(ctx context.Context, every time.Duration) {
+	tick := time.NewTicker(every)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			s := r.Stats()
+			log.Printf("cache: hits=%d (not found=%d) misses=%d hit_rate=%.1f%% entries=%d evictions=%d invalidations=%d",
+				s.Hits, s.NegativeHits, s.Misses, 100*s.HitRate(), s.Entries, s.Evictions, s.Invalidations)
+		}
+	}
+}
+
+// Name e Handle fazem do cache um ports.EventHandler: eventos de
+// filmes (de qualquer réplica) invalidam o id afetado.
+func (r *Repository) Name() string { return "cache" }
+
+func (r *Repository) Handle(_ context.Context, ev domain.MovieEvent) error {
+	ids := []string{ev.Movie.ID}
+	if ev.Before != nil {
+		ids = append(ids, ev.Before.ID)
+	}
+	r.Invalidate(ids...)
+	return nil
+}
+
+// store grava a entrada se nenhuma invalidação aconteceu desde gen.
+func (r *Repository) store(gen uint64, key string, m *domain.Movie, ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.gen != gen {
+		return
+	}
+	e := &entry{key: key, movie: m, expires: r.now().Add(ttl)}
+	if el, ok := r.items[key]; ok {
+		el.Value = e
+		r.lru.MoveToFront(el)
+		return
+	}
+	r.items[key] = r.lru.PushFront(e)
+	for r.lru.Len() > r.cfg.MaxEntries {
+		r.removeElement(r.lru.Back())
+		r.evictions.Add(1)
+	}
+}
+
+// removeElement chamado com mu obtido.
+func (r *Repository) removeElement(el *list.Element) {
+	r.lru.Remove(el)
+	delete(r.items, el.Value.(*entry).key)
+}
+
+// clone copia as listas: quem recebe o filme pode alterá-lo à vontade.
+func clone(m *domain.Movie) *domain.Movie {
+	cp := *m
+	cp.Genres = slices.Clone(m.Genres)
+	cp.Directors = slices.Clone(m.Directors)
+	cp.Cast = slices.Clone(m.Cast)
+	return &cp
+}
@@ -0,0 +1,207 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/repository"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/repository/repotest"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/stretchr/testify/require"
+)
+
+// o decorator não muda o contrato do repositório decorado
+func TestRepository_Conformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) ports.MovieRepository {
+		return NewRepository(repository.NewMemoryRepository(), Config{})
+	})
+}
+
+// countingRepo conta os Gets que chegam ao repositório decorado.
+type countingRepo struct {
+	ports.MovieRepository
+	gets atomic.Int64
+	// during roda no meio do Get, depois da leitura (simula corrida)
+	during func()
+}
+
+func (r *countingRepo) Get(ctx context.Context, id string) (*domain.Movie, error) {
+	r.gets.Add(1)
+	m, err := r.MovieRepository.Get(ctx, id)
+	if r.during != nil {
+		r.during()
+	}
+	return m, err
+}
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+func newClock() *clock                   { return &clock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)} }
+
+// seeded repositório em memória com os filmes "1", "2" e "3".
+func seeded(t *testing.T) *countingRepo {
+	t.Helper()
+	mem := repository.NewMemoryRepository()
+	_, err := mem.BulkInsertIgnoreDuplicates(context.Background(), []domain.Movie{
+		{Title: "One", Year: 2001, LegacyID: "1", Genres: []string{"Drama"}},
+		{Title: "Two", Year: 2002, LegacyID: "2"},
+		{Title: "Three", Year: 2003, LegacyID: "3"},
+	})
+	require.NoError(t, err)
+	return &countingRepo{MovieRepository: mem}
+}
+
+func TestRepository_HitsMissesAndTTL(t *testing.T) {
+	inner, clk := seeded(t), newClock()
+	c := NewRepository(inner, Config{TTL: time.Minute, NegativeTTL: time.Second})
+	c.now = clk.now
+	ctx := context.Background()
+
+	for range 3 {
+		m, err := c.Get(ctx, "1")
+		require.NoError(t, err)
+		require.Equal(t, "One", m.Title)
+		m.Genres[0] = "changed" // cópia: não altera o cache
+	}
+	m, _ := c.Get(ctx, "1")
+	require.Equal(t, []string{"Drama"}, m.Genres)
+	require.EqualValues(t, 1, inner.gets.Load())
+
+	// not found também é guardado, por menos tempo
+	for range 2 {
+		_, err := c.Get(ctx, "404")
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	}
+	require.EqualValues(t, 2, inner.gets.Load())
+	clk.advance(2 * time.Second)
+	_, err := c.Get(ctx, "404")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	require.EqualValues(t, 3, inner.gets.Load())
+
+	clk.advance(time.Minute)
+	_, err = c.Get(ctx, "1")
+	require.NoError(t, err)
+	require.EqualValues(t, 4, inner.gets.Load())
+
+	s := c.Stats()
+	require.Equal(t, Stats{Hits: 4, NegativeHits: 1, Misses: 4, Entries: 2}, s)
+	require.InDelta(t, 0.5, s.HitRate(), 1e-9)
+}
+
+func TestRepository_LRUEviction(t *testing.T) {
+	inner := seeded(t)
+	c := NewRepository(inner, Config{MaxEntries: 2})
+	ctx := context.Background()
+
+	for _, id := range []string{"1", "2", "1", "3"} { // "2" é o menos usado
+		_, err := c.Get(ctx, id)
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 3, inner.gets.Load())
+	_, _ = c.Get(ctx, "1")
+	require.EqualValues(t, 3, inner.gets.Load())
+	_, _ = c.Get(ctx, "2")
+	require.EqualValues(t, 4, inner.gets.Load())
+
+	s := c.Stats()
+	require.Equal(t, 2, s.Entries)
+	require.EqualValues(t, 2, s.Evictions)
+}
+
+func TestRepository_WritesInvalidate(t *testing.T) {
+	inner := seeded(t)
+	c := NewRepository(inner, Config{})
+	ctx := context.Background()
+
+	_, _ = c.Get(ctx, "1")
+	_, err := c.Update(ctx, "1", &domain.Movie{Title: "One!", Year: 2001}, domain.AnyVersion)
+	require.NoError(t, err)
+	m, err := c.Get(ctx, "1")
+	require.NoError(t, err)
+	require.Equal(t, "One!", m.Title)
+
+	_, err = c.Delete(ctx, "1", domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = c.Get(ctx, "1")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// not found guardado some quando o filme volta da lixeira
+	_, err = c.Restore(ctx, "1", domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = c.Get(ctx, "1")
+	require.NoError(t, err)
+
+	// ...ou passa a existir
+	_, err = c.Get(ctx, "9")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = c.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{{Title: "Nine", Year: 2009, LegacyID: "9"}})
+	require.NoError(t, err)
+	_, err = c.Get(ctx, "9")
+	require.NoError(t, err)
+
+	created, err := c.Create(ctx, &domain.Movie{Title: "New", Year: 2020})
+	require.NoError(t, err)
+	_, err = c.Get(ctx, created.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, 7, inner.gets.Load())
+}
+
+func TestRepository_EventsInvalidate(t *testing.T) {
+	inner := seeded(t)
+	c := NewRepository(inner, Config{})
+	ctx := context.Background()
+
+	_, _ = c.Get(ctx, "2")
+	// escrita de outra réplica: direto no repositório, avisada por evento
+	_, err := inner.Update(ctx, "2", &domain.Movie{Title: "Two!", Year: 2002}, domain.AnyVersion)
+	require.NoError(t, err)
+	m, _ := c.Get(ctx, "2")
+	require.Equal(t, "Two", m.Title, "ainda no cache")
+
+	require.NoError(t, c.Handle(ctx, domain.MovieEvent{Type: domain.EventMovieUpdated, Movie: domain.Movie{ID: "2"}}))
+	m, _ = c.Get(ctx, "2")
+	require.Equal(t, "Two!", m.Title)
+}
+
+// Get que leu antes de uma invalidação não grava o valor velho.
+func TestRepository_InvalidationDuringGet(t *testing.T) {
+	inner := seeded(t)
+	c := NewRepository(inner, Config{})
+	ctx := context.Background()
+
+	inner.during = func() {
+		inner.during = nil
+		_, err := c.Update(ctx, "3", &domain.Movie{Title: "Three!", Year: 2003}, domain.AnyVersion)
+		require.NoError(t, err)
+	}
+	m, err := c.Get(ctx, "3")
+	require.NoError(t, err)
+	require.Equal(t, "Three", m.Title)
+
+	m, err = c.Get(ctx, "3")
+	require.NoError(t, err)
+	require.Equal(t, "Three!", m.Title)
+}
+
+// versão esperada que não confere: o Get que a forneceu pode ter vindo de
+// uma entrada velha do cache
+func TestRepository_VersionMismatchInvalidates(t *testing.T) {
+	inner := seeded(t)
+	c := NewRepository(inner, Config{})
+	ctx := context.Background()
+
+	m, _ := c.Get(ctx, "1")
+	_, err := inner.Update(ctx, "1", &domain.Movie{Title: "One!", Year: 2001}, domain.AnyVersion)
+	require.NoError(t, err)
+
+	_, err = c.Update(ctx, "1", &domain.Movie{Title: "Mine", Year: 2001}, m.Version)
+	require.ErrorIs(t, err, domain.ErrVersionMismatch)
+	m, _ = c.Get(ctx, "1")
+	require.Equal(t, "One!", m.Title)
+	require.EqualValues(t, 2, m.Version)
+}
@@ -0,0 +1,194 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/events"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	defaultMaxDeliver = 5
+	defaultMinBackoff = time.Second
+	defaultMaxBackoff = time.Minute
+	defaultAckWait    = 30 * time.Second
+)
+
+// Headers acrescentados às mensagens enviadas para o dead-letter subject.
+const (
+	HeaderDLQSubject   = "Movies-DLQ-Subject"
+	HeaderDLQError     = "Movies-DLQ-Error"
+	HeaderDLQDelivered = "Movies-DLQ-Delivered"
+)
+
+// Config do consumer durável. Stream e Durable são obrigatórios; os demais
+// têm padrão.
+type Config struct {
+	Stream   string
+	Durable  string
+	Subjects []string // vazio = todos os subjects do stream
+	// DeadLetterSubject recebe mensagens que não decodificam ou que
+	// esgotaram MaxDeliver tentativas. Vazio = mensagem é só descartada (Term).
+	DeadLetterSubject string
+	// DeadLetterStream guarda o dead-letter subject (padrão: Stream+"_DLQ").
+	DeadLetterStream string
+	MaxDeliver       int
+	MinBackoff       time.Duration
+	MaxBackoff       time.Duration
+	AckWait          time.Duration
+}
+
+// Consumer lê eventos de filmes de um consumer JetStream durável e repassa
+// a cada handler registrado (at-least-once). Falha de handler vira NAK com
+// backoff exponencial; depois de MaxDeliver entregas, ou se a mensagem não
+// decodifica, ela vai para o dead-letter subject e é encerrada.
+type Consumer struct {
+	js       jetstream.JetStream
+	cfg      Config
+	handlers []ports.EventHandler
+}
+
+func NewConsumer(nc *nats.Conn, cfg Config) (*Consumer, error) {
+	if cfg.Stream == "" || cfg.Durable == "" {
+		return nil, errors.New("consumer: stream and durable are required")
+	}
+	if cfg.MaxDeliver <= 0 {
+		cfg.MaxDeliver = defaultMaxDeliver
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+	if cfg.DeadLetterStream == "" {
+		cfg.DeadLetterStream = cfg.Stream + "_DLQ"
+	}
+	if cfg.AckWait <= 0 {
+		cfg.AckWait = defaultAckWait
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("jetstream: %w", err)
+	}
+	return &Consumer{js: js, cfg: cfg}, nil
+}
+
+// Register adiciona handlers; todos recebem todos os eventos, na ordem de
+// registro. Chamar antes de Run.
+func (c *Consumer) Register(hs ...ports.EventHandler) {
+	c.handlers = append(c.handlers, hs...)
+}
+
+// Run cria (ou atualiza) o consumer durável e processa mensagens até ctx
+// ser cancelado.
+func (c *Consumer) Run(ctx context.Context) error {
+	if c.cfg.DeadLetterSubject != "" {
+		if err := events.EnsureStream(ctx, c.js, c.cfg.DeadLetterStream, []string{c.cfg.DeadLetterSubject}); err != nil {
+			return fmt.Errorf("dead-letter: %w", err)
+		}
+	}
+	cons, err := c.js.CreateOrUpdateConsumer(ctx, c.cfg.Stream, jetstream.ConsumerConfig{
+		Durable:        c.cfg.Durable,
+		FilterSubjects: c.cfg.Subjects,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		AckWait:        c.cfg.AckWait,
+		// sem limite no servidor: quem decide o dead-letter é o Consumer
+		MaxDeliver: -1,
+	})
+	if err != nil {
+		return fmt.Errorf("consumer %s/%s: %w", c.cfg.Stream, c.cfg.Durable, err)
+	}
+
+	cc, err := cons.Consume(func(msg jetstream.Msg) { c.process(ctx, msg) })
+	if err != nil {
+		return fmt.Errorf("consume %s/%s: %w", c.cfg.Stream, c.cfg.Durable, err)
+	}
+	<-ctx.Done()
+	cc.Drain()
+	<-cc.Closed()
+	return nil
+}
+
+func (c *Consumer) process(ctx context.Context, msg jetstream.Msg) {
+	var delivered uint64 = 1
+	if md, err := msg.Metadata(); err == nil {
+		delivered = md.NumDelivered
+	}
+
+	ev, err := events.Decode(msg.Headers(), msg.Data())
+	if err != nil {
+		c.deadLetter(ctx, msg, delivered, err)
+		return
+	}
+
+	if err := c.dispatch(ctx, ev); err != nil {
+		if delivered >= uint64(c.cfg.MaxDeliver) {
+			c.deadLetter(ctx, msg, delivered, err)
+			return
+		}
+		delay := c.backoff(int(delivered))
+		log.Printf("consumer: %s %s (delivery %d) failed, retrying in %s: %v", ev.Type, ev.ID, delivered, delay, err)
+		_ = msg.NakWithDelay(delay)
+		return
+	}
+	if err := msg.Ack(); err != nil {
+		log.Printf("consumer: ack %s %s: %v", ev.Type, ev.ID, err)
+	}
+}
+
+// dispatch chama os handlers em ordem e para no primeiro erro. Na
+// retentativa todos rodam de novo (por isso precisam ser idempotentes).
+func (c *Consumer) dispatch(ctx context.Context, ev domain.MovieEvent) error {
+	for _, h := range c.handlers {
+		if err := h.Handle(ctx, ev); err != nil {
+			return fmt.Errorf("handler %s: %w", h.Name(), err)
+		}
+	}
+	return nil
+}
+
+// deadLetter copia a mensagem (headers + corpo) para o dead-letter subject
+// com o motivo e encerra a original. Se o DLQ falhar, a original recebe NAK
+// e volta depois: nada é perdido em silêncio.
+func (c *Consumer) deadLetter(ctx context.Context, msg jetstream.Msg, delivered uint64, cause error) {
+	log.Printf("consumer: dead-lettering %s message (delivery %d): %v", msg.Subject(), delivered, cause)
+	if c.cfg.DeadLetterSubject != "" {
+		dlq := nats.NewMsg(c.cfg.DeadLetterSubject)
+		for k, vs := range msg.Headers() {
+			if k == jetstream.MsgIDHeader {
+				continue // não deixa a dedup do stream de DLQ engolir a cópia
+			}
+			dlq.Header[k] = vs
+		}
+		dlq.Header.Set(HeaderDLQSubject, msg.Subject())
+		dlq.Header.Set(HeaderDLQError, cause.Error())
+		dlq.Header.Set(HeaderDLQDelivered, strconv.FormatUint(delivered, 10))
+		dlq.Data = msg.Data()
+		if _, err := c.js.PublishMsg(ctx, dlq); err != nil {
+			log.Printf("consumer: dead-letter publish to %s failed: %v", c.cfg.DeadLetterSubject, err)
+			_ = msg.NakWithDelay(c.cfg.MaxBackoff)
+			return
+		}
+	}
+	_ = msg.Term()
+}
+
+func (c *Consumer) backoff(attempts int) time.Duration {
+	d := c.cfg.MinBackoff
+	for i := 1; i < attempts && d < c.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > c.cfg.MaxBackoff {
+		d = c.cfg.MaxBackoff
+	}
+	return d
+}
@@ -0,0 +1,165 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/events"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+var testSubjects = events.Subjects{Created: "movies.created", Updated: "movies.updated", Deleted: "movies.deleted", Snapshot: "movies.snapshot"}
+
+func runJetStream(t *testing.T) *nats.Conn {
+	t.Helper()
+	ns, err := server.NewServer(&server.Options{Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	require.NoError(t, err)
+	ns.Start()
+	t.Cleanup(ns.Shutdown)
+	require.True(t, ns.ReadyForConnections(5*time.Second))
+
+	nc, err := nats.Connect(ns.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+// recHandler falha as primeiras failures chamadas e registra o resto.
+type recHandler struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	got      []domain.MovieEvent
+}
+
+func (h *recHandler) Name() string { return "rec" }
+
+func (h *recHandler) Handle(_ context.Context, ev domain.MovieEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	if h.calls <= h.failures {
+		return errors.New("boom")
+	}
+	h.got = append(h.got, ev)
+	return nil
+}
+
+func (h *recHandler) events() []domain.MovieEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]domain.MovieEvent(nil), h.got...)
+}
+
+func startConsumer(t *testing.T, nc *nats.Conn, hs ...*recHandler) {
+	t.Helper()
+	c, err := NewConsumer(nc, Config{
+		Stream:            "MOVIES",
+		Durable:           "test",
+		DeadLetterSubject: "movies-dlq",
+		MaxDeliver:        3,
+		MinBackoff:        10 * time.Millisecond,
+		MaxBackoff:        20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	for _, h := range hs {
+		c.Register(h)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
+
+func TestConsumer_RetriesUntilHandled(t *testing.T) {
+	nc := runJetStream(t)
+	ctx := context.Background()
+	pub, err := events.NewJetStreamPublisher(ctx, nc, events.FormatCloudEvents, "MOVIES", testSubjects)
+	require.NoError(t, err)
+
+	h := &recHandler{failures: 2}
+	startConsumer(t, nc, h)
+
+	m := domain.Movie{ID: "8", Title: "Sneeze", Year: 1894}
+	require.NoError(t, pub.MovieCreated(ctx, m))
+	require.NoError(t, pub.MovieDeleted(ctx, m))
+
+	require.Eventually(t, func() bool { return len(h.events()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	got := h.events()
+	require.Equal(t, domain.EventMovieCreated, got[0].Type)
+	require.Equal(t, m, got[0].Movie)
+	require.Equal(t, domain.EventMovieDeleted, got[1].Type)
+}
+
+func TestConsumer_DeadLetters(t *testing.T) {
+	nc := runJetStream(t)
+	ctx := context.Background()
+	pub, err := events.NewJetStreamPublisher(ctx, nc, events.FormatLegacy, "MOVIES", testSubjects)
+	require.NoError(t, err)
+
+	dlq, err := nc.SubscribeSync("movies-dlq")
+	require.NoError(t, err)
+
+	h := &recHandler{failures: 1000}
+	startConsumer(t, nc, h)
+
+	// poison: não decodifica, vai direto para o DLQ
+	_, err = nc.Request("movies.created", []byte("{not json"), time.Second)
+	require.NoError(t, err)
+	msg, err := dlq.NextMsg(5 * time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "{not json", string(msg.Data))
+	require.Equal(t, "movies.created", msg.Header.Get(HeaderDLQSubject))
+	require.Equal(t, "1", msg.Header.Get(HeaderDLQDelivered))
+	require.Contains(t, msg.Header.Get(HeaderDLQError), "malformed event")
+
+	// handler sempre falha: DLQ depois de MaxDeliver entregas
+	require.NoError(t, pub.MovieCreated(ctx, domain.Movie{ID: "8", Title: "Sneeze", Year: 1894}))
+	msg, err = dlq.NextMsg(5 * time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "3", msg.Header.Get(HeaderDLQDelivered))
+	require.Contains(t, msg.Header.Get(HeaderDLQError), "handler rec: boom")
+	require.Empty(t, h.events())
+}
+
+func TestSubscribe_FanOut(t *testing.T) {
+	nc := runJetStream(t)
+	ctx := context.Background()
+	pub := events.NewNatsPublisher(nc, events.FormatCloudEventsBinary, testSubjects)
+
+	// duas "réplicas": as duas recebem todos os eventos
+	a, b := &recHandler{}, &recHandler{failures: 1}
+	for _, h := range []*recHandler{a, b} {
+		unsubscribe, err := Subscribe(nc, []string{testSubjects.Updated, testSubjects.Deleted}, h)
+		require.NoError(t, err)
+		t.Cleanup(unsubscribe)
+	}
+	require.NoError(t, nc.Flush())
+
+	before := domain.Movie{ID: "8", Title: "Sneeze", Year: 1894}
+	after := domain.Movie{ID: "8", Title: "Sneeze!", Year: 1894}
+	require.NoError(t, pub.MovieCreated(ctx, before)) // subject não inscrito
+	require.NoError(t, nc.Publish(testSubjects.Updated, []byte("{not json")))
+	require.NoError(t, pub.MovieUpdated(ctx, before, after))
+	require.NoError(t, pub.MovieDeleted(ctx, after))
+
+	require.Eventually(t, func() bool { return len(a.events()) == 2 && len(b.events()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	// sem ordem garantida entre subjects
+	byType := map[string]domain.MovieEvent{}
+	for _, ev := range a.events() {
+		byType[ev.Type] = ev
+	}
+	require.Equal(t, &before, byType[domain.EventMovieUpdated].Before)
+	require.Equal(t, after, byType[domain.EventMovieDeleted].Movie)
+	// erro do handler não é retentado: b perdeu o primeiro evento
+	require.Len(t, b.events(), 1)
+}
@@ -0,0 +1,47 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/events"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/nats-io/nats.go"
+)
+
+// Subscribe entrega aos handlers os eventos publicados nos subjects via
+// NATS core: sem durable nem ack, e cada processo inscrito recebe todos os
+// eventos (fan-out). Serve para avisos best-effort a todas as réplicas,
+// como invalidação de cache; processamento at-least-once usa o Consumer.
+// A ordem só é garantida dentro de um mesmo subject. Mensagens que não
+// decodificam e erros dos handlers só vão para o log.
+// Devolve a função que cancela as inscrições.
+func Subscribe(nc *nats.Conn, subjects []string, hs ...ports.EventHandler) (func(), error) {
+	var subs []*nats.Subscription
+	unsubscribe := func() {
+		for _, s := range subs {
+			_ = s.Unsubscribe()
+		}
+	}
+	for _, subject := range subjects {
+		s, err := nc.Subscribe(subject, func(msg *nats.Msg) {
+			ev, err := events.Decode(msg.Header, msg.Data)
+			if err != nil {
+				log.Printf("subscribe %s: %v", msg.Subject, err)
+				return
+			}
+			for _, h := range hs {
+				if err := h.Handle(context.Background(), ev); err != nil {
+					log.Printf("subscribe %s: handler %s: %v", msg.Subject, h.Name(), err)
+				}
+			}
+		})
+		if err != nil {
+			unsubscribe()
+			return nil, fmt.Errorf("subscribe %s: %w", subject, err)
+		}
+		subs = append(subs, s)
+	}
+	return unsubscribe, nil
+}
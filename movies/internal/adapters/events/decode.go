@@ -0,0 +1,99 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// ErrMalformedEvent indica mensagem que nunca vai ser decodificável
+// (poison message): não adianta retentar.
+var ErrMalformedEvent = errors.New("malformed event")
+
+// Decode reconhece os três formatos publicados (legacy, CloudEvents
+// estruturado e binário) e devolve o evento de domínio. No legacy, que não
+// tem id, usa o Nats-Msg-Id da mensagem quando houver (JetStream).
+func Decode(h nats.Header, data []byte) (domain.MovieEvent, error) {
+	var (
+		ev  domain.MovieEvent
+		raw json.RawMessage
+	)
+	switch {
+	case h.Get("ce-specversion") != "":
+		t, err := time.Parse(time.RFC3339Nano, h.Get("ce-time"))
+		if err != nil {
+			return ev, fmt.Errorf("%w: ce-time: %v", ErrMalformedEvent, err)
+		}
+		ev = domain.MovieEvent{ID: h.Get("ce-id"), Type: h.Get("ce-type"), OccurredAt: t}
+		raw = data
+	case strings.HasPrefix(h.Get("Content-Type"), ceContentType):
+		var ce struct {
+			ID   string          `json:"id"`
+			Type string          `json:"type"`
+			Time time.Time       `json:"time"`
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &ce); err != nil {
+			return ev, fmt.Errorf("%w: %v", ErrMalformedEvent, err)
+		}
+		ev = domain.MovieEvent{ID: ce.ID, Type: ce.Type, OccurredAt: ce.Time}
+		raw = ce.Data
+	default:
+		var env struct {
+			Type       string          `json:"type"`
+			OccurredAt time.Time       `json:"occurred_at"`
+			Payload    json.RawMessage `json:"payload"`
+		}
+		if err := json.Unmarshal(data, &env); err != nil {
+			return ev, fmt.Errorf("%w: %v", ErrMalformedEvent, err)
+		}
+		ev = domain.MovieEvent{ID: h.Get(jetstream.MsgIDHeader), Type: env.Type, OccurredAt: env.OccurredAt}
+		raw = env.Payload
+	}
+
+	switch ev.Type {
+	case domain.EventMovieCreated, domain.EventMovieDeleted, domain.EventMovieSnapshot:
+		var p moviePayload
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return ev, fmt.Errorf("%w: %s payload: %v", ErrMalformedEvent, ev.Type, err)
+		}
+		ev.Movie = p.toDomain()
+	case domain.EventMovieUpdated:
+		var p updatedPayload
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return ev, fmt.Errorf("%w: %s payload: %v", ErrMalformedEvent, ev.Type, err)
+		}
+		before := p.Before.toDomain()
+		ev.Movie, ev.Before = p.After.toDomain(), &before
+	default:
+		return ev, fmt.Errorf("%w: unknown event type %q", ErrMalformedEvent, ev.Type)
+	}
+	if ev.Movie.ID == "" {
+		return ev, fmt.Errorf("%w: %s without movie id", ErrMalformedEvent, ev.Type)
+	}
+	return ev, nil
+}
+
+func (p moviePayload) toDomain() domain.Movie {
+	return domain.Movie{
+		ID:               p.ID,
+		Title:            p.Title,
+		Year:             p.Year,
+		Version:          p.Version,
+		Genres:           p.Genres,
+		RuntimeMinutes:   p.RuntimeMinutes,
+		Directors:        p.Directors,
+		Cast:             p.Cast,
+		Synopsis:         p.Synopsis,
+		OriginalLanguage: p.OriginalLanguage,
+		PosterURL:        p.PosterURL,
+		OriginalTitle:    p.OriginalTitle,
+		DeletedAt:        p.DeletedAt,
+	}
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Format escolhe o envelope das mensagens publicadas.
+type Format string
+
+const (
+	// FormatLegacy é o envelope original {type, occurred_at, payload}.
+	FormatLegacy Format = "legacy"
+	// FormatCloudEvents é CloudEvents 1.0 em modo estruturado (JSON no corpo).
+	FormatCloudEvents Format = "cloudevents"
+	// FormatCloudEventsBinary é CloudEvents 1.0 em modo binário: atributos
+	// nos headers NATS (ce-*) e só o payload no corpo.
+	FormatCloudEventsBinary Format = "cloudevents-binary"
+)
+
+const (
+	ceSpecVersion   = "1.0"
+	ceSource        = "movies"
+	ceSchemaVersion = "v1"
+	ceContentType   = "application/cloudevents+json"
+	jsonContentType = "application/json"
+)
+
+// ParseFormat valida o valor vindo da configuração; vazio = legacy.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case "":
+		return FormatLegacy, nil
+	case FormatLegacy, FormatCloudEvents, FormatCloudEventsBinary:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown event format %q (want legacy, cloudevents or cloudevents-binary)", s)
+	}
+}
+
+type eventEnvelope struct {
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Payload    interface{} `json:"payload"`
+}
+
+// cloudEvent é o evento CloudEvents 1.0 em modo estruturado (JSON).
+type cloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	DataSchema      string      `json:"dataschema"`
+	Data            interface{} `json:"data"`
+}
+
+// dataSchema identifica a versão do formato do payload de cada tipo de
+// evento; mudança incompatível no payload = nova versão.
+func dataSchema(typ string) string {
+	return "urn:movies:schema:" + typ + ":" + ceSchemaVersion
+}
+
+// message monta a mensagem NATS do evento no formato f.
+func (f Format) message(subject string, meta Meta, typ string, payload interface{}) (*nats.Msg, error) {
+	msg := nats.NewMsg(subject)
+	var err error
+	switch f {
+	case FormatCloudEvents:
+		msg.Header.Set("Content-Type", ceContentType)
+		msg.Data, err = json.Marshal(cloudEvent{
+			SpecVersion:     ceSpecVersion,
+			ID:              meta.ID,
+			Source:          ceSource,
+			Type:            typ,
+			Time:            meta.OccurredAt,
+			DataContentType: jsonContentType,
+			DataSchema:      dataSchema(typ),
+			Data:            payload,
+		})
+	case FormatCloudEventsBinary:
+		msg.Header.Set("Content-Type", jsonContentType)
+		msg.Header.Set("ce-specversion", ceSpecVersion)
+		msg.Header.Set("ce-id", meta.ID)
+		msg.Header.Set("ce-source", ceSource)
+		msg.Header.Set("ce-type", typ)
+		msg.Header.Set("ce-time", meta.OccurredAt.Format(time.RFC3339Nano))
+		msg.Header.Set("ce-dataschema", dataSchema(typ))
+		msg.Data, err = json.Marshal(payload)
+	default:
+		msg.Data, err = json.Marshal(eventEnvelope{
+			Type:       typ,
+			OccurredAt: meta.OccurredAt,
+			Payload:    payload,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+var fixedMeta = Meta{ID: "evt-1", OccurredAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+func TestFormat_Legacy(t *testing.T) {
+	msg, err := FormatLegacy.message("movies.created", fixedMeta, domain.EventMovieCreated, toPayload(domain.Movie{ID: "8", Title: "Sneeze", Year: 1894, Version: 1}))
+	require.NoError(t, err)
+	require.Empty(t, msg.Header)
+	require.JSONEq(t, `{"type":"movies.created","occurred_at":"2025-01-02T03:04:05Z","payload":{"id":"8","title":"Sneeze","year":1894,"version":1}}`, string(msg.Data))
+}
+
+func TestFormat_CloudEventsStructured(t *testing.T) {
+	msg, err := FormatCloudEvents.message("movies.created", fixedMeta, domain.EventMovieCreated, toPayload(domain.Movie{ID: "8", Title: "Sneeze", Year: 1894, Version: 1}))
+	require.NoError(t, err)
+	require.Equal(t, "application/cloudevents+json", msg.Header.Get("Content-Type"))
+	require.JSONEq(t, `{
+		"specversion":"1.0",
+		"id":"evt-1",
+		"source":"movies",
+		"type":"movies.created",
+		"time":"2025-01-02T03:04:05Z",
+		"datacontenttype":"application/json",
+		"dataschema":"urn:movies:schema:movies.created:v1",
+		"data":{"id":"8","title":"Sneeze","year":1894,"version":1}
+	}`, string(msg.Data))
+}
+
+func TestFormat_CloudEventsBinary(t *testing.T) {
+	before := domain.Movie{ID: "8", Title: "Sneeze", Year: 1894, Version: 1}
+	after := domain.Movie{ID: "8", Title: "Fred Ott's Sneeze", Year: 1894, Version: 2}
+	msg, err := FormatCloudEventsBinary.message("movies.updated", fixedMeta, domain.EventMovieUpdated, toUpdatedPayload(before, after))
+	require.NoError(t, err)
+	require.Equal(t, "application/json", msg.Header.Get("Content-Type"))
+	require.Equal(t, "1.0", msg.Header.Get("ce-specversion"))
+	require.Equal(t, "evt-1", msg.Header.Get("ce-id"))
+	require.Equal(t, "movies", msg.Header.Get("ce-source"))
+	require.Equal(t, "movies.updated", msg.Header.Get("ce-type"))
+	require.Equal(t, "2025-01-02T03:04:05Z", msg.Header.Get("ce-time"))
+	require.Equal(t, "urn:movies:schema:movies.updated:v1", msg.Header.Get("ce-dataschema"))
+	require.JSONEq(t, `{"id":"8","before":{"id":"8","title":"Sneeze","year":1894,"version":1},"after":{"id":"8","title":"Fred Ott's Sneeze","year":1894,"version":2}}`, string(msg.Data))
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("")
+	require.NoError(t, err)
+	require.Equal(t, FormatLegacy, f)
+	f, err = ParseFormat("cloudevents-binary")
+	require.NoError(t, err)
+	require.Equal(t, FormatCloudEventsBinary, f)
+	_, err = ParseFormat("avro")
+	require.Error(t, err)
+}
+
+func TestNatsPublisher_CloudEventsUniqueIDs(t *testing.T) {
+	nc := runJetStream(t)
+	sub, err := nc.SubscribeSync("movies.>")
+	require.NoError(t, err)
+
+	pub := NewNatsPublisher(nc, FormatCloudEvents, testSubjects)
+	m := domain.Movie{ID: "8", Title: "Sneeze", Year: 1894}
+	require.NoError(t, pub.MovieCreated(context.Background(), m))
+	require.NoError(t, pub.MovieDeleted(context.Background(), m))
+
+	ids := map[string]bool{}
+	for _, want := range []string{domain.EventMovieCreated, domain.EventMovieDeleted} {
+		msg, err := sub.NextMsg(time.Second)
+		require.NoError(t, err)
+		var ce cloudEvent
+		require.NoError(t, json.Unmarshal(msg.Data, &ce))
+		require.Equal(t, want, ce.Type)
+		require.NotEmpty(t, ce.ID)
+		require.False(t, ce.Time.IsZero())
+		ids[ce.ID] = true
+	}
+	require.Len(t, ids, 2)
+}
+
+func TestDecode_RoundTripAllFormats(t *testing.T) {
+	before := domain.Movie{ID: "8", Title: "Sneeze", Year: 1894, Version: 1}
+	after := domain.Movie{
+		ID: "8", Title: "Fred Ott's Sneeze", Year: 1894, Version: 2,
+		Genres: []string{"Short", "Documentary"}, RuntimeMinutes: 1,
+		Directors: []string{"William K.L. Dickson"}, Cast: []string{"Fred Ott"},
+		Synopsis: "A man sneezes.", OriginalLanguage: "en", PosterURL: "https://img.example/sneeze.jpg",
+		DeletedAt: &fixedMeta.OccurredAt,
+	}
+	for _, f := range []Format{FormatLegacy, FormatCloudEvents, FormatCloudEventsBinary} {
+		t.Run(string(f), func(t *testing.T) {
+			msg, err := f.message("movies.updated", fixedMeta, domain.EventMovieUpdated, toUpdatedPayload(before, after))
+			require.NoError(t, err)
+			msg.Header.Set("Nats-Msg-Id", fixedMeta.ID) // como chega via JetStream
+
+			ev, err := Decode(msg.Header, msg.Data)
+			require.NoError(t, err)
+			require.Equal(t, domain.MovieEvent{
+				ID:         "evt-1",
+				Type:       domain.EventMovieUpdated,
+				OccurredAt: fixedMeta.OccurredAt,
+				Movie:      after,
+				Before:     &before,
+			}, ev)
+		})
+	}
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	for name, data := range map[string]string{
+		"not json":     `{`,
+		"unknown type": `{"type":"movies.renamed","occurred_at":"2025-01-02T03:04:05Z","payload":{"id":"8"}}`,
+		"no movie id":  `{"type":"movies.created","occurred_at":"2025-01-02T03:04:05Z","payload":{"title":"x"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Decode(nil, []byte(data))
+			require.ErrorIs(t, err, ErrMalformedEvent)
+		})
+	}
+}
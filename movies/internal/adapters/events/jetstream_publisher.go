@@ -0,0 +1,136 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	defaultAckTimeout  = 5 * time.Second
+	defaultDedupWindow = 2 * time.Minute
+	defaultStreamAge   = 7 * 24 * time.Hour
+)
+
+// JetStreamPublisher publica no JetStream e aguarda o PubAck (at-least-once).
+// Cada mensagem leva Nats-Msg-Id = id do evento: retentativas do mesmo
+// evento (ex.: relay do outbox) são descartadas pelo servidor dentro da
+// janela de de-duplicação do stream.
+type JetStreamPublisher struct {
+	js         jetstream.JetStream
+	format     Format
+	ackTimeout time.Duration
+	subjects   Subjects
+}
+
+// NewJetStreamPublisher garante o stream: cria se não existir ou valida que
+// o existente cobre os subjects de filmes.
+func NewJetStreamPublisher(ctx context.Context, nc *nats.Conn, format Format, stream string, subjects Subjects) (ports.EventPublisher, error) {
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("jetstream: %w", err)
+	}
+	if err := EnsureStream(ctx, js, stream, subjects.all()); err != nil {
+		return nil, err
+	}
+	return &JetStreamPublisher{
+		js:         js,
+		format:     format,
+		ackTimeout: defaultAckTimeout,
+		subjects:   subjects,
+	}, nil
+}
+
+// EnsureStream cria o stream (file storage, dedup de 2min, retenção de 7
+// dias) ou, se já existir, confere que ele captura todos os subjects.
+func EnsureStream(ctx context.Context, js jetstream.JetStream, name string, subjects []string) error {
+	s, err := js.Stream(ctx, name)
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		_, err = js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:       name,
+			Subjects:   subjects,
+			Storage:    jetstream.FileStorage,
+			Duplicates: defaultDedupWindow,
+			MaxAge:     defaultStreamAge,
+		})
+		if err != nil {
+			return fmt.Errorf("create stream %s: %w", name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("lookup stream %s: %w", name, err)
+	}
+
+	cfg := s.CachedInfo().Config
+	for _, subj := range subjects {
+		if !coveredBy(subj, cfg.Subjects) {
+			return fmt.Errorf("stream %s does not capture subject %q (subjects=%v)", name, subj, cfg.Subjects)
+		}
+	}
+	return nil
+}
+
+// coveredBy diz se subject casa com algum filtro (suporta "*" e ">").
+func coveredBy(subject string, filters []string) bool {
+	st := strings.Split(subject, ".")
+	for _, f := range filters {
+		ft := strings.Split(f, ".")
+		match := true
+		for i, tok := range ft {
+			if tok == ">" {
+				match = i < len(st)
+				break
+			}
+			if i >= len(st) || (tok != "*" && tok != st[i]) {
+				match = false
+				break
+			}
+			if i == len(ft)-1 && len(ft) != len(st) {
+				match = false
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *JetStreamPublisher) publish(ctx context.Context, subject, typ string, payload interface{}) error {
+	meta := metaFrom(ctx)
+	msg, err := p.format.message(subject, meta, typ, payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.ackTimeout)
+	defer cancel()
+	if _, err := p.js.PublishMsg(ctx, msg, jetstream.WithMsgID(meta.ID)); err != nil {
+		return fmt.Errorf("jetstream publish %s: %w", subject, err)
+	}
+	return nil
+}
+
+func (p *JetStreamPublisher) MovieCreated(ctx context.Context, m domain.Movie) error {
+	return p.publish(ctx, p.subjects.Created, domain.EventMovieCreated, toPayload(m))
+}
+
+func (p *JetStreamPublisher) MovieUpdated(ctx context.Context, before, after domain.Movie) error {
+	return p.publish(ctx, p.subjects.Updated, domain.EventMovieUpdated, toUpdatedPayload(before, after))
+}
+
+func (p *JetStreamPublisher) MovieDeleted(ctx context.Context, m domain.Movie) error {
+	return p.publish(ctx, p.subjects.Deleted, domain.EventMovieDeleted, toPayload(m))
+}
+
+func (p *JetStreamPublisher) MovieSnapshot(ctx context.Context, m domain.Movie) error {
+	return p.publish(ctx, p.subjects.Snapshot, domain.EventMovieSnapshot, toPayload(m))
+}
@@ -0,0 +1,93 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/require"
+)
+
+// runJetStream sobe um nats-server embutido com JetStream para o teste.
+func runJetStream(t *testing.T) *nats.Conn {
+	t.Helper()
+	ns, err := server.NewServer(&server.Options{Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	require.NoError(t, err)
+	ns.Start()
+	t.Cleanup(ns.Shutdown)
+	require.True(t, ns.ReadyForConnections(5*time.Second))
+
+	nc, err := nats.Connect(ns.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+var testSubjects = Subjects{Created: "movies.created", Updated: "movies.updated", Deleted: "movies.deleted", Snapshot: "movies.snapshot"}
+
+func TestJetStreamPublisher_DedupByEventID(t *testing.T) {
+	nc := runJetStream(t)
+	ctx := context.Background()
+
+	pub, err := NewJetStreamPublisher(ctx, nc, FormatLegacy, "MOVIES", testSubjects)
+	require.NoError(t, err)
+
+	m := domain.Movie{ID: "8", Title: "Sneeze", Year: 1894}
+	evCtx := WithMeta(ctx, Meta{ID: "evt-1", OccurredAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, pub.MovieCreated(evCtx, m))
+	require.NoError(t, pub.MovieCreated(evCtx, m)) // retry do mesmo evento
+	require.NoError(t, pub.MovieDeleted(ctx, m))   // sem Meta: id novo
+
+	js, err := jetstream.New(nc)
+	require.NoError(t, err)
+	s, err := js.Stream(ctx, "MOVIES")
+	require.NoError(t, err)
+	info, err := s.Info(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), info.State.Msgs)
+
+	raw, err := s.GetMsg(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "evt-1", raw.Header.Get(jetstream.MsgIDHeader))
+	var env struct {
+		Type       string       `json:"type"`
+		OccurredAt time.Time    `json:"occurred_at"`
+		Payload    moviePayload `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(raw.Data, &env))
+	require.Equal(t, domain.EventMovieCreated, env.Type)
+	require.Equal(t, "Sneeze", env.Payload.Title)
+	require.True(t, env.OccurredAt.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestJetStreamPublisher_ValidatesExistingStream(t *testing.T) {
+	nc := runJetStream(t)
+	ctx := context.Background()
+
+	js, err := jetstream.New(nc)
+	require.NoError(t, err)
+	_, err = js.CreateStream(ctx, jetstream.StreamConfig{Name: "MOVIES", Subjects: []string{"movies.created"}})
+	require.NoError(t, err)
+
+	_, err = NewJetStreamPublisher(ctx, nc, FormatLegacy, "MOVIES", testSubjects)
+	require.Error(t, err)
+
+	_, err = js.UpdateStream(ctx, jetstream.StreamConfig{Name: "MOVIES", Subjects: []string{"movies.>"}})
+	require.NoError(t, err)
+	_, err = NewJetStreamPublisher(ctx, nc, FormatLegacy, "MOVIES", testSubjects)
+	require.NoError(t, err)
+}
+
+func TestCoveredBy(t *testing.T) {
+	require.True(t, coveredBy("movies.created", []string{"movies.>"}))
+	require.True(t, coveredBy("movies.created", []string{"other", "movies.*"}))
+	require.True(t, coveredBy("movies.created", []string{"movies.created"}))
+	require.False(t, coveredBy("movies.created", []string{"movies"}))
+	require.False(t, coveredBy("movies.created.x", []string{"movies.*"}))
+	require.False(t, coveredBy("movies", []string{"movies.>"}))
+}
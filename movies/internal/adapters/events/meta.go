@@ -0,0 +1,35 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nuid"
+)
+
+// Meta identifica uma ocorrência de evento. Quem publica a partir de um
+// registro persistido (ex.: relay do outbox) injeta Meta no ctx para que
+// id e horário sejam estáveis entre retentativas.
+type Meta struct {
+	ID         string
+	OccurredAt time.Time
+}
+
+type metaKey struct{}
+
+func WithMeta(ctx context.Context, m Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, m)
+}
+
+// metaFrom devolve a Meta do ctx, completando id (único) e OccurredAt (agora)
+// quando ausentes.
+func metaFrom(ctx context.Context) Meta {
+	m, _ := ctx.Value(metaKey{}).(Meta)
+	if m.ID == "" {
+		m.ID = nuid.Next()
+	}
+	if m.OccurredAt.IsZero() {
+		m.OccurredAt = time.Now().UTC()
+	}
+	return m
+}
@@ -2,7 +2,6 @@ package events
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
@@ -10,44 +9,102 @@ import (
 	"github.com/nats-io/nats.go"
 )
 
+// Subjects NATS de cada tipo de evento.
+type Subjects struct {
+	Created  string
+	Updated  string
+	Deleted  string
+	Snapshot string
+}
+
+func (s Subjects) all() []string {
+	return []string{s.Created, s.Updated, s.Deleted, s.Snapshot}
+}
+
 type NatsPublisher struct {
-	nc             *nats.Conn
-	subjectCreated string
-	subjectDeleted string
+	nc       *nats.Conn
+	format   Format
+	subjects Subjects
 }
 
-func NewNatsPublisher(nc *nats.Conn, subjCreated, subjDeleted string) ports.EventPublisher {
-	return &NatsPublisher{nc: nc, subjectCreated: subjCreated, subjectDeleted: subjDeleted}
+func NewNatsPublisher(nc *nats.Conn, format Format, subjects Subjects) ports.EventPublisher {
+	return &NatsPublisher{nc: nc, format: format, subjects: subjects}
 }
 
-type eventEnvelope struct {
-	Type       string      `json:"type"`
-	OccurredAt time.Time   `json:"occurred_at"`
-	Payload    interface{} `json:"payload"`
+// moviePayload snapshot do filme publicado nos eventos. Os campos
+// opcionais são omitidos quando vazios (consumidores antigos só leem
+// id/title/year). version é a do filme depois da escrita: consumidores a
+// usam para ordenar eventos do mesmo id e descartar os atrasados.
+type moviePayload struct {
+	ID               string   `json:"id"`
+	Title            string   `json:"title"`
+	Year             int      `json:"year"`
+	Version          int64    `json:"version"`
+	Genres           []string `json:"genres,omitempty"`
+	RuntimeMinutes   int      `json:"runtime_minutes,omitempty"`
+	Directors        []string `json:"directors,omitempty"`
+	Cast             []string `json:"cast,omitempty"`
+	Synopsis         string   `json:"synopsis,omitempty"`
+	OriginalLanguage string   `json:"original_language,omitempty"`
+	PosterURL        string   `json:"poster_url,omitempty"`
+	OriginalTitle    string   `json:"original_title,omitempty"`
+	// DeletedAt só em filmes na lixeira (movies.deleted do Delete e do
+	// purge).
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
-func (p *NatsPublisher) MovieCreated(ctx context.Context, m domain.Movie) error {
-	ev := eventEnvelope{
-		Type:       "movies.created",
-		OccurredAt: time.Now().UTC(),
-		Payload: struct {
-			ID    string `json:"id"`
-			Title string `json:"title"`
-			Year  int    `json:"year"`
-		}{ID: m.ID, Title: m.Title, Year: m.Year},
+func toPayload(m domain.Movie) moviePayload {
+	return moviePayload{
+		ID:               m.ID,
+		Title:            m.Title,
+		Year:             m.Year,
+		Version:          m.Version,
+		Genres:           m.Genres,
+		RuntimeMinutes:   m.RuntimeMinutes,
+		Directors:        m.Directors,
+		Cast:             m.Cast,
+		Synopsis:         m.Synopsis,
+		OriginalLanguage: m.OriginalLanguage,
+		PosterURL:        m.PosterURL,
+		OriginalTitle:    m.OriginalTitle,
+		DeletedAt:        m.DeletedAt,
 	}
-	b, _ := json.Marshal(ev)
-	return p.nc.Publish(p.subjectCreated, b)
 }
 
-func (p *NatsPublisher) MovieDeleted(ctx context.Context, id string) error {
-	ev := eventEnvelope{
-		Type:       "movies.deleted",
-		OccurredAt: time.Now().UTC(),
-		Payload: struct {
-			ID string `json:"id"`
-		}{ID: id},
+// updatedPayload carrega os snapshots antes/depois de movies.updated.
+type updatedPayload struct {
+	ID     string       `json:"id"`
+	Before moviePayload `json:"before"`
+	After  moviePayload `json:"after"`
+}
+
+func toUpdatedPayload(before, after domain.Movie) updatedPayload {
+	return updatedPayload{ID: after.ID, Before: toPayload(before), After: toPayload(after)}
+}
+
+func (p *NatsPublisher) publish(ctx context.Context, subject, typ string, payload interface{}) error {
+	msg, err := p.format.message(subject, metaFrom(ctx), typ, payload)
+	if err != nil {
+		return err
 	}
-	b, _ := json.Marshal(ev)
-	return p.nc.Publish(p.subjectDeleted, b)
+	return p.nc.PublishMsg(msg)
+}
+
+func (p *NatsPublisher) MovieCreated(ctx context.Context, m domain.Movie) error {
+	return p.publish(ctx, p.subjects.Created, domain.EventMovieCreated, toPayload(m))
+}
+
+func (p *NatsPublisher) MovieUpdated(ctx context.Context, before, after domain.Movie) error {
+	return p.publish(ctx, p.subjects.Updated, domain.EventMovieUpdated, toUpdatedPayload(before, after))
+}
+
+// MovieDeleted mantém "id" no topo do payload (compatível com o formato
+// anterior) e acrescenta o snapshot completo do filme removido.
+func (p *NatsPublisher) MovieDeleted(ctx context.Context, m domain.Movie) error {
+	return p.publish(ctx, p.subjects.Deleted, domain.EventMovieDeleted, toPayload(m))
+}
+
+// MovieSnapshot republica o estado atual de um filme (replay/backfill).
+func (p *NatsPublisher) MovieSnapshot(ctx context.Context, m domain.Movie) error {
+	return p.publish(ctx, p.subjects.Snapshot, domain.EventMovieSnapshot, toPayload(m))
 }
@@ -11,5 +11,7 @@ type NoopPublisher struct{}
 
 func NewNoopPublisher() ports.EventPublisher { return &NoopPublisher{} }
 
-func (NoopPublisher) MovieCreated(ctx context.Context, m domain.Movie) error { return nil }
-func (NoopPublisher) MovieDeleted(ctx context.Context, id string) error      { return nil }
+func (NoopPublisher) MovieCreated(ctx context.Context, m domain.Movie) error             { return nil }
+func (NoopPublisher) MovieUpdated(ctx context.Context, before, after domain.Movie) error { return nil }
+func (NoopPublisher) MovieDeleted(ctx context.Context, m domain.Movie) error             { return nil }
+func (NoopPublisher) MovieSnapshot(ctx context.Context, m domain.Movie) error            { return nil }
@@ -2,16 +2,21 @@ package grpcserver
 
 import (
 	"context"
+	"errors"
 	"net"
+	"strings"
+	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
 	moviespb "github.com/caiqueborghese/sipubtech-challenge/proto/moviespb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Server struct {
@@ -23,36 +28,196 @@ func New(svc ports.MovieService) *Server { return &Server{svc: svc} }
 
 func toPB(m domain.Movie) *moviespb.Movie {
 	return &moviespb.Movie{
-		Id:    m.ID,
-		Title: m.Title,
-		Year:  int32(m.Year),
+		Id:               m.ID,
+		Title:            m.Title,
+		Year:             int32(m.Year),
+		Genres:           m.Genres,
+		RuntimeMinutes:   int32(m.RuntimeMinutes),
+		Directors:        m.Directors,
+		Cast:             m.Cast,
+		Synopsis:         m.Synopsis,
+		OriginalLanguage: m.OriginalLanguage,
+		PosterUrl:        m.PosterURL,
+		OriginalTitle:    m.OriginalTitle,
+		Version:          m.Version,
+		DeletedAt:        timestampOrNil(m.DeletedAt),
 	}
 }
 
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func fromPB(m *moviespb.Movie) domain.Movie {
+	return domain.Movie{
+		Title:            m.GetTitle(),
+		Year:             int(m.GetYear()),
+		Genres:           m.GetGenres(),
+		RuntimeMinutes:   int(m.GetRuntimeMinutes()),
+		Directors:        m.GetDirectors(),
+		Cast:             m.GetCast(),
+		Synopsis:         m.GetSynopsis(),
+		OriginalLanguage: m.GetOriginalLanguage(),
+		PosterURL:        m.GetPosterUrl(),
+	}
+}
+
+// invalidArgs erros de argumento e o campo da requisição a que se referem.
+var invalidArgs = []struct {
+	err   error
+	field string
+}{
+	{domain.ErrInvalidID, "id"},
+	{domain.ErrInvalidPageToken, "page_token"},
+	{domain.ErrInvalidSortField, "sort_by"},
+	{domain.ErrInvalidSortOrder, "sort_order"},
+	{domain.ErrInvalidYearRange, "min_year"},
+	{domain.ErrInvalidUpdateMask, "update_mask"},
+	{domain.ErrInvalidResumeToken, "resume_token"},
+	{domain.ErrInvalidQuery, "query"},
+	{domain.ErrInvalidPrefix, "prefix"},
+}
+
+// toStatusErr traduz erros de domínio em status gRPC. Validação e conflito
+// levam errdetails.BadRequest com uma violação por campo; conflito leva
+// também ErrorInfo com o id do filme existente (e trashed=true se ele está
+// na lixeira).
 func toStatusErr(err error) error {
-	switch err {
-	case nil:
+	if err == nil {
 		return nil
-	case domain.ErrNotFound:
+	}
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
 		return status.Error(codes.NotFound, err.Error())
-	case domain.ErrInvalidID:
-		return status.Error(codes.InvalidArgument, err.Error())
-	default:
-		// validações de domínio diversas
-		return status.Error(codes.Unknown, err.Error())
+	case errors.Is(err, domain.ErrValidation):
+		return withDetails(codes.InvalidArgument, err, badRequest(domain.Violations(err)))
+	case errors.Is(err, domain.ErrAlreadyExists):
+		details := []protoadapt.MessageV1{badRequest(domain.Violations(err))}
+		var ce *domain.ConflictError
+		if errors.As(err, &ce) && ce.ConflictingID != "" {
+			md := map[string]string{"conflicting_id": ce.ConflictingID}
+			if ce.Trashed {
+				md["trashed"] = "true" // RestoreMovie em vez de criar de novo
+			}
+			details = append(details, &errdetails.ErrorInfo{
+				Reason:   "MOVIE_ALREADY_EXISTS",
+				Domain:   errorDomain,
+				Metadata: md,
+			})
+		}
+		return withDetails(codes.AlreadyExists, err, details...)
+	case errors.Is(err, domain.ErrVersionRequired):
+		return withDetails(codes.InvalidArgument, err, badRequest([]domain.FieldViolation{
+			{Field: "expected_version", Constraint: domain.ConstraintRequired, Description: err.Error()},
+		}))
+	case errors.Is(err, domain.ErrVersionMismatch):
+		return withDetails(codes.Aborted, err, &errdetails.ErrorInfo{
+			Reason: "MOVIE_VERSION_MISMATCH",
+			Domain: errorDomain,
+		})
+	case errors.Is(err, domain.ErrWatchUnavailable), errors.Is(err, domain.ErrSuggestUnavailable):
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	for _, ia := range invalidArgs {
+		if errors.Is(err, ia.err) {
+			return withDetails(codes.InvalidArgument, err, badRequest([]domain.FieldViolation{
+				{Field: ia.field, Constraint: domain.ConstraintInvalid, Description: err.Error()},
+			}))
+		}
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// errorDomain identifica o serviço em errdetails.ErrorInfo.
+const errorDomain = "movies.sipubtech"
+
+func badRequest(vs []domain.FieldViolation) *errdetails.BadRequest {
+	br := &errdetails.BadRequest{}
+	for _, v := range vs {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+			Reason:      strings.ToUpper(v.Constraint),
+		})
+	}
+	return br
+}
+
+func withDetails(c codes.Code, err error, details ...protoadapt.MessageV1) error {
+	st, derr := status.New(c, err.Error()).WithDetails(details...)
+	if derr != nil {
+		return status.Error(c, err.Error())
 	}
+	return st.Err()
 }
 
-func (s *Server) ListMovies(ctx context.Context, _ *emptypb.Empty) (*moviespb.ListMoviesResponse, error) {
-	ms, err := s.svc.List(ctx)
+func listOptions(in *moviespb.ListMoviesRequest) domain.ListOptions {
+	return domain.ListOptions{
+		PageSize:    int(in.GetPageSize()),
+		PageToken:   in.GetPageToken(),
+		MinYear:     int(in.GetMinYear()),
+		MaxYear:     int(in.GetMaxYear()),
+		TitlePrefix: in.GetTitlePrefix(),
+		SortBy:      in.GetSortBy(),
+		SortOrder:   in.GetSortOrder(),
+	}
+}
+
+func toListResponse(page domain.MoviePage) *moviespb.ListMoviesResponse {
+	out := make([]*moviespb.Movie, 0, len(page.Movies))
+	for _, m := range page.Movies {
+		out = append(out, toPB(m))
+	}
+	return &moviespb.ListMoviesResponse{Movies: out, NextPageToken: page.NextPageToken}
+}
+
+func (s *Server) ListMovies(ctx context.Context, in *moviespb.ListMoviesRequest) (*moviespb.ListMoviesResponse, error) {
+	page, err := s.svc.List(ctx, listOptions(in))
 	if err != nil {
 		return nil, toStatusErr(err)
 	}
-	out := make([]*moviespb.Movie, 0, len(ms))
-	for _, m := range ms {
-		out = append(out, toPB(m))
+	return toListResponse(page), nil
+}
+
+func (s *Server) ListDeletedMovies(ctx context.Context, in *moviespb.ListMoviesRequest) (*moviespb.ListMoviesResponse, error) {
+	page, err := s.svc.ListDeleted(ctx, listOptions(in))
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+	return toListResponse(page), nil
+}
+
+func (s *Server) SearchMovies(ctx context.Context, in *moviespb.SearchMoviesRequest) (*moviespb.SearchMoviesResponse, error) {
+	page, err := s.svc.Search(ctx, domain.SearchOptions{
+		Query:     in.GetQuery(),
+		PageSize:  int(in.GetPageSize()),
+		PageToken: in.GetPageToken(),
+		MinYear:   int(in.GetMinYear()),
+		MaxYear:   int(in.GetMaxYear()),
+	})
+	if err != nil {
+		return nil, toStatusErr(err)
 	}
-	return &moviespb.ListMoviesResponse{Movies: out}, nil
+	out := make([]*moviespb.SearchResult, 0, len(page.Hits))
+	for _, h := range page.Hits {
+		out = append(out, &moviespb.SearchResult{Movie: toPB(h.Movie), Score: h.Score})
+	}
+	return &moviespb.SearchMoviesResponse{Results: out, NextPageToken: page.NextPageToken}, nil
+}
+
+func (s *Server) SuggestTitles(ctx context.Context, in *moviespb.SuggestTitlesRequest) (*moviespb.SuggestTitlesResponse, error) {
+	ss, err := s.svc.SuggestTitles(ctx, domain.SuggestOptions{Prefix: in.GetPrefix(), Limit: int(in.GetLimit())})
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+	out := make([]*moviespb.TitleSuggestion, 0, len(ss))
+	for _, t := range ss {
+		out = append(out, &moviespb.TitleSuggestion{Id: t.ID, Title: t.Title, Year: int32(t.Year)})
+	}
+	return &moviespb.SuggestTitlesResponse{Suggestions: out}, nil
 }
 
 func (s *Server) GetMovie(ctx context.Context, in *moviespb.GetMovieRequest) (*moviespb.GetMovieResponse, error) {
@@ -63,10 +228,25 @@ func (s *Server) GetMovie(ctx context.Context, in *moviespb.GetMovieRequest) (*m
 	return &moviespb.GetMovieResponse{Movie: toPB(*m)}, nil
 }
 
+func (s *Server) GetDeletedMovie(ctx context.Context, in *moviespb.GetMovieRequest) (*moviespb.GetMovieResponse, error) {
+	m, err := s.svc.GetDeleted(ctx, in.GetId())
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+	return &moviespb.GetMovieResponse{Movie: toPB(*m)}, nil
+}
+
 func (s *Server) CreateMovie(ctx context.Context, in *moviespb.CreateMovieRequest) (*moviespb.CreateMovieResponse, error) {
 	n := domain.Movie{
-		Title: in.GetTitle(),
-		Year:  int(in.GetYear()),
+		Title:            in.GetTitle(),
+		Year:             int(in.GetYear()),
+		Genres:           in.GetGenres(),
+		RuntimeMinutes:   int(in.GetRuntimeMinutes()),
+		Directors:        in.GetDirectors(),
+		Cast:             in.GetCast(),
+		Synopsis:         in.GetSynopsis(),
+		OriginalLanguage: in.GetOriginalLanguage(),
+		PosterURL:        in.GetPosterUrl(),
 	}
 	created, err := s.svc.Create(ctx, n)
 	if err != nil {
@@ -75,11 +255,66 @@ func (s *Server) CreateMovie(ctx context.Context, in *moviespb.CreateMovieReques
 	return &moviespb.CreateMovieResponse{Movie: toPB(*created)}, nil
 }
 
+// requireVersion: toda alteração pela API confere a versão que o cliente
+// leu; expected_version ausente (0) é erro, não "grava às cegas".
+func requireVersion(v int64) error {
+	if v == domain.AnyVersion {
+		return toStatusErr(domain.ErrVersionRequired)
+	}
+	return nil
+}
+
+func (s *Server) UpdateMovie(ctx context.Context, in *moviespb.UpdateMovieRequest) (*moviespb.UpdateMovieResponse, error) {
+	if err := requireVersion(in.GetExpectedVersion()); err != nil {
+		return nil, err
+	}
+	updated, err := s.svc.Update(ctx, in.GetId(), fromPB(in.GetMovie()), in.GetUpdateMask().GetPaths(), in.GetExpectedVersion())
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+	return &moviespb.UpdateMovieResponse{Movie: toPB(*updated)}, nil
+}
+
 func (s *Server) DeleteMovie(ctx context.Context, in *moviespb.DeleteMovieRequest) (*moviespb.DeleteMovieResponse, error) {
-	if err := s.svc.Delete(ctx, in.GetId()); err != nil {
+	if err := requireVersion(in.GetExpectedVersion()); err != nil {
+		return nil, err
+	}
+	deleted, err := s.svc.Delete(ctx, in.GetId(), in.GetExpectedVersion())
+	if err != nil {
 		return nil, toStatusErr(err)
 	}
-	return &moviespb.DeleteMovieResponse{Success: true}, nil
+	return &moviespb.DeleteMovieResponse{Success: true, Movie: toPB(*deleted)}, nil
+}
+
+func (s *Server) RestoreMovie(ctx context.Context, in *moviespb.RestoreMovieRequest) (*moviespb.RestoreMovieResponse, error) {
+	if err := requireVersion(in.GetExpectedVersion()); err != nil {
+		return nil, err
+	}
+	restored, err := s.svc.Restore(ctx, in.GetId(), in.GetExpectedVersion())
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+	return &moviespb.RestoreMovieResponse{Movie: toPB(*restored)}, nil
+}
+
+var changeTypesPB = map[string]moviespb.MovieChange_Type{
+	domain.EventMovieCreated: moviespb.MovieChange_CREATED,
+	domain.EventMovieUpdated: moviespb.MovieChange_UPDATED,
+	domain.EventMovieDeleted: moviespb.MovieChange_DELETED,
+}
+
+func (s *Server) WatchMovies(in *moviespb.WatchMoviesRequest, stream moviespb.MovieService_WatchMoviesServer) error {
+	err := s.svc.Watch(stream.Context(), in.GetResumeToken(), func(c domain.MovieChange) error {
+		return stream.Send(&moviespb.MovieChange{
+			Type:        changeTypesPB[c.Type],
+			Movie:       toPB(c.Movie),
+			ResumeToken: c.ResumeToken,
+		})
+	})
+	if _, ok := status.FromError(err); ok {
+		return err // nil ou erro do próprio stream
+	}
+	return toStatusErr(err)
 }
 
 func RunGRPCServer(svc ports.MovieService, grpcAddr string) error {
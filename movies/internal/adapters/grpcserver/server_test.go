@@ -2,31 +2,123 @@ package grpcserver
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
 	moviespb "github.com/caiqueborghese/sipubtech-challenge/proto/moviespb"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 type fakeSvc struct{}
 
-func (f fakeSvc) List(ctx context.Context) ([]domain.Movie, error) {
-	return []domain.Movie{{ID: "8", Title: "X", Year: 2000}}, nil
+var trashedAt = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func (f fakeSvc) List(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error) {
+	return domain.MoviePage{
+		Movies:        []domain.Movie{{ID: "8", Title: "X", Year: 2000}},
+		NextPageToken: "next",
+	}, nil
+}
+func (f fakeSvc) SuggestTitles(ctx context.Context, opts domain.SuggestOptions) ([]domain.TitleSuggestion, error) {
+	opts.Normalize()
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return []domain.TitleSuggestion{{ID: "4", Title: "Lumière and Company", Year: 1995}}, nil
 }
 func (f fakeSvc) Get(ctx context.Context, id string) (*domain.Movie, error) {
 	return &domain.Movie{ID: id, Title: "One", Year: 1999}, nil
 }
 func (f fakeSvc) Create(ctx context.Context, m domain.Movie) (*domain.Movie, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	switch m.Title {
+	case "Dup":
+		return nil, &domain.ConflictError{Fields: []string{domain.FieldTitle, domain.FieldYear}, ConflictingID: "8"}
+	case "Trashed":
+		return nil, &domain.ConflictError{Fields: []string{domain.FieldTitle, domain.FieldYear}, ConflictingID: "7", Trashed: true}
+	}
 	m.ID = "new"
 	return &m, nil
 }
-func (f fakeSvc) Delete(ctx context.Context, id string) error { return nil }
+func (f fakeSvc) Update(ctx context.Context, id string, m domain.Movie, fields []string, expectedVersion int64) (*domain.Movie, error) {
+	if id == "dup" {
+		return nil, domain.ErrAlreadyExists
+	}
+	cur := domain.Movie{ID: id, Title: "One", Year: 1999, Version: 3}
+	if expectedVersion != domain.AnyVersion && expectedVersion != cur.Version {
+		return nil, domain.ErrVersionMismatch
+	}
+	if err := cur.ApplyUpdate(m, fields); err != nil {
+		return nil, err
+	}
+	cur.Version++
+	return &cur, nil
+}
+func (f fakeSvc) Delete(ctx context.Context, id string, expectedVersion int64) (*domain.Movie, error) {
+	if expectedVersion != domain.AnyVersion && expectedVersion != 3 {
+		return nil, domain.ErrVersionMismatch
+	}
+	return &domain.Movie{ID: id, Title: "One", Year: 1999, Version: 4, DeletedAt: &trashedAt}, nil
+}
+func (f fakeSvc) Search(ctx context.Context, opts domain.SearchOptions) (domain.SearchPage, error) {
+	opts.Normalize()
+	if err := opts.Validate(); err != nil {
+		return domain.SearchPage{}, err
+	}
+	return domain.SearchPage{
+		Hits:          []domain.SearchHit{{Movie: domain.Movie{ID: "8", Title: opts.Query, Year: 1903}, Score: 1.5}},
+		NextPageToken: "next",
+	}, nil
+}
+func (f fakeSvc) Watch(ctx context.Context, resumeToken string, fn func(domain.MovieChange) error) error {
+	if resumeToken == "stale" {
+		return domain.ErrInvalidResumeToken
+	}
+	for _, c := range []domain.MovieChange{
+		{Type: domain.EventMovieCreated, Movie: domain.Movie{ID: "9", Title: "New", Year: 2001}, ResumeToken: "1"},
+		{Type: domain.EventMovieDeleted, Movie: domain.Movie{ID: "8"}, ResumeToken: "2"},
+	} {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (f fakeSvc) ListDeleted(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error) {
+	return domain.MoviePage{Movies: []domain.Movie{{ID: "7", Title: "Gone", Year: 1990, Version: 2, DeletedAt: &trashedAt}}}, nil
+}
+func (f fakeSvc) GetDeleted(ctx context.Context, id string) (*domain.Movie, error) {
+	if id != "7" {
+		return nil, domain.ErrNotFound
+	}
+	return &domain.Movie{ID: "7", Title: "Gone", Year: 1990, Version: 2, DeletedAt: &trashedAt}, nil
+}
+func (f fakeSvc) Restore(ctx context.Context, id string, expectedVersion int64) (*domain.Movie, error) {
+	if id != "7" {
+		return nil, domain.ErrNotFound
+	}
+	if expectedVersion != domain.AnyVersion && expectedVersion != 2 {
+		return nil, domain.ErrVersionMismatch
+	}
+	return &domain.Movie{ID: id, Title: "Gone", Year: 1990, Version: 3}, nil
+}
+func (f fakeSvc) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	return 0, nil
+}
 func (f fakeSvc) EnsureSeed(ctx context.Context, seed []domain.Movie) (int, error) {
 	return 0, nil
 }
@@ -56,9 +148,277 @@ func TestListMovies_Bufconn(t *testing.T) {
 	defer cleanup()
 
 	cli := moviespb.NewMovieServiceClient(conn)
-	resp, err := cli.ListMovies(context.Background(), &emptypb.Empty{})
+	resp, err := cli.ListMovies(context.Background(), &moviespb.ListMoviesRequest{PageSize: 1})
 	require.NoError(t, err)
 	require.Len(t, resp.GetMovies(), 1)
 	require.Equal(t, "8", resp.GetMovies()[0].GetId())
 	require.Equal(t, int32(2000), resp.GetMovies()[0].GetYear())
+	require.Equal(t, "next", resp.GetNextPageToken())
+}
+
+func TestSearchMovies_Bufconn(t *testing.T) {
+	s := grpc.NewServer()
+	moviespb.RegisterMovieServiceServer(s, New(fakeSvc{}))
+
+	conn, cleanup, err := dialBuf(s)
+	require.NoError(t, err)
+	defer cleanup()
+
+	cli := moviespb.NewMovieServiceClient(conn)
+	resp, err := cli.SearchMovies(context.Background(), &moviespb.SearchMoviesRequest{Query: "  train   robbery "})
+	require.NoError(t, err)
+	require.Len(t, resp.GetResults(), 1)
+	require.Equal(t, "train robbery", resp.GetResults()[0].GetMovie().GetTitle())
+	require.Equal(t, 1.5, resp.GetResults()[0].GetScore())
+	require.Equal(t, "next", resp.GetNextPageToken())
+
+	_, err = cli.SearchMovies(context.Background(), &moviespb.SearchMoviesRequest{Query: " "})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, map[string]string{"query": "INVALID"}, fieldViolations(t, err))
+}
+
+func TestSuggestTitles_Bufconn(t *testing.T) {
+	s := grpc.NewServer()
+	moviespb.RegisterMovieServiceServer(s, New(fakeSvc{}))
+
+	conn, cleanup, err := dialBuf(s)
+	require.NoError(t, err)
+	defer cleanup()
+
+	cli := moviespb.NewMovieServiceClient(conn)
+	resp, err := cli.SuggestTitles(context.Background(), &moviespb.SuggestTitlesRequest{Prefix: "lu"})
+	require.NoError(t, err)
+	require.Len(t, resp.GetSuggestions(), 1)
+	require.Equal(t, "4", resp.GetSuggestions()[0].GetId())
+	require.Equal(t, int32(1995), resp.GetSuggestions()[0].GetYear())
+
+	_, err = cli.SuggestTitles(context.Background(), &moviespb.SuggestTitlesRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, map[string]string{"prefix": "INVALID"}, fieldViolations(t, err))
+}
+
+func TestUpdateMovie_Bufconn(t *testing.T) {
+	s := grpc.NewServer()
+	moviespb.RegisterMovieServiceServer(s, New(fakeSvc{}))
+
+	conn, cleanup, err := dialBuf(s)
+	require.NoError(t, err)
+	defer cleanup()
+
+	cli := moviespb.NewMovieServiceClient(conn)
+	resp, err := cli.UpdateMovie(context.Background(), &moviespb.UpdateMovieRequest{
+		Id:              "8",
+		Movie:           &moviespb.Movie{Title: "Two", Year: 1},
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+		ExpectedVersion: 3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Two", resp.GetMovie().GetTitle())
+	require.Equal(t, int32(1999), resp.GetMovie().GetYear())
+	require.Equal(t, int64(4), resp.GetMovie().GetVersion())
+
+	_, err = cli.UpdateMovie(context.Background(), &moviespb.UpdateMovieRequest{
+		Id:              "8",
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"rating"}},
+		ExpectedVersion: 3,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = cli.UpdateMovie(context.Background(), &moviespb.UpdateMovieRequest{Id: "dup", ExpectedVersion: 3})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestExpectedVersion_Bufconn(t *testing.T) {
+	s := grpc.NewServer()
+	moviespb.RegisterMovieServiceServer(s, New(fakeSvc{}))
+
+	conn, cleanup, err := dialBuf(s)
+	require.NoError(t, err)
+	defer cleanup()
+	cli := moviespb.NewMovieServiceClient(conn)
+	ctx := context.Background()
+
+	_, err = cli.UpdateMovie(ctx, &moviespb.UpdateMovieRequest{
+		Id:              "8",
+		Movie:           &moviespb.Movie{Title: "Two"},
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+		ExpectedVersion: 3,
+	})
+	require.NoError(t, err)
+
+	_, err = cli.UpdateMovie(ctx, &moviespb.UpdateMovieRequest{Id: "8", ExpectedVersion: 2})
+	require.Equal(t, codes.Aborted, status.Code(err))
+	var info *errdetails.ErrorInfo
+	for _, d := range status.Convert(err).Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			info = ei
+		}
+	}
+	require.NotNil(t, info)
+	require.Equal(t, "MOVIE_VERSION_MISMATCH", info.GetReason())
+
+	_, err = cli.DeleteMovie(ctx, &moviespb.DeleteMovieRequest{Id: "8", ExpectedVersion: 2})
+	require.Equal(t, codes.Aborted, status.Code(err))
+	deleted, err := cli.DeleteMovie(ctx, &moviespb.DeleteMovieRequest{Id: "8", ExpectedVersion: 3})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), deleted.GetMovie().GetVersion())
+	require.Equal(t, trashedAt, deleted.GetMovie().GetDeletedAt().AsTime())
+
+	// sem expected_version nenhuma alteração chega ao serviço
+	_, err = cli.UpdateMovie(ctx, &moviespb.UpdateMovieRequest{Id: "8", Movie: &moviespb.Movie{Title: "Two"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, map[string]string{"expected_version": "REQUIRED"}, fieldViolations(t, err))
+	_, err = cli.DeleteMovie(ctx, &moviespb.DeleteMovieRequest{Id: "8"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = cli.RestoreMovie(ctx, &moviespb.RestoreMovieRequest{Id: "7"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTrash_Bufconn(t *testing.T) {
+	s := grpc.NewServer()
+	moviespb.RegisterMovieServiceServer(s, New(fakeSvc{}))
+
+	conn, cleanup, err := dialBuf(s)
+	require.NoError(t, err)
+	defer cleanup()
+	cli := moviespb.NewMovieServiceClient(conn)
+	ctx := context.Background()
+
+	list, err := cli.ListDeletedMovies(ctx, &moviespb.ListMoviesRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetMovies(), 1)
+	require.Equal(t, trashedAt, list.GetMovies()[0].GetDeletedAt().AsTime())
+
+	got, err := cli.GetDeletedMovie(ctx, &moviespb.GetMovieRequest{Id: "7"})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), got.GetMovie().GetVersion())
+	_, err = cli.GetDeletedMovie(ctx, &moviespb.GetMovieRequest{Id: "8"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	resp, err := cli.RestoreMovie(ctx, &moviespb.RestoreMovieRequest{Id: "7", ExpectedVersion: 2})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), resp.GetMovie().GetVersion())
+	require.Nil(t, resp.GetMovie().GetDeletedAt())
+
+	_, err = cli.RestoreMovie(ctx, &moviespb.RestoreMovieRequest{Id: "7", ExpectedVersion: 1})
+	require.Equal(t, codes.Aborted, status.Code(err))
+	_, err = cli.RestoreMovie(ctx, &moviespb.RestoreMovieRequest{Id: "8", ExpectedVersion: 2})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// fieldViolations extrai errdetails.BadRequest do status como campo -> reason.
+func fieldViolations(t *testing.T, err error) map[string]string {
+	t.Helper()
+	out := map[string]string{}
+	for _, d := range status.Convert(err).Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				out[v.GetField()] = v.GetReason()
+			}
+		}
+	}
+	return out
+}
+
+func TestCreateMovie_ErrorDetails(t *testing.T) {
+	s := grpc.NewServer()
+	moviespb.RegisterMovieServiceServer(s, New(fakeSvc{}))
+
+	conn, cleanup, err := dialBuf(s)
+	require.NoError(t, err)
+	defer cleanup()
+	cli := moviespb.NewMovieServiceClient(conn)
+
+	_, err = cli.CreateMovie(context.Background(), &moviespb.CreateMovieRequest{Title: " ", Year: 1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, map[string]string{"title": "REQUIRED", "year": "RANGE"}, fieldViolations(t, err))
+
+	_, err = cli.CreateMovie(context.Background(), &moviespb.CreateMovieRequest{Title: "Dup", Year: 2000})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	require.Equal(t, map[string]string{"title": "UNIQUE", "year": "UNIQUE"}, fieldViolations(t, err))
+	var info *errdetails.ErrorInfo
+	for _, d := range status.Convert(err).Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			info = ei
+		}
+	}
+	require.NotNil(t, info)
+	require.Equal(t, map[string]string{"conflicting_id": "8"}, info.GetMetadata())
+
+	// conflito com filme na lixeira: o cliente pode restaurá-lo
+	_, err = cli.CreateMovie(context.Background(), &moviespb.CreateMovieRequest{Title: "Trashed", Year: 2000})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	for _, d := range status.Convert(err).Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			info = ei
+		}
+	}
+	require.Equal(t, map[string]string{"conflicting_id": "7", "trashed": "true"}, info.GetMetadata())
+}
+
+func TestCreateMovie_OptionalFields(t *testing.T) {
+	s := grpc.NewServer()
+	moviespb.RegisterMovieServiceServer(s, New(fakeSvc{}))
+
+	conn, cleanup, err := dialBuf(s)
+	require.NoError(t, err)
+	defer cleanup()
+	cli := moviespb.NewMovieServiceClient(conn)
+
+	resp, err := cli.CreateMovie(context.Background(), &moviespb.CreateMovieRequest{
+		Title: "Metropolis", Year: 1927,
+		Genres: []string{"Drama"}, RuntimeMinutes: 153,
+		Directors: []string{"Fritz Lang"}, Cast: []string{"Brigitte Helm"},
+		Synopsis: "A city of the future.", OriginalLanguage: "de", PosterUrl: "https://img.example/m.jpg",
+	})
+	require.NoError(t, err)
+	m := resp.GetMovie()
+	require.Equal(t, []string{"Drama"}, m.GetGenres())
+	require.Equal(t, int32(153), m.GetRuntimeMinutes())
+	require.Equal(t, []string{"Fritz Lang"}, m.GetDirectors())
+	require.Equal(t, []string{"Brigitte Helm"}, m.GetCast())
+	require.Equal(t, "A city of the future.", m.GetSynopsis())
+	require.Equal(t, "de", m.GetOriginalLanguage())
+	require.Equal(t, "https://img.example/m.jpg", m.GetPosterUrl())
+}
+
+func TestToStatusErr_WrappedSentinels(t *testing.T) {
+	err := toStatusErr(fmt.Errorf("list: %w", domain.ErrInvalidSortField))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, map[string]string{"sort_by": "INVALID"}, fieldViolations(t, err))
+
+	require.Equal(t, codes.NotFound, status.Code(toStatusErr(fmt.Errorf("get: %w", domain.ErrNotFound))))
+	require.Equal(t, codes.Unknown, status.Code(toStatusErr(errors.New("boom"))))
+}
+
+func TestWatchMovies_Bufconn(t *testing.T) {
+	s := grpc.NewServer()
+	moviespb.RegisterMovieServiceServer(s, New(fakeSvc{}))
+
+	conn, cleanup, err := dialBuf(s)
+	require.NoError(t, err)
+	defer cleanup()
+
+	cli := moviespb.NewMovieServiceClient(conn)
+	stream, err := cli.WatchMovies(context.Background(), &moviespb.WatchMoviesRequest{})
+	require.NoError(t, err)
+
+	c, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, moviespb.MovieChange_CREATED, c.GetType())
+	require.Equal(t, "New", c.GetMovie().GetTitle())
+	require.Equal(t, "1", c.GetResumeToken())
+
+	c, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, moviespb.MovieChange_DELETED, c.GetType())
+	require.Equal(t, "8", c.GetMovie().GetId())
+
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+
+	stream, err = cli.WatchMovies(context.Background(), &moviespb.WatchMoviesRequest{ResumeToken: "stale"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
 }
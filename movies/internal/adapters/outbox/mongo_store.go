@@ -0,0 +1,107 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ Store = (*MongoStore)(nil)
+
+// Registros enviados ficam um tempo para auditoria e depois expiram (índice TTL).
+const sentRetention = 7 * 24 * time.Hour
+
+type MongoStore struct {
+	col *mongo.Collection
+}
+
+func NewMongoStore(col *mongo.Collection) (*MongoStore, error) {
+	_, err := col.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("status_fifo"),
+		},
+		{
+			Keys:    bson.D{{Key: "sent_at", Value: 1}},
+			Options: options.Index().SetName("ttl_sent").SetExpireAfterSeconds(int32(sentRetention.Seconds())),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create outbox indexes: %w", err)
+	}
+	return &MongoStore{col: col}, nil
+}
+
+func (s *MongoStore) Add(ctx context.Context, r Record) error {
+	_, err := s.col.InsertOne(ctx, r)
+	return err
+}
+
+// ClaimNext é FIFO estrito: se o mais antigo ainda está em backoff, nada é
+// entregue, preservando a ordem dos eventos para os consumidores.
+func (s *MongoStore) ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*Record, error) {
+	var head Record
+	err := s.col.FindOne(ctx,
+		bson.M{"status": StatusPending},
+		options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}}),
+	).Decode(&head)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if head.NextAttemptAt.After(now) {
+		return nil, nil
+	}
+
+	// reserva condicional: outra réplica pode ter pego o mesmo registro
+	var claimed Record
+	err = s.col.FindOneAndUpdate(ctx,
+		bson.M{"_id": head.ID, "status": StatusPending, "next_attempt_at": head.NextAttemptAt},
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&claimed)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &claimed, nil
+}
+
+func (s *MongoStore) MarkSent(ctx context.Context, id string, at time.Time) error {
+	_, err := s.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": StatusSent, "sent_at": at},
+		"$unset": bson.M{"last_error": ""},
+	})
+	return err
+}
+
+func (s *MongoStore) MarkRetry(ctx context.Context, id string, attempts int, next time.Time, lastErr string) error {
+	_, err := s.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"attempts":        attempts,
+		"next_attempt_at": next,
+		"last_error":      lastErr,
+	}})
+	return err
+}
+
+func (s *MongoStore) MarkFailed(ctx context.Context, id string, attempts int, lastErr string) error {
+	_, err := s.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"status":     StatusFailed,
+		"attempts":   attempts,
+		"last_error": lastErr,
+	}})
+	return err
+}
+
+func (s *MongoStore) Pending(ctx context.Context) (int64, error) {
+	return s.col.CountDocuments(ctx, bson.M{"status": StatusPending})
+}
@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ ports.EventPublisher = (*Publisher)(nil)
+
+// Publisher grava eventos no outbox em vez de enviá-los. Usado dentro de
+// ports.Transactor, o evento é persistido atomicamente com o filme; o Relay
+// faz o envio de fato.
+type Publisher struct {
+	store Store
+}
+
+func NewPublisher(store Store) *Publisher { return &Publisher{store: store} }
+
+func (p *Publisher) add(ctx context.Context, typ string, m domain.Movie, before *domain.Movie) error {
+	now := time.Now().UTC()
+	return p.store.Add(ctx, Record{
+		ID:            primitive.NewObjectID().Hex(),
+		Type:          typ,
+		Movie:         m,
+		Before:        before,
+		Status:        StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+}
+
+func (p *Publisher) MovieCreated(ctx context.Context, m domain.Movie) error {
+	return p.add(ctx, domain.EventMovieCreated, m, nil)
+}
+
+func (p *Publisher) MovieUpdated(ctx context.Context, before, after domain.Movie) error {
+	return p.add(ctx, domain.EventMovieUpdated, after, &before)
+}
+
+func (p *Publisher) MovieDeleted(ctx context.Context, m domain.Movie) error {
+	return p.add(ctx, domain.EventMovieDeleted, m, nil)
+}
+
+func (p *Publisher) MovieSnapshot(ctx context.Context, m domain.Movie) error {
+	return p.add(ctx, domain.EventMovieSnapshot, m, nil)
+}
@@ -0,0 +1,43 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+)
+
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	// StatusFailed é o dead-letter: o relay desistiu após maxAttempts.
+	StatusFailed = "failed"
+)
+
+// Record é um evento de domínio aguardando publicação. ID é um ObjectID em
+// hex (ordenável por tempo) e serve de id estável do evento entre retries.
+type Record struct {
+	ID            string        `bson:"_id"`
+	Type          string        `bson:"type"`
+	Movie         domain.Movie  `bson:"movie"`
+	Before        *domain.Movie `bson:"before,omitempty"` // só em movies.updated
+	Status        string        `bson:"status"`
+	Attempts      int           `bson:"attempts"`
+	NextAttemptAt time.Time     `bson:"next_attempt_at"`
+	LastError     string        `bson:"last_error,omitempty"`
+	CreatedAt     time.Time     `bson:"created_at"`
+	SentAt        *time.Time    `bson:"sent_at,omitempty"`
+}
+
+// Store persiste o outbox. Add deve respeitar a transação presente no ctx.
+type Store interface {
+	Add(ctx context.Context, r Record) error
+	// ClaimNext devolve o registro pendente mais antigo se já estiver na hora
+	// de tentar, reservando-o por lease; nil quando não há nada a fazer.
+	ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*Record, error)
+	MarkSent(ctx context.Context, id string, at time.Time) error
+	MarkRetry(ctx context.Context, id string, attempts int, next time.Time, lastErr string) error
+	// MarkFailed tira o registro da fila de pending (dead-letter).
+	MarkFailed(ctx context.Context, id string, attempts int, lastErr string) error
+	Pending(ctx context.Context) (int64, error)
+}
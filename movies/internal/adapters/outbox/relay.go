@@ -0,0 +1,144 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/events"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+)
+
+const (
+	defaultLease       = 30 * time.Second
+	defaultMinBackoff  = time.Second
+	defaultMaxBackoff  = time.Minute
+	defaultReportEvery = 30 * time.Second
+)
+
+// Relay lê o outbox em ordem e publica cada registro no publisher real
+// (NATS), com retentativas e backoff exponencial. Um registro que falha
+// maxAttempts vezes vai para failed (dead-letter) e deixa de bloquear a fila;
+// ele continua na coleção para inspeção e reenvio manual.
+type Relay struct {
+	store       Store
+	pub         ports.EventPublisher
+	interval    time.Duration
+	maxAttempts int
+
+	lease       time.Duration
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	reportEvery time.Duration
+	now         func() time.Time
+}
+
+// NewRelay cria o relay; maxAttempts <= 0 desliga o dead-letter (retenta
+// para sempre).
+func NewRelay(store Store, pub ports.EventPublisher, interval time.Duration, maxAttempts int) *Relay {
+	return &Relay{
+		store:       store,
+		pub:         pub,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		lease:       defaultLease,
+		minBackoff:  defaultMinBackoff,
+		maxBackoff:  defaultMaxBackoff,
+		reportEvery: defaultReportEvery,
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run processa o outbox a cada interval até ctx ser cancelado e reporta o
+// backlog no log periodicamente.
+func (r *Relay) Run(ctx context.Context) {
+	tick := time.NewTicker(r.interval)
+	defer tick.Stop()
+	report := time.NewTicker(r.reportEvery)
+	defer report.Stop()
+
+	for {
+		if _, err := r.Drain(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-report.C:
+			if n, err := r.store.Pending(ctx); err == nil && n > 0 {
+				log.Printf("outbox relay: backlog=%d pending events", n)
+			}
+		case <-tick.C:
+		}
+	}
+}
+
+// Drain publica registros enquanto houver algum pronto. Para na primeira
+// falha (o registro volta para pending com backoff) para não furar a ordem,
+// exceto quando a falha esgota maxAttempts: aí o registro vai para failed e
+// a fila segue com o próximo.
+func (r *Relay) Drain(ctx context.Context) (sent int, err error) {
+	for ctx.Err() == nil {
+		rec, err := r.store.ClaimNext(ctx, r.now(), r.lease)
+		if err != nil {
+			return sent, fmt.Errorf("claim: %w", err)
+		}
+		if rec == nil {
+			return sent, nil
+		}
+
+		if perr := r.dispatch(ctx, *rec); perr != nil {
+			attempts := rec.Attempts + 1
+			if r.maxAttempts > 0 && attempts >= r.maxAttempts {
+				if err := r.store.MarkFailed(ctx, rec.ID, attempts, perr.Error()); err != nil {
+					return sent, fmt.Errorf("mark failed %s: %w", rec.ID, err)
+				}
+				log.Printf("outbox relay: dead-lettered %s %s after %d attempts: %v", rec.Type, rec.ID, attempts, perr)
+				continue
+			}
+			next := r.now().Add(r.backoff(attempts))
+			if err := r.store.MarkRetry(ctx, rec.ID, attempts, next, perr.Error()); err != nil {
+				return sent, fmt.Errorf("mark retry %s: %w", rec.ID, err)
+			}
+			return sent, fmt.Errorf("publish %s %s (attempt %d): %w", rec.Type, rec.ID, attempts, perr)
+		}
+		if err := r.store.MarkSent(ctx, rec.ID, r.now()); err != nil {
+			return sent, fmt.Errorf("mark sent %s: %w", rec.ID, err)
+		}
+		sent++
+	}
+	return sent, ctx.Err()
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.minBackoff
+	for i := 1; i < attempts && d < r.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > r.maxBackoff {
+		d = r.maxBackoff
+	}
+	return d
+}
+
+func (r *Relay) dispatch(ctx context.Context, rec Record) error {
+	ctx = events.WithMeta(ctx, events.Meta{ID: rec.ID, OccurredAt: rec.CreatedAt})
+	switch rec.Type {
+	case domain.EventMovieCreated:
+		return r.pub.MovieCreated(ctx, rec.Movie)
+	case domain.EventMovieUpdated:
+		before := rec.Movie
+		if rec.Before != nil {
+			before = *rec.Before
+		}
+		return r.pub.MovieUpdated(ctx, before, rec.Movie)
+	case domain.EventMovieDeleted:
+		return r.pub.MovieDeleted(ctx, rec.Movie)
+	case domain.EventMovieSnapshot:
+		return r.pub.MovieSnapshot(ctx, rec.Movie)
+	default:
+		return fmt.Errorf("unknown event type %q", rec.Type)
+	}
+}
@@ -0,0 +1,157 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore implementa Store em memória com a mesma semântica FIFO do MongoStore.
+type memStore struct{ recs map[string]*Record }
+
+func newMemStore() *memStore { return &memStore{recs: map[string]*Record{}} }
+
+func (s *memStore) Add(ctx context.Context, r Record) error {
+	s.recs[r.ID] = &r
+	return nil
+}
+func (s *memStore) pending() []*Record {
+	var out []*Record
+	for _, r := range s.recs {
+		if r.Status == StatusPending {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+func (s *memStore) ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*Record, error) {
+	p := s.pending()
+	if len(p) == 0 || p[0].NextAttemptAt.After(now) {
+		return nil, nil
+	}
+	p[0].NextAttemptAt = now.Add(lease)
+	cp := *p[0]
+	return &cp, nil
+}
+func (s *memStore) MarkSent(ctx context.Context, id string, at time.Time) error {
+	s.recs[id].Status = StatusSent
+	s.recs[id].SentAt = &at
+	return nil
+}
+func (s *memStore) MarkRetry(ctx context.Context, id string, attempts int, next time.Time, lastErr string) error {
+	r := s.recs[id]
+	r.Attempts, r.NextAttemptAt, r.LastError = attempts, next, lastErr
+	return nil
+}
+func (s *memStore) MarkFailed(ctx context.Context, id string, attempts int, lastErr string) error {
+	r := s.recs[id]
+	r.Status, r.Attempts, r.LastError = StatusFailed, attempts, lastErr
+	return nil
+}
+func (s *memStore) Pending(ctx context.Context) (int64, error) { return int64(len(s.pending())), nil }
+
+type flakyPub struct {
+	fail int
+	got  []string
+}
+
+func (p *flakyPub) do(typ string, m domain.Movie) error {
+	if p.fail > 0 {
+		p.fail--
+		return errors.New("nats: connection closed")
+	}
+	p.got = append(p.got, typ+":"+m.ID)
+	return nil
+}
+func (p *flakyPub) MovieCreated(ctx context.Context, m domain.Movie) error {
+	return p.do(domain.EventMovieCreated, m)
+}
+func (p *flakyPub) MovieUpdated(ctx context.Context, before, after domain.Movie) error {
+	return p.do(domain.EventMovieUpdated, after)
+}
+func (p *flakyPub) MovieDeleted(ctx context.Context, m domain.Movie) error {
+	return p.do(domain.EventMovieDeleted, m)
+}
+func (p *flakyPub) MovieSnapshot(ctx context.Context, m domain.Movie) error {
+	return p.do(domain.EventMovieSnapshot, m)
+}
+
+func TestRelay_RetriesInOrderUntilSent(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	out := NewPublisher(store)
+	require.NoError(t, out.MovieCreated(ctx, domain.Movie{ID: "1"}))
+	require.NoError(t, out.MovieUpdated(ctx, domain.Movie{ID: "1"}, domain.Movie{ID: "1", Title: "x"}))
+	require.NoError(t, out.MovieDeleted(ctx, domain.Movie{ID: "1"}))
+
+	pub := &flakyPub{fail: 2}
+	relay := NewRelay(store, pub, time.Second, 0)
+	now := time.Now().UTC()
+	relay.now = func() time.Time { return now }
+
+	// 1ª falha: nada enviado, registro mais antigo entra em backoff
+	sent, err := relay.Drain(ctx)
+	require.Error(t, err)
+	require.Zero(t, sent)
+
+	// ainda em backoff: FIFO estrito não deixa os seguintes passarem na frente
+	sent, err = relay.Drain(ctx)
+	require.NoError(t, err)
+	require.Zero(t, sent)
+
+	now = now.Add(relay.minBackoff)
+	_, err = relay.Drain(ctx) // 2ª falha, backoff dobra
+	require.Error(t, err)
+	head := store.pending()[0]
+	require.Equal(t, 2, head.Attempts)
+	require.Equal(t, now.Add(2*relay.minBackoff), head.NextAttemptAt)
+
+	now = now.Add(2 * relay.minBackoff)
+	sent, err = relay.Drain(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, sent)
+	require.Equal(t, []string{"movies.created:1", "movies.updated:1", "movies.deleted:1"}, pub.got)
+
+	n, _ := store.Pending(ctx)
+	require.Zero(t, n)
+}
+
+func TestRelay_DeadLettersAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	require.NoError(t, store.Add(ctx, Record{ID: "a", Type: "movies.bogus", Status: StatusPending}))
+	require.NoError(t, store.Add(ctx, Record{ID: "b", Type: domain.EventMovieCreated, Movie: domain.Movie{ID: "2"}, Status: StatusPending}))
+
+	pub := &flakyPub{}
+	relay := NewRelay(store, pub, time.Second, 2)
+	now := time.Now().UTC()
+	relay.now = func() time.Time { return now }
+
+	// 1ª falha: ainda bloqueia a fila
+	_, err := relay.Drain(ctx)
+	require.Error(t, err)
+	require.Empty(t, pub.got)
+
+	// 2ª falha esgota as tentativas: vai para failed e o seguinte passa
+	now = now.Add(relay.minBackoff)
+	sent, err := relay.Drain(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+	require.Equal(t, []string{"movies.created:2"}, pub.got)
+	require.Equal(t, StatusFailed, store.recs["a"].Status)
+	require.Equal(t, 2, store.recs["a"].Attempts)
+	require.Contains(t, store.recs["a"].LastError, "unknown event type")
+}
+
+func TestRelay_BackoffIsCapped(t *testing.T) {
+	r := NewRelay(newMemStore(), &flakyPub{}, time.Second, 0)
+	require.Equal(t, time.Second, r.backoff(1))
+	require.Equal(t, 4*time.Second, r.backoff(3))
+	require.Equal(t, time.Minute, r.backoff(50))
+}
@@ -0,0 +1,26 @@
+package projection
+
+import (
+	"context"
+	"log"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+)
+
+var _ ports.EventHandler = LogHandler{}
+
+// LogHandler só registra cada evento recebido no log.
+type LogHandler struct{}
+
+func (LogHandler) Name() string { return "log" }
+
+func (LogHandler) Handle(_ context.Context, ev domain.MovieEvent) error {
+	m := ev.Movie
+	if ev.Before != nil {
+		log.Printf("event %s id=%s at=%s movie=%s %q (%d) -> %q (%d)", ev.Type, ev.ID, ev.OccurredAt.Format("2006-01-02T15:04:05Z07:00"), m.ID, ev.Before.Title, ev.Before.Year, m.Title, m.Year)
+		return nil
+	}
+	log.Printf("event %s id=%s at=%s movie=%s %q (%d)", ev.Type, ev.ID, ev.OccurredAt.Format("2006-01-02T15:04:05Z07:00"), m.ID, m.Title, m.Year)
+	return nil
+}
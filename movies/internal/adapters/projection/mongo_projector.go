@@ -0,0 +1,99 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ ports.EventHandler = (*MongoProjector)(nil)
+
+// MongoProjector mantém um read model de filmes (um documento por id) a
+// partir dos eventos. Cada documento guarda o OccurredAt do último evento
+// aplicado: eventos repetidos ou mais antigos (entrega at-least-once, fora
+// de ordem) são ignorados. movies.deleted (lixeira ou purge) vira tombstone
+// (deleted=true) para que um created atrasado não ressuscite o filme; o
+// created do restore é mais novo que o tombstone e o desfaz.
+type MongoProjector struct {
+	col *mongo.Collection
+}
+
+func NewMongoProjector(col *mongo.Collection) (*MongoProjector, error) {
+	_, err := col.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "deleted", Value: 1}, {Key: "title", Value: 1}},
+		Options: options.Index().SetName("live_title"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create indexes: %w", err)
+	}
+	return &MongoProjector{col: col}, nil
+}
+
+func (p *MongoProjector) Name() string { return "mongo-projector" }
+
+func (p *MongoProjector) Handle(ctx context.Context, ev domain.MovieEvent) error {
+	switch ev.Type {
+	case domain.EventMovieCreated, domain.EventMovieUpdated, domain.EventMovieSnapshot:
+		m := ev.Movie
+		return p.apply(ctx, ev, bson.M{
+			"title":             m.Title,
+			"year":              m.Year,
+			"genres":            m.Genres,
+			"runtime_minutes":   m.RuntimeMinutes,
+			"directors":         m.Directors,
+			"cast":              m.Cast,
+			"synopsis":          m.Synopsis,
+			"original_language": m.OriginalLanguage,
+			"poster_url":        m.PosterURL,
+			"original_title":    m.OriginalTitle,
+			"deleted":           m.DeletedAt != nil,
+		})
+	case domain.EventMovieDeleted:
+		return p.apply(ctx, ev, bson.M{"deleted": true})
+	default:
+		return nil // tipos novos não interessam a esta projeção
+	}
+}
+
+// apply grava set se ev for mais novo que o estado atual. Se o documento já
+// tem versão >= ev, o filtro não casa, o upsert tenta inserir o mesmo _id e
+// o Mongo responde duplicate key: o evento é velho e é descartado.
+func (p *MongoProjector) apply(ctx context.Context, ev domain.MovieEvent, set bson.M) error {
+	set["version"] = ev.OccurredAt
+	set["event_id"] = ev.ID
+	set["projected_at"] = time.Now().UTC()
+
+	filter := bson.M{
+		"_id":     ev.Movie.ID,
+		"version": bson.M{"$lt": ev.OccurredAt},
+	}
+	_, err := p.col.UpdateOne(ctx, filter, bson.M{"$set": set}, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// readMovie é o documento do read model.
+type readMovie struct {
+	ID               string    `bson:"_id"`
+	Title            string    `bson:"title"`
+	Year             int       `bson:"year"`
+	Genres           []string  `bson:"genres"`
+	RuntimeMinutes   int       `bson:"runtime_minutes"`
+	Directors        []string  `bson:"directors"`
+	Cast             []string  `bson:"cast"`
+	Synopsis         string    `bson:"synopsis"`
+	OriginalLanguage string    `bson:"original_language"`
+	PosterURL        string    `bson:"poster_url"`
+	OriginalTitle    string    `bson:"original_title"`
+	Deleted          bool      `bson:"deleted"`
+	Version          time.Time `bson:"version"`
+	EventID          string    `bson:"event_id"`
+}
@@ -0,0 +1,100 @@
+//go:build integration
+// +build integration
+
+package projection
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func newTestCollection(t *testing.T) *mongo.Collection {
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		uri = "mongodb://localhost:27017"
+	}
+	dbname := os.Getenv("MONGODB_DB")
+	if dbname == "" {
+		dbname = "moviesdb_test"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	cl, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+
+	col := cl.Database(dbname).Collection("movies_read")
+	_ = col.Drop(ctx)
+	return col
+}
+
+func TestMongoProjector_IdempotentAndOrdered(t *testing.T) {
+	col := newTestCollection(t)
+	p, err := NewMongoProjector(col)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	created := domain.MovieEvent{ID: "e1", Type: domain.EventMovieCreated, OccurredAt: t0,
+		Movie: domain.Movie{ID: "8", Title: "Sneeze", Year: 1894}}
+	updated := domain.MovieEvent{ID: "e2", Type: domain.EventMovieUpdated, OccurredAt: t0.Add(time.Minute),
+		Movie: domain.Movie{ID: "8", Title: "Fred Ott's Sneeze", Year: 1894}}
+	deleted := domain.MovieEvent{ID: "e3", Type: domain.EventMovieDeleted, OccurredAt: t0.Add(2 * time.Minute),
+		Movie: updated.Movie}
+
+	read := func() readMovie {
+		var rm readMovie
+		require.NoError(t, col.FindOne(ctx, bson.M{"_id": "8"}).Decode(&rm))
+		return rm
+	}
+
+	require.NoError(t, p.Handle(ctx, created))
+	require.NoError(t, p.Handle(ctx, updated))
+	require.NoError(t, p.Handle(ctx, created)) // redelivery atrasada: ignorada
+	require.NoError(t, p.Handle(ctx, updated)) // duplicata: ignorada
+	rm := read()
+	require.Equal(t, "Fred Ott's Sneeze", rm.Title)
+	require.Equal(t, "e2", rm.EventID)
+	require.False(t, rm.Deleted)
+
+	require.NoError(t, p.Handle(ctx, deleted))
+	require.NoError(t, p.Handle(ctx, updated)) // não ressuscita
+	rm = read()
+	require.True(t, rm.Deleted)
+	require.Equal(t, "e3", rm.EventID)
+}
+
+func TestMongoProjector_TrashAndRestore(t *testing.T) {
+	col := newTestCollection(t)
+	p, err := NewMongoProjector(col)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := domain.Movie{ID: "8", Title: "Sneeze", Year: 1894}
+	trashed := m
+	trashed.DeletedAt = &t0
+
+	read := func() readMovie {
+		var rm readMovie
+		require.NoError(t, col.FindOne(ctx, bson.M{"_id": "8"}).Decode(&rm))
+		return rm
+	}
+
+	require.NoError(t, p.Handle(ctx, domain.MovieEvent{ID: "e1", Type: domain.EventMovieCreated, OccurredAt: t0, Movie: m}))
+	require.NoError(t, p.Handle(ctx, domain.MovieEvent{ID: "e2", Type: domain.EventMovieDeleted, OccurredAt: t0.Add(time.Minute), Movie: trashed}))
+	require.True(t, read().Deleted, "na lixeira")
+
+	require.NoError(t, p.Handle(ctx, domain.MovieEvent{ID: "e3", Type: domain.EventMovieCreated, OccurredAt: t0.Add(2 * time.Minute), Movie: m}))
+	require.False(t, read().Deleted, "restaurado")
+	require.Equal(t, "Sneeze", read().Title)
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Chaves de ordenação completas por SortBy: campo principal + desempates,
+// sempre terminando em _id para a ordem ser total (title sozinho não é único).
+var listKeys = map[string][]string{
+	domain.SortByID:    {"legacy_id", "title", "_id"},
+	domain.SortByTitle: {"title", "_id"},
+	domain.SortByYear:  {"year", "title", "_id"},
+}
+
+var listCollation = &options.Collation{
+	Locale:          "en",
+	NumericOrdering: true, // ordenação numérica para strings "8", "10", ...
+}
+
+func sortDir(order string) int {
+	if order == domain.SortDesc {
+		return -1
+	}
+	return 1
+}
+
+// listSort monta o sort do Mongo; todas as chaves seguem a mesma direção,
+// então os índices list_* (ascendentes) atendem asc e desc.
+func listSort(keys []string, dir int) bson.D {
+	out := make(bson.D, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, bson.E{Key: k, Value: dir})
+	}
+	return out
+}
+
+// listFilter converte os filtros de ListOptions em query do Mongo,
+// restrita ao catálogo ou à lixeira (opts.Deleted).
+func listFilter(opts domain.ListOptions) bson.D {
+	filter := bson.D{deletedFilter(opts)}
+	year := bson.D{}
+	if opts.MinYear > 0 {
+		year = append(year, bson.E{Key: "$gte", Value: opts.MinYear})
+	}
+	if opts.MaxYear > 0 {
+		year = append(year, bson.E{Key: "$lte", Value: opts.MaxYear})
+	}
+	if len(year) > 0 {
+		filter = append(filter, bson.E{Key: "year", Value: year})
+	}
+	if opts.TitlePrefix != "" {
+		// intervalo no índice title_prefix; regex case-insensitive não usa índice
+		p := foldTitle(opts.TitlePrefix)
+		filter = append(filter, bson.E{Key: "title_fold", Value: bson.D{
+			{Key: "$gte", Value: p},
+			{Key: "$lt", Value: p + prefixEnd},
+		}})
+	}
+	return filter
+}
+
+// prefixEnd tem o maior peso primário na collation ICU: todo title_fold que
+// começa por p fica em [p, p+prefixEnd).
+const prefixEnd = "\uffff"
+
+// foldedDigit substitui '0' em title_fold (uso privado, '1' vira U+E001...).
+const foldedDigit = '\ue000'
+
+// foldTitle monta title_fold, a chave do filtro por prefixo: título em
+// minúsculas e sem dígitos ASCII. Com o NumericOrdering da listCollation,
+// "Movie 10" ordena depois de "Movie 2" e nenhum intervalo pegaria só os
+// títulos começando por "Movie 1"; trocando os dígitos por caracteres de
+// uso privado, a ordem volta a respeitar prefixos.
+func foldTitle(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return foldedDigit + r - '0'
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// deletedFilter separa catálogo (deleted_at ausente) e lixeira.
+func deletedFilter(opts domain.ListOptions) bson.E {
+	if !opts.Deleted {
+		return bson.E{Key: "deleted_at", Value: nil}
+	}
+	cond := bson.D{{Key: "$type", Value: "date"}}
+	if !opts.DeletedBefore.IsZero() {
+		cond = append(cond, bson.E{Key: "$lt", Value: opts.DeletedBefore})
+	}
+	return bson.E{Key: "deleted_at", Value: cond}
+}
+
+// listCursor guarda a chave de ordenação do último item entregue e a
+// ordenação que o gerou. Serializado como base64url(JSON) para o cliente
+// tratar como opaco.
+type listCursor struct {
+	Sort     string `json:"s"`
+	LegacyID string `json:"l,omitempty"`
+	Title    string `json:"t"`
+	Year     int    `json:"y,omitempty"`
+	ID       string `json:"i"`
+}
+
+// sortSpec identifica a ordenação (e se é a lixeira) que gerou o cursor.
+func sortSpec(opts domain.ListOptions) string {
+	if opts.Deleted {
+		return "deleted:" + opts.SortBy + ":" + opts.SortOrder
+	}
+	return opts.SortBy + ":" + opts.SortOrder
+}
+
+func cursorFrom(opts domain.ListOptions, d dbMovie) listCursor {
+	return listCursor{
+		Sort:     sortSpec(opts),
+		LegacyID: d.LegacyID,
+		Title:    d.Title,
+		Year:     d.Year,
+		ID:       d.ID.Hex(),
+	}
+}
+
+func (c listCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor rejeita tokens malformados ou gerados com outra ordenação.
+func decodeCursor(tok string, opts domain.ListOptions) (listCursor, error) {
+	var c listCursor
+	b, err := base64.RawURLEncoding.DecodeString(tok)
+	if err != nil {
+		return c, domain.ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, domain.ErrInvalidPageToken
+	}
+	if c.Sort != sortSpec(opts) {
+		return c, domain.ErrInvalidPageToken
+	}
+	if _, err := primitive.ObjectIDFromHex(c.ID); err != nil {
+		return c, domain.ErrInvalidPageToken
+	}
+	return c, nil
+}
+
+// value devolve o valor do cursor para uma chave de ordenação.
+// legacy_id ausente vira nil (no Mongo, null ordena antes de string).
+func (c listCursor) value(key string) any {
+	switch key {
+	case "legacy_id":
+		if c.LegacyID == "" {
+			return nil
+		}
+		return c.LegacyID
+	case "title":
+		return c.Title
+	case "year":
+		return c.Year
+	default:
+		oid, _ := primitive.ObjectIDFromHex(c.ID)
+		return oid
+	}
+}
+
+// after monta o filtro keyset "estritamente depois do cursor":
+// (k0 > v0) OR (k0 = v0 AND k1 > v1) OR ... (com < quando desc).
+func (c listCursor) after(keys []string, dir int) bson.D {
+	ors := bson.A{}
+	for i, k := range keys {
+		cond, ok := beyond(k, c.value(k), dir)
+		if !ok {
+			continue
+		}
+		conj := bson.D{}
+		for _, prev := range keys[:i] {
+			conj = append(conj, bson.E{Key: prev, Value: c.value(prev)})
+		}
+		ors = append(ors, append(conj, cond))
+	}
+	return bson.D{{Key: "$or", Value: ors}}
+}
+
+// beyond é a condição "k vem depois de v" na direção dir. ok=false quando
+// nenhum valor pode vir depois (desc a partir de null).
+func beyond(key string, v any, dir int) (bson.E, bool) {
+	nullable := key == "legacy_id"
+	switch {
+	case v == nil && dir > 0:
+		return bson.E{Key: key, Value: bson.D{{Key: "$type", Value: "string"}}}, true
+	case v == nil:
+		return bson.E{}, false
+	case dir > 0:
+		return bson.E{Key: key, Value: bson.D{{Key: "$gt", Value: v}}}, true
+	case nullable:
+		return bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: key, Value: bson.D{{Key: "$lt", Value: v}}}},
+			bson.D{{Key: key, Value: nil}},
+		}}, true
+	default:
+		return bson.E{Key: key, Value: bson.D{{Key: "$lt", Value: v}}}, true
+	}
+}
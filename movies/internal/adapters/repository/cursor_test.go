@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursor_RoundTripAndSortBinding(t *testing.T) {
+	opts := domain.ListOptions{SortBy: domain.SortByYear, SortOrder: domain.SortDesc}
+	d := dbMovie{ID: primitive.NewObjectID(), Title: "Metropolis", Year: 1927, LegacyID: "3"}
+
+	tok := cursorFrom(opts, d).encode()
+	c, err := decodeCursor(tok, opts)
+	require.NoError(t, err)
+	require.Equal(t, 1927, c.value("year"))
+	require.Equal(t, d.ID, c.value("_id"))
+
+	_, err = decodeCursor(tok, domain.ListOptions{SortBy: domain.SortByYear, SortOrder: domain.SortAsc})
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken)
+	_, err = decodeCursor("not-base64!", opts)
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken)
+
+	// cursor da lixeira não serve para o catálogo (nem o contrário)
+	trash := opts
+	trash.Deleted = true
+	_, err = decodeCursor(cursorFrom(trash, d).encode(), opts)
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken)
+	_, err = decodeCursor(tok, trash)
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken)
+}
+
+func TestCursor_AfterNullLegacyID(t *testing.T) {
+	oid := primitive.NewObjectID()
+	c := listCursor{Title: "New", ID: oid.Hex()}
+	keys := listKeys[domain.SortByID]
+
+	// asc: qualquer legacy_id string vem depois de null
+	asc := c.after(keys, 1)[0].Value
+	require.Equal(t, bson.A{
+		bson.D{{Key: "legacy_id", Value: bson.D{{Key: "$type", Value: "string"}}}},
+		bson.D{{Key: "legacy_id", Value: nil}, {Key: "title", Value: bson.D{{Key: "$gt", Value: "New"}}}},
+		bson.D{{Key: "legacy_id", Value: nil}, {Key: "title", Value: "New"}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: oid}}}},
+	}, asc)
+
+	// desc: nada vem "antes" de null no primeiro nível
+	desc := c.after(keys, -1)[0].Value.(bson.A)
+	require.Len(t, desc, 2)
+}
+
+func TestListFilter_TitlePrefixRange(t *testing.T) {
+	require.Equal(t, "the kid \ue001\ue009\ue002\ue001", foldTitle("The Kid 1921"))
+
+	f := listFilter(domain.ListOptions{TitlePrefix: "Movie 1"})
+	require.Equal(t, bson.E{Key: "title_fold", Value: bson.D{
+		{Key: "$gte", Value: "movie \ue001"},
+		{Key: "$lt", Value: "movie \ue001\uffff"},
+	}}, f[len(f)-1])
+
+	// curingas de regex são literais
+	f = listFilter(domain.ListOptions{TitlePrefix: "The.*"})
+	require.Equal(t, "the.*", f[len(f)-1].Value.(bson.D)[0].Value)
+}
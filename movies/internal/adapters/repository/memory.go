@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ ports.MovieRepository = (*MemoryRepository)(nil)
+
+// MemoryRepository é o MovieRepository em memória (STORAGE=memory), para
+// rodar e testar sem Mongo. Segue a semântica do MongoRepository: _id
+// ObjectID para todo filme (id externo = legacy_id quando existe),
+// unicidade de title+year e de legacy_id, mesma ordenação e mesmo formato
+// de page token no List. A busca textual é uma aproximação do $text (sem
+// stemming). Nada é persistido.
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	docs     map[primitive.ObjectID]dbMovie
+	byLegacy map[string]primitive.ObjectID
+	byKey    map[titleYear]primitive.ObjectID
+
+	// índices do List (ver memory_index.go)
+	col   listCollator
+	keys  map[primitive.ObjectID]sortKey
+	lists map[listIndex][]sortKey
+}
+
+type titleYear struct {
+	title string
+	year  int
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		docs:     make(map[primitive.ObjectID]dbMovie),
+		byLegacy: make(map[string]primitive.ObjectID),
+		byKey:    make(map[titleYear]primitive.ObjectID),
+		col:      newListCollator(),
+		keys:     make(map[primitive.ObjectID]sortKey),
+		lists:    make(map[listIndex][]sortKey),
+	}
+}
+
+func (r *MemoryRepository) List(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error) {
+	opts.Normalize()
+	keys, ok := listKeys[opts.SortBy]
+	if !ok {
+		return domain.MoviePage{}, domain.ErrInvalidSortField
+	}
+	dir := sortDir(opts.SortOrder)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// a busca binária parte do cursor; o filtro é aplicado na varredura,
+	// que para em PageSize+1 (o extra só indica que há próxima página)
+	idx := r.lists[listIndex{opts.SortBy, opts.Deleted}]
+	start, step := 0, 1
+	if dir < 0 {
+		start, step = len(idx)-1, -1
+	}
+	if opts.PageToken != "" || opts.AfterID != "" {
+		var from sortKey
+		if opts.PageToken != "" {
+			c, err := decodeCursor(opts.PageToken, opts)
+			if err != nil {
+				return domain.MoviePage{}, err
+			}
+			oid, _ := primitive.ObjectIDFromHex(c.ID)
+			from = newListCollator().key(dbMovie{ID: oid, LegacyID: c.LegacyID, Title: c.Title, Year: c.Year})
+		} else {
+			d, ok := r.findIn(opts.AfterID, opts.Deleted)
+			if !ok {
+				return domain.MoviePage{}, domain.ErrNotFound
+			}
+			from = r.keys[d.ID]
+		}
+		if dir > 0 {
+			start = sort.Search(len(idx), func(i int) bool { return compareKeys(keys, idx[i], from) > 0 })
+		} else {
+			start = sort.Search(len(idx), func(i int) bool { return compareKeys(keys, idx[i], from) >= 0 }) - 1
+		}
+	}
+
+	var dbms []dbMovie
+	for i := start; i >= 0 && i < len(idx) && len(dbms) <= opts.PageSize; i += step {
+		if d := r.docs[idx[i].id]; matchesList(d, opts) {
+			dbms = append(dbms, d)
+		}
+	}
+
+	var page domain.MoviePage
+	if len(dbms) > opts.PageSize {
+		dbms = dbms[:opts.PageSize]
+		page.NextPageToken = cursorFrom(opts, dbms[len(dbms)-1]).encode()
+	}
+	page.Movies = make([]domain.Movie, 0, len(dbms))
+	for _, d := range dbms {
+		page.Movies = append(page.Movies, d.clone().toDomain())
+	}
+	return page, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, id string) (*domain.Movie, error) {
+	return r.get(id, false)
+}
+
+func (r *MemoryRepository) GetDeleted(ctx context.Context, id string) (*domain.Movie, error) {
+	return r.get(id, true)
+}
+
+func (r *MemoryRepository) get(id string, deleted bool) (*domain.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.findIn(id, deleted)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	m := d.clone().toDomain()
+	return &m, nil
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, m *domain.Movie) (*domain.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := fromDomain(*m).clone()
+	if err := r.conflict(d, primitive.NilObjectID); err != nil {
+		return nil, err
+	}
+	d.ID = primitive.NewObjectID()
+	r.put(d)
+
+	cp := *m
+	cp.ID, cp.Version = d.toDomain().ID, d.Version
+	return &cp, nil
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, id string, m *domain.Movie, version int64) (*domain.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, err := r.findVersion(id, version, false)
+	if err != nil {
+		return nil, err
+	}
+	// mesmos campos do updateDoc do Mongo; id, legacy_id e created_at ficam
+	next := fromDomain(*m).clone()
+	next.ID, next.LegacyID, next.Created = cur.ID, cur.LegacyID, cur.Created
+	next.Updated = time.Now().UTC()
+	next.Version = cur.Version + 1
+	if err := r.conflict(next, cur.ID); err != nil {
+		return nil, err
+	}
+	r.remove(cur)
+	r.put(next)
+	dm := next.clone().toDomain()
+	return &dm, nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	now := time.Now().UTC()
+	return r.setDeleted(id, version, false, &now)
+}
+
+func (r *MemoryRepository) Restore(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	return r.setDeleted(id, version, true, nil)
+}
+
+// setDeleted grava deletedAt no filme id (que deve estar na lixeira ou
+// não, conforme deleted) e incrementa a versão.
+func (r *MemoryRepository) setDeleted(id string, version int64, deleted bool, deletedAt *time.Time) (*domain.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, err := r.findVersion(id, version, deleted)
+	if err != nil {
+		return nil, err
+	}
+	d.Deleted = deletedAt
+	d.Version++
+	r.put(d)
+	dm := d.clone().toDomain()
+	return &dm, nil
+}
+
+func (r *MemoryRepository) HardDelete(ctx context.Context, id string, deletedBefore time.Time) (*domain.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.findIn(id, true)
+	if !ok || !d.Deleted.Before(deletedBefore) {
+		return nil, domain.ErrNotFound
+	}
+	r.remove(d)
+	dm := d.toDomain()
+	return &dm, nil
+}
+
+func (r *MemoryRepository) Count(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var n int64
+	for _, d := range r.docs {
+		if d.Deleted == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryRepository) BulkInsertIgnoreDuplicates(ctx context.Context, ms []domain.Movie) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var added []dbMovie
+	for _, m := range ms {
+		d := fromDomain(m).clone()
+		if r.conflict(d, primitive.NilObjectID) != nil {
+			continue
+		}
+		d.ID = primitive.NewObjectID()
+		r.store(d)
+		added = append(added, d)
+	}
+	r.enlistAll(added)
+	return len(added), nil
+}
+
+// find localiza como o idFilter: ObjectID quando id é hex; senão legacy_id.
+// Chamado com o lock (leitura ou escrita) já obtido.
+func (r *MemoryRepository) find(id string) (dbMovie, bool) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		var ok bool
+		if oid, ok = r.byLegacy[id]; !ok {
+			return dbMovie{}, false
+		}
+	}
+	d, ok := r.docs[oid]
+	return d, ok
+}
+
+// findIn find restrito ao catálogo ou à lixeira (deleted).
+func (r *MemoryRepository) findIn(id string, deleted bool) (dbMovie, bool) {
+	d, ok := r.find(id)
+	if !ok || (d.Deleted != nil) != deleted {
+		return dbMovie{}, false
+	}
+	return d, true
+}
+
+// findVersion findIn de uma escrita condicional: ErrVersionMismatch se o
+// filme existe em outra versão (exceto com AnyVersion).
+func (r *MemoryRepository) findVersion(id string, version int64, deleted bool) (dbMovie, error) {
+	d, ok := r.findIn(id, deleted)
+	switch {
+	case !ok:
+		return dbMovie{}, domain.ErrNotFound
+	case version != domain.AnyVersion && d.Version != version:
+		return dbMovie{}, domain.ErrVersionMismatch
+	}
+	return d, nil
+}
+
+// conflict reproduz os índices únicos (uniq_title_year antes de
+// uniq_legacy_id), ignorando o próprio documento self.
+func (r *MemoryRepository) conflict(d dbMovie, self primitive.ObjectID) error {
+	if oid, dup := r.byKey[titleYear{d.Title, d.Year}]; dup && oid != self {
+		return &domain.ConflictError{
+			Fields:        []string{domain.FieldTitle, domain.FieldYear},
+			ConflictingID: r.docs[oid].toDomain().ID,
+			Trashed:       r.docs[oid].Deleted != nil,
+		}
+	}
+	if d.LegacyID == "" {
+		return nil
+	}
+	if oid, dup := r.byLegacy[d.LegacyID]; dup && oid != self {
+		return &domain.ConflictError{Fields: []string{"id"}, ConflictingID: d.LegacyID, Trashed: r.docs[oid].Deleted != nil}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) put(d dbMovie) {
+	r.store(d)
+	r.enlist(d)
+}
+
+// store grava d nos mapas; o List só o enxerga depois do enlist.
+func (r *MemoryRepository) store(d dbMovie) {
+	if old, ok := r.docs[d.ID]; ok {
+		r.unlist(old)
+	}
+	r.docs[d.ID] = d
+	r.byKey[titleYear{d.Title, d.Year}] = d.ID
+	if d.LegacyID != "" {
+		r.byLegacy[d.LegacyID] = d.ID
+	}
+}
+
+func (r *MemoryRepository) remove(d dbMovie) {
+	r.unlist(d)
+	delete(r.docs, d.ID)
+	delete(r.byKey, titleYear{d.Title, d.Year})
+	if d.LegacyID != "" {
+		delete(r.byLegacy, d.LegacyID)
+	}
+}
+
+// clone copia as listas: nada do que o chamador recebe ou entrega
+// compartilha memória com o que está guardado.
+func (d dbMovie) clone() dbMovie {
+	d.Genres = slices.Clone(d.Genres)
+	d.Directors = slices.Clone(d.Directors)
+	d.Cast = slices.Clone(d.Cast)
+	if d.Deleted != nil {
+		t := *d.Deleted
+		d.Deleted = &t
+	}
+	return d
+}
+
+// matchesList aplica os filtros do listFilter.
+func matchesList(d dbMovie, opts domain.ListOptions) bool {
+	if (d.Deleted != nil) != opts.Deleted {
+		return false
+	}
+	if opts.Deleted && !opts.DeletedBefore.IsZero() && !d.Deleted.Before(opts.DeletedBefore) {
+		return false
+	}
+	if opts.MinYear > 0 && d.Year < opts.MinYear {
+		return false
+	}
+	if opts.MaxYear > 0 && d.Year > opts.MaxYear {
+		return false
+	}
+	return opts.TitlePrefix == "" || strings.HasPrefix(strings.ToLower(d.Title), strings.ToLower(opts.TitlePrefix))
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// listIndex identifica um índice ordenado do List: um por chave de
+// listKeys, separado entre catálogo e lixeira (como o deletedFilter).
+type listIndex struct {
+	sortBy  string
+	deleted bool
+}
+
+// sortKey é a chave de ordenação de um documento, com as chaves de
+// collation já calculadas: comparar duas não usa o collator.
+type sortKey struct {
+	id     primitive.ObjectID
+	legacy []byte // nil quando não há legacy_id (null no Mongo)
+	title  []byte
+	year   int
+}
+
+// listCollator calcula chaves como a listCollation do Mongo (locale "en"
+// com ordenação numérica). Um collate.Collator não pode ser compartilhado
+// entre goroutines: o do repositório só é usado com o lock de escrita.
+type listCollator struct{ c *collate.Collator }
+
+func newListCollator() listCollator {
+	return listCollator{collate.New(language.English, collate.Numeric)}
+}
+
+func (lc listCollator) key(d dbMovie) sortKey {
+	var buf collate.Buffer
+	k := sortKey{id: d.ID, year: d.Year}
+	k.title = slices.Clone(lc.c.KeyFromString(&buf, d.Title))
+	if d.LegacyID != "" {
+		buf.Reset()
+		k.legacy = append([]byte{}, lc.c.KeyFromString(&buf, d.LegacyID)...)
+	}
+	return k
+}
+
+// compareKeys ordena a e b pelas chaves de listKeys, ascendente.
+// legacy_id ausente vem antes de qualquer valor (null no Mongo).
+func compareKeys(keys []string, a, b sortKey) int {
+	for _, k := range keys {
+		var c int
+		switch k {
+		case "legacy_id":
+			switch {
+			case a.legacy == nil && b.legacy == nil:
+			case a.legacy == nil:
+				c = -1
+			case b.legacy == nil:
+				c = 1
+			default:
+				c = bytes.Compare(a.legacy, b.legacy)
+			}
+		case "title":
+			c = bytes.Compare(a.title, b.title)
+		case "year":
+			c = cmp.Compare(a.year, b.year)
+		default:
+			c = bytes.Compare(a.id[:], b.id[:])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// enlist insere d nos índices do List. Chamado com o lock de escrita.
+func (r *MemoryRepository) enlist(d dbMovie) {
+	k := r.col.key(d)
+	r.keys[d.ID] = k
+	for sortBy, keys := range listKeys {
+		li := listIndex{sortBy, d.Deleted != nil}
+		idx := r.lists[li]
+		i := sort.Search(len(idx), func(i int) bool { return compareKeys(keys, idx[i], k) > 0 })
+		r.lists[li] = slices.Insert(idx, i, k)
+	}
+}
+
+// enlistAll insere ds de uma vez e reordena cada índice só no fim
+// (carga do seed: inserir um a um desloca o slice a cada documento).
+func (r *MemoryRepository) enlistAll(ds []dbMovie) {
+	for _, d := range ds {
+		k := r.col.key(d)
+		r.keys[d.ID] = k
+		for sortBy := range listKeys {
+			li := listIndex{sortBy, d.Deleted != nil}
+			r.lists[li] = append(r.lists[li], k)
+		}
+	}
+	for li, idx := range r.lists {
+		keys := listKeys[li.sortBy]
+		slices.SortFunc(idx, func(a, b sortKey) int { return compareKeys(keys, a, b) })
+	}
+}
+
+// unlist tira d (na versão guardada) dos índices do List.
+func (r *MemoryRepository) unlist(d dbMovie) {
+	k, ok := r.keys[d.ID]
+	if !ok {
+		return
+	}
+	delete(r.keys, d.ID)
+	for sortBy, keys := range listKeys {
+		li := listIndex{sortBy, d.Deleted != nil}
+		idx := r.lists[li]
+		if i, found := slices.BinarySearchFunc(idx, k, func(e, t sortKey) int { return compareKeys(keys, e, t) }); found {
+			r.lists[li] = slices.Delete(idx, i, i+1)
+		}
+	}
+}
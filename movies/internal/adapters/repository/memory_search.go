@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+)
+
+// Search do MemoryRepository: aproximação do $text com os mesmos campos e
+// pesos do índice search_text, sem acentos e maiúsculas, e stop words em
+// inglês ignoradas. Não há stemming ("robberies" não casa "robbery").
+// Ordem e page token iguais aos do MongoRepository.
+func (r *MemoryRepository) Search(ctx context.Context, opts domain.SearchOptions) (domain.SearchPage, error) {
+	opts.Normalize()
+	var from *searchCursor
+	if opts.PageToken != "" {
+		c, err := decodeSearchCursor(opts.PageToken, opts)
+		if err != nil {
+			return domain.SearchPage{}, err
+		}
+		from = &c
+	}
+	q := parseTextQuery(opts.Query)
+	years := domain.ListOptions{MinYear: opts.MinYear, MaxYear: opts.MaxYear}
+
+	r.mu.RLock()
+	var hits []scoredMovie
+	for _, d := range r.docs {
+		if !matchesList(d, years) {
+			continue
+		}
+		score, ok := q.score(d)
+		if !ok {
+			continue
+		}
+		if from != nil && !(score < from.Score || (score == from.Score && d.ID.Hex() > from.ID)) {
+			continue
+		}
+		hits = append(hits, scoredMovie{dbMovie: d.clone(), Score: score})
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(hits, func(a, b scoredMovie) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.Hex(), b.ID.Hex())
+	})
+
+	var page domain.SearchPage
+	if len(hits) > opts.PageSize {
+		hits = hits[:opts.PageSize]
+		last := hits[len(hits)-1]
+		page.NextPageToken = searchCursor{Spec: searchSpec(opts), Score: last.Score, ID: last.ID.Hex()}.encode()
+	}
+	page.Hits = make([]domain.SearchHit, 0, len(hits))
+	for _, h := range hits {
+		page.Hits = append(page.Hits, domain.SearchHit{Movie: h.toDomain(), Score: h.Score})
+	}
+	return page, nil
+}
+
+// stopWords ignoradas na query e nos documentos, como no índice "english".
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "the": true, "to": true,
+	"with": true,
+}
+
+// textTokens quebra s em palavras sem acentos, minúsculas e sem stop words.
+func textTokens(s string) []string {
+	words := strings.FieldsFunc(domain.FoldText(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return slices.DeleteFunc(words, func(w string) bool { return stopWords[w] })
+}
+
+var quoted = regexp.MustCompile(`"([^"]*)"`)
+
+// textQuery query no formato do $search: termos (OU), "frases" (todas
+// obrigatórias) e -termos (excluem o documento).
+type textQuery struct {
+	terms   map[string]bool
+	phrases []string // tokens unidos por espaço
+	quoted  []string // texto das frases sem acentos, com stop words
+	negated map[string]bool
+}
+
+func parseTextQuery(s string) textQuery {
+	q := textQuery{terms: map[string]bool{}, negated: map[string]bool{}}
+	for _, m := range quoted.FindAllStringSubmatch(s, -1) {
+		toks := textTokens(m[1])
+		if len(toks) == 0 {
+			continue
+		}
+		q.phrases = append(q.phrases, strings.Join(toks, " "))
+		q.quoted = append(q.quoted, domain.FoldText(m[1]))
+		for _, t := range toks {
+			q.terms[t] = true
+		}
+	}
+	for _, f := range strings.Fields(quoted.ReplaceAllString(s, " ")) {
+		dst := q.terms
+		if strings.HasPrefix(f, "-") {
+			dst = q.negated
+		}
+		for _, t := range textTokens(f) {
+			dst[t] = true
+		}
+	}
+	return q
+}
+
+// score calcula a relevância de d como o $text: por campo, peso ×
+// (0,5 + 0,5 × ocorrências/palavras do campo) para cada termo presente.
+// ok=false quando d não casa com a query.
+func (q textQuery) score(d dbMovie) (float64, bool) {
+	var score float64
+	var joined []string
+	for _, w := range searchWeights {
+		toks := textTokens(strings.Join(d.searchField(w.Key), " "))
+		if len(toks) == 0 {
+			continue
+		}
+		joined = append(joined, " "+strings.Join(toks, " ")+" ")
+		counts := map[string]int{}
+		for _, t := range toks {
+			if q.negated[t] {
+				return 0, false
+			}
+			if q.terms[t] {
+				counts[t]++
+			}
+		}
+		weight := float64(w.Value.(int))
+		for _, n := range counts {
+			score += weight * (0.5 + 0.5*float64(n)/float64(len(toks)))
+		}
+	}
+	for _, p := range q.phrases {
+		if !slices.ContainsFunc(joined, func(f string) bool { return strings.Contains(f, " "+p+" ") }) {
+			return 0, false
+		}
+	}
+	return score, score > 0
+}
+
+// searchField valores de um campo do índice de texto.
+func (d dbMovie) searchField(name string) []string {
+	switch name {
+	case "title":
+		return []string{d.Title}
+	case "original_title":
+		return []string{d.OriginalTitle}
+	case "directors":
+		return d.Directors
+	case "cast":
+		return d.Cast
+	case "genres":
+		return d.Genres
+	case "synopsis":
+		return []string{d.Synopsis}
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/repository/repotest"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMemoryRepository_Conformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) ports.MovieRepository { return NewMemoryRepository() })
+}
+
+func TestMemoryRepository_CRUD(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	legacy, err := repo.Create(ctx, &domain.Movie{Title: "Legacy 8", Year: 1894, LegacyID: "8"})
+	require.NoError(t, err)
+	require.Equal(t, "8", legacy.ID)
+
+	created, err := repo.Create(ctx, &domain.Movie{Title: "New", Year: 2001, Genres: []string{"Drama"}})
+	require.NoError(t, err)
+	_, err = primitive.ObjectIDFromHex(created.ID)
+	require.NoError(t, err, "id de filme criado é ObjectID")
+
+	got, err := repo.Get(ctx, "8")
+	require.NoError(t, err)
+	require.Equal(t, "Legacy 8", got.Title)
+	got, err = repo.Get(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Drama"}, got.Genres)
+	got.Genres[0] = "changed" // cópia: não altera o repositório
+	got, _ = repo.Get(ctx, created.ID)
+	require.Equal(t, []string{"Drama"}, got.Genres)
+
+	_, err = repo.Get(ctx, primitive.NewObjectID().Hex())
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// unicidade: title+year (com o id conflitante) e legacy_id
+	_, err = repo.Create(ctx, &domain.Movie{Title: "New", Year: 2001})
+	var ce *domain.ConflictError
+	require.ErrorAs(t, err, &ce)
+	require.ErrorIs(t, err, domain.ErrAlreadyExists)
+	require.Equal(t, created.ID, ce.ConflictingID)
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Other", Year: 2001, LegacyID: "8"})
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, []string{"id"}, ce.Fields)
+
+	// update mantém id/legacy_id e troca a chave title+year
+	upd, err := repo.Update(ctx, "8", &domain.Movie{Title: "Legacy Eight", Year: 1894, Synopsis: "x"}, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, "8", upd.ID)
+	require.Equal(t, "x", upd.Synopsis)
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Legacy 8", Year: 1894})
+	require.NoError(t, err, "chave antiga liberada")
+	_, err = repo.Update(ctx, created.ID, &domain.Movie{Title: "Legacy Eight", Year: 1894}, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrAlreadyExists)
+	_, err = repo.Update(ctx, "404", &domain.Movie{Title: "X", Year: 2000}, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	deleted, err := repo.Delete(ctx, created.ID, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, "New", deleted.Title)
+	_, err = repo.Delete(ctx, created.ID, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	n, err := repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "A", Year: 1900, LegacyID: "1"},
+		{Title: "A", Year: 1900, LegacyID: "2"}, // title+year duplicado
+		{Title: "B", Year: 1900, LegacyID: "8"}, // legacy_id duplicado
+		{Title: "Legacy Eight", Year: 1894},     // já existe
+		{Title: "C", Year: 1900, LegacyID: "3"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	cnt, err := repo.Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), cnt)
+}
+
+func TestMemoryRepository_ListOrderAndPaging(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	_, err := repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "The Kid", Year: 1921, LegacyID: "10"},
+		{Title: "The Gold Rush", Year: 1925, LegacyID: "2"},
+		{Title: "Metropolis", Year: 1927, LegacyID: "8"},
+		{Title: "the thin man", Year: 1934, LegacyID: "9"},
+	})
+	require.NoError(t, err)
+	created, err := repo.Create(ctx, &domain.Movie{Title: "Zelig", Year: 1983})
+	require.NoError(t, err)
+
+	collect := func(opts domain.ListOptions) []string {
+		var titles []string
+		for {
+			page, err := repo.List(ctx, opts)
+			require.NoError(t, err)
+			for _, m := range page.Movies {
+				titles = append(titles, m.Title)
+			}
+			if page.NextPageToken == "" {
+				return titles
+			}
+			opts.PageToken = page.NextPageToken
+		}
+	}
+
+	// ordem do catálogo: sem legacy_id primeiro, depois legacy_id numérico
+	require.Equal(t,
+		[]string{"Zelig", "The Gold Rush", "Metropolis", "the thin man", "The Kid"},
+		collect(domain.ListOptions{PageSize: 2}),
+	)
+	require.Equal(t,
+		[]string{"The Kid", "the thin man", "Metropolis", "The Gold Rush", "Zelig"},
+		collect(domain.ListOptions{PageSize: 1, SortOrder: domain.SortDesc}),
+	)
+	require.Equal(t,
+		[]string{"The Kid", "The Gold Rush", "Metropolis"},
+		collect(domain.ListOptions{PageSize: 1, MaxYear: 1929, SortBy: domain.SortByYear}),
+	)
+	require.Equal(t,
+		[]string{"the thin man", "The Kid", "The Gold Rush"},
+		collect(domain.ListOptions{TitlePrefix: "THE", SortBy: domain.SortByTitle, SortOrder: domain.SortDesc}),
+	)
+
+	page, err := repo.List(ctx, domain.ListOptions{AfterID: "8"})
+	require.NoError(t, err)
+	require.Len(t, page.Movies, 2)
+	require.Equal(t, "9", page.Movies[0].ID)
+	page, err = repo.List(ctx, domain.ListOptions{AfterID: created.ID, PageSize: 1})
+	require.NoError(t, err)
+	require.Equal(t, "2", page.Movies[0].ID)
+
+	_, err = repo.List(ctx, domain.ListOptions{AfterID: "404"})
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.List(ctx, domain.ListOptions{PageToken: page.NextPageToken, SortBy: domain.SortByTitle})
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken)
+}
+
+// Os índices do List acompanham as escritas: update que muda o título,
+// lixeira, restore e exclusão definitiva.
+func TestMemoryRepository_ListIndexFollowsWrites(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	_, err := repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "Movie 10", Year: 2000, LegacyID: "1"},
+		{Title: "Movie 2", Year: 2000, LegacyID: "2"},
+		{Title: "Movie 1", Year: 2000, LegacyID: "3"},
+	})
+	require.NoError(t, err)
+	titles := func(opts domain.ListOptions) []string {
+		page, err := repo.List(ctx, opts)
+		require.NoError(t, err)
+		var out []string
+		for _, m := range page.Movies {
+			out = append(out, m.Title)
+		}
+		return out
+	}
+	byTitle := domain.ListOptions{SortBy: domain.SortByTitle}
+	require.Equal(t, []string{"Movie 1", "Movie 2", "Movie 10"}, titles(byTitle))
+
+	_, err = repo.Update(ctx, "3", &domain.Movie{Title: "Movie 20", Year: 2000}, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Movie 2", "Movie 10", "Movie 20"}, titles(byTitle))
+
+	_, err = repo.Delete(ctx, "2", domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Movie 10", "Movie 20"}, titles(byTitle))
+	trash := domain.ListOptions{SortBy: domain.SortByTitle, Deleted: true}
+	require.Equal(t, []string{"Movie 2"}, titles(trash))
+
+	_, err = repo.Restore(ctx, "2", domain.AnyVersion)
+	require.NoError(t, err)
+	require.Empty(t, titles(trash))
+	require.Equal(t, []string{"Movie 2", "Movie 10", "Movie 20"}, titles(byTitle))
+
+	_, err = repo.Delete(ctx, "1", domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = repo.HardDelete(ctx, "1", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, titles(trash))
+	require.Equal(t, []string{"Movie 20", "Movie 2"}, titles(domain.ListOptions{SortBy: domain.SortByTitle, SortOrder: domain.SortDesc}))
+}
+
+func TestMemoryRepository_Search(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	_, err := repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "The Great Train Robbery", Year: 1903, LegacyID: "1"},
+		{Title: "A Train Robbery Remake", Year: 1950, LegacyID: "2"},
+		{Title: "The Kid", Year: 1921, LegacyID: "3", Synopsis: "A tramp finds a child; later a robbery goes wrong."},
+		{Title: "La sortie des usines Lumière", Year: 1895, LegacyID: "4"},
+		{Title: "Metropolis", Year: 1927, LegacyID: "5", Directors: []string{"Fritz Lang"}},
+	})
+	require.NoError(t, err)
+
+	search := func(opts domain.SearchOptions) []string {
+		var ids []string
+		for {
+			page, err := repo.Search(ctx, opts)
+			require.NoError(t, err)
+			for _, h := range page.Hits {
+				require.Positive(t, h.Score)
+				ids = append(ids, h.Movie.ID)
+			}
+			if page.NextPageToken == "" {
+				return ids
+			}
+			opts.PageToken = page.NextPageToken
+		}
+	}
+
+	require.Equal(t, []string{"1", "2", "3"}, search(domain.SearchOptions{Query: "train robbery", PageSize: 1}))
+	require.Equal(t, []string{"1", "3"}, search(domain.SearchOptions{Query: "train robbery", MaxYear: 1949}))
+	require.Equal(t, []string{"4"}, search(domain.SearchOptions{Query: "LUMIERE"}))
+	require.Equal(t, []string{"5"}, search(domain.SearchOptions{Query: "lang"}))
+	require.Equal(t, []string{"1", "2"}, search(domain.SearchOptions{Query: `"train robbery"`}))
+	require.Equal(t, []string{"1", "3"}, search(domain.SearchOptions{Query: "robbery -remake"}))
+	require.Empty(t, search(domain.SearchOptions{Query: "the"}))
+
+	_, err = repo.Search(ctx, domain.SearchOptions{Query: "other", PageToken: searchCursor{Spec: "x", ID: primitive.NewObjectID().Hex()}.encode()})
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken)
+}
@@ -0,0 +1,36 @@
+-- Ordenação como a listCollation do Mongo: locale "en" com números em
+-- ordem numérica ("8" antes de "10"). Determinística: a unicidade continua
+-- diferenciando maiúsculas, como no uniq_title_year do Mongo.
+CREATE COLLATION IF NOT EXISTS movies_en_numeric (provider = icu, locale = 'en-u-kn-true');
+
+CREATE TABLE movies (
+    -- ObjectID em hex, gerado pelo serviço (mesmo formato do _id no Mongo)
+    id                TEXT        COLLATE "C" PRIMARY KEY,
+    -- '' = filme criado pela API; ordena antes de qualquer legacy_id, como o null no Mongo
+    legacy_id         TEXT        COLLATE movies_en_numeric NOT NULL DEFAULT '',
+    title             TEXT        COLLATE movies_en_numeric NOT NULL,
+    year              INTEGER     NOT NULL,
+    genres            TEXT[],
+    runtime_minutes   INTEGER     NOT NULL DEFAULT 0,
+    directors         TEXT[],
+    cast_members      TEXT[],
+    synopsis          TEXT        NOT NULL DEFAULT '',
+    original_language TEXT        NOT NULL DEFAULT '',
+    poster_url        TEXT        NOT NULL DEFAULT '',
+    original_title    TEXT        NOT NULL DEFAULT '',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ,
+    -- documento da busca textual, montado pelo serviço a partir do texto sem acentos
+    search_doc        TSVECTOR    NOT NULL DEFAULT '',
+
+    CONSTRAINT uniq_title_year UNIQUE (title, year)
+);
+
+CREATE UNIQUE INDEX uniq_legacy_id ON movies (legacy_id) WHERE legacy_id <> '';
+
+-- cobrem ORDER BY + keyset do List em cada SortBy; desc percorre ao contrário
+CREATE INDEX list_order ON movies (legacy_id, title, id);
+CREATE INDEX list_title ON movies (title, id);
+CREATE INDEX list_year  ON movies (year, title, id);
+
+CREATE INDEX search_text ON movies USING GIN (search_doc);
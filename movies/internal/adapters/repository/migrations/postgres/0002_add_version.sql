@@ -0,0 +1,2 @@
+-- Controle de concorrência otimista: começa em 1 e aumenta a cada UPDATE.
+ALTER TABLE movies ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
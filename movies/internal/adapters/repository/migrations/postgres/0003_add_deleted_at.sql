@@ -0,0 +1,4 @@
+-- Lixeira: filmes apagados guardam deleted_at até o purge (NULL = no catálogo).
+ALTER TABLE movies ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX trash ON movies (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Filtro title_prefix: intervalo em lower(title) com collation "C" (ordem
+-- dos bytes, em que todo título que começa pelo prefixo fica contíguo).
+-- O ILIKE na coluna ICU não usava índice nenhum.
+CREATE INDEX title_prefix ON movies ((lower(title) COLLATE "C"));
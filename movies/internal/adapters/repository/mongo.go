@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
@@ -31,58 +32,171 @@ func NewMongoRepository(col *mongo.Collection) (*MongoRepository, error) {
 			Keys:    bson.D{{Key: "legacy_id", Value: 1}},
 			Options: options.Index().SetUnique(true).SetSparse(true).SetName("uniq_legacy_id"),
 		},
+		// cobrem sort + keyset do List em cada SortBy (mesma collation da query);
+		// desc percorre o mesmo índice ao contrário
+		{
+			Keys:    listSort(listKeys[domain.SortByID], 1),
+			Options: options.Index().SetName("list_order").SetCollation(listCollation),
+		},
+		{
+			Keys:    listSort(listKeys[domain.SortByTitle], 1),
+			Options: options.Index().SetName("list_title").SetCollation(listCollation),
+		},
+		{
+			Keys:    listSort(listKeys[domain.SortByYear], 1),
+			Options: options.Index().SetName("list_year").SetCollation(listCollation),
+		},
+		// filtro title_prefix (intervalo em title_fold, mesma collation da query)
+		{
+			Keys:    bson.D{{Key: "title_fold", Value: 1}},
+			Options: options.Index().SetName("title_prefix").SetCollation(listCollation),
+		},
+		searchIndex(),
+		// lixeira e purge (deleted_at só existe em filmes apagados)
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetSparse(true).SetName("trash"),
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create indexes: %w", err)
 	}
+	// documentos gravados antes do controle de versão começam na versão 1
+	if _, err := col.UpdateMany(context.Background(),
+		bson.M{"version": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"version": 1}},
+	); err != nil {
+		return nil, fmt.Errorf("backfill version: %w", err)
+	}
+	if err := backfillTitleFold(context.Background(), col); err != nil {
+		return nil, fmt.Errorf("backfill title_fold: %w", err)
+	}
 	return &MongoRepository{col: col}, nil
 }
 
-func (r *MongoRepository) List(ctx context.Context) ([]domain.Movie, error) {
-	findOpts := options.Find().
-		SetSort(bson.D{
-			{Key: "legacy_id", Value: 1}, // 1 = asc
-			{Key: "title", Value: 1},     // desempate
-		}).
-		SetCollation(&options.Collation{
-			Locale:          "en",
-			NumericOrdering: true, // ordenação numérica para strings "8", "10", ...
-		})
-
-	cur, err := r.col.Find(ctx, bson.D{}, findOpts)
+// backfillFields campos que NewMongoRepository preenche em documentos
+// antigos. Um update que só toca neles não muda o filme: o MongoWatcher
+// não o repassa, senão a primeira subida depois da atualização viraria uma
+// enxurrada de "updated".
+var backfillFields = bson.A{"version", "title_fold"}
+
+// backfillTitleFold preenche title_fold nos documentos gravados antes do
+// filtro por prefixo usá-lo. Cada update confere o título lido, então uma
+// escrita concorrente (que já grava title_fold) não é sobrescrita.
+func backfillTitleFold(ctx context.Context, col *mongo.Collection) error {
+	cur, err := col.Find(ctx, bson.M{"title_fold": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"title": 1}))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer cur.Close(ctx)
 
-	var out []domain.Movie
+	const batch = 500
+	models := make([]mongo.WriteModel, 0, batch)
+	flush := func() error {
+		if len(models) == 0 {
+			return nil
+		}
+		_, err := col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		models = models[:0]
+		return err
+	}
 	for cur.Next(ctx) {
-		var dbm dbMovie
-		if err := cur.Decode(&dbm); err != nil {
-			return nil, err
+		var d struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Title string             `bson:"title"`
+		}
+		if err := cur.Decode(&d); err != nil {
+			return err
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": d.ID, "title": d.Title}).
+			SetUpdate(bson.M{"$set": bson.M{"title_fold": foldTitle(d.Title)}}))
+		if len(models) == batch {
+			if err := flush(); err != nil {
+				return err
+			}
 		}
-		out = append(out, dbm.toDomain())
 	}
-	return out, cur.Err()
+	if err := cur.Err(); err != nil {
+		return err
+	}
+	return flush()
 }
 
-func (r *MongoRepository) Get(ctx context.Context, id string) (*domain.Movie, error) {
+func (r *MongoRepository) List(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error) {
+	opts.Normalize() // idempotente; cobre chamadas diretas ao repositório
+	keys, ok := listKeys[opts.SortBy]
+	if !ok {
+		return domain.MoviePage{}, domain.ErrInvalidSortField
+	}
+	dir := sortDir(opts.SortOrder)
 
-	if oid, err := primitive.ObjectIDFromHex(id); err == nil {
-		var dbm dbMovie
-		if err := r.col.FindOne(ctx, bson.M{"_id": oid}).Decode(&dbm); err != nil {
+	filter := listFilter(opts)
+	switch {
+	case opts.PageToken != "":
+		c, err := decodeCursor(opts.PageToken, opts)
+		if err != nil {
+			return domain.MoviePage{}, err
+		}
+		filter = append(filter, c.after(keys, dir)...)
+	case opts.AfterID != "":
+		var from dbMovie
+		if err := r.col.FindOne(ctx, withDeleted(idFilter(opts.AfterID), opts)).Decode(&from); err != nil {
 			if errors.Is(err, mongo.ErrNoDocuments) {
-				return nil, domain.ErrNotFound
+				return domain.MoviePage{}, domain.ErrNotFound
 			}
-			return nil, err
+			return domain.MoviePage{}, err
 		}
-		dm := dbm.toDomain()
-		return &dm, nil
+		filter = append(filter, cursorFrom(opts, from).after(keys, dir)...)
 	}
 
-	// fallback: legacy_id
+	findOpts := options.Find().
+		SetSort(listSort(keys, dir)).
+		SetCollation(listCollation).
+		SetLimit(int64(opts.PageSize) + 1) // +1 para saber se existe próxima página
+
+	cur, err := r.col.Find(ctx, filter, findOpts)
+	if err != nil {
+		return domain.MoviePage{}, err
+	}
+	defer cur.Close(ctx)
+
+	var dbms []dbMovie
+	for cur.Next(ctx) {
+		var dbm dbMovie
+		if err := cur.Decode(&dbm); err != nil {
+			return domain.MoviePage{}, err
+		}
+		dbms = append(dbms, dbm)
+	}
+	if err := cur.Err(); err != nil {
+		return domain.MoviePage{}, err
+	}
+
+	var page domain.MoviePage
+	if len(dbms) > opts.PageSize {
+		dbms = dbms[:opts.PageSize]
+		page.NextPageToken = cursorFrom(opts, dbms[len(dbms)-1]).encode()
+	}
+	page.Movies = make([]domain.Movie, 0, len(dbms))
+	for _, dbm := range dbms {
+		page.Movies = append(page.Movies, dbm.toDomain())
+	}
+	return page, nil
+}
+
+func (r *MongoRepository) Get(ctx context.Context, id string) (*domain.Movie, error) {
+	return r.findOne(ctx, live(idFilter(id)))
+}
+
+func (r *MongoRepository) GetDeleted(ctx context.Context, id string) (*domain.Movie, error) {
+	return r.findOne(ctx, trashed(idFilter(id)))
+}
+
+func (r *MongoRepository) findOne(ctx context.Context, filter bson.M) (*domain.Movie, error) {
 	var dbm dbMovie
-	if err := r.col.FindOne(ctx, bson.M{"legacy_id": id}).Decode(&dbm); err != nil {
+	if err := r.col.FindOne(ctx, filter).Decode(&dbm); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, domain.ErrNotFound
 		}
@@ -96,19 +210,11 @@ func (r *MongoRepository) Create(ctx context.Context, m *domain.Movie) (*domain.
 	dbm := fromDomain(*m)
 	res, err := r.col.InsertOne(ctx, dbm)
 	if err != nil {
-
-		var we mongo.WriteException
-		if errors.As(err, &we) {
-			for _, e := range we.WriteErrors {
-				if e.Code == 11000 {
-					return nil, fmt.Errorf("duplicate movie (title/year or legacy_id): %w", err)
-				}
-			}
-		}
-		return nil, err
+		return nil, r.conflict(err, m)
 	}
 
 	cp := *m
+	cp.Version = dbm.Version
 	if cp.LegacyID != "" {
 		cp.ID = cp.LegacyID
 	} else {
@@ -119,31 +225,69 @@ func (r *MongoRepository) Create(ctx context.Context, m *domain.Movie) (*domain.
 	return &cp, nil
 }
 
-func (r *MongoRepository) Delete(ctx context.Context, id string) error {
-	if oid, err := primitive.ObjectIDFromHex(id); err == nil {
-		res, err := r.col.DeleteOne(ctx, bson.M{"_id": oid})
-		if err != nil {
-			return err
-		}
-		if res.DeletedCount == 0 {
-			return domain.ErrNotFound
+func (r *MongoRepository) Update(ctx context.Context, id string, m *domain.Movie, version int64) (*domain.Movie, error) {
+	dm, err := r.findAndUpdate(ctx, withVersion(live(idFilter(id)), version), updateDoc(fromDomain(*m)))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, r.noMatch(ctx, id, version, r.Get)
+	}
+	if err != nil {
+		return nil, r.conflict(err, m)
+	}
+	return dm, nil
+}
+
+func (r *MongoRepository) Delete(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	dm, err := r.findAndUpdate(ctx, withVersion(live(idFilter(id)), version), bson.M{
+		"$set": bson.M{"deleted_at": time.Now().UTC()},
+		"$inc": bson.M{"version": 1},
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, r.noMatch(ctx, id, version, r.Get)
+	}
+	return dm, err
+}
+
+func (r *MongoRepository) Restore(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	dm, err := r.findAndUpdate(ctx, withVersion(trashed(idFilter(id)), version), bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$inc":   bson.M{"version": 1},
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, r.noMatch(ctx, id, version, r.GetDeleted)
+	}
+	return dm, err
+}
+
+func (r *MongoRepository) HardDelete(ctx context.Context, id string, deletedBefore time.Time) (*domain.Movie, error) {
+	filter := idFilter(id)
+	filter["deleted_at"] = bson.M{"$lt": deletedBefore}
+	var dbm dbMovie
+	if err := r.col.FindOneAndDelete(ctx, filter).Decode(&dbm); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
 		}
-		return nil
+		return nil, err
 	}
+	dm := dbm.toDomain()
+	return &dm, nil
+}
 
-	// fallback: por legacy_id
-	res, err := r.col.DeleteOne(ctx, bson.M{"legacy_id": id})
+// findAndUpdate aplica update ao documento de filter e devolve o estado
+// depois; mongo.ErrNoDocuments quando nada casa.
+func (r *MongoRepository) findAndUpdate(ctx context.Context, filter, update bson.M) (*domain.Movie, error) {
+	var dbm dbMovie
+	err := r.col.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&dbm)
 	if err != nil {
-		return err
-	}
-	if res.DeletedCount == 0 {
-		return domain.ErrNotFound
+		return nil, err
 	}
-	return nil
+	dm := dbm.toDomain()
+	return &dm, nil
 }
 
 func (r *MongoRepository) Count(ctx context.Context) (int64, error) {
-	return r.col.CountDocuments(ctx, bson.D{})
+	return r.col.CountDocuments(ctx, live(bson.M{}))
 }
 
 func (r *MongoRepository) BulkInsertIgnoreDuplicates(ctx context.Context, ms []domain.Movie) (int, error) {
@@ -172,14 +316,107 @@ func (r *MongoRepository) BulkInsertIgnoreDuplicates(ctx context.Context, ms []d
 	return int(res.InsertedCount), nil
 }
 
+// noMatch explica a escrita condicional que não encontrou documento: o
+// filme não existe (segundo get) ou está em outra versão.
+func (r *MongoRepository) noMatch(ctx context.Context, id string, version int64, get getFunc) error {
+	if version == domain.AnyVersion {
+		return domain.ErrNotFound
+	}
+	if _, err := get(ctx, id); err != nil {
+		return err
+	}
+	return domain.ErrVersionMismatch
+}
+
+// getFunc é Get ou GetDeleted.
+type getFunc func(ctx context.Context, id string) (*domain.Movie, error)
+
+// conflictLookupTimeout limita a busca do filme conflitante.
+const conflictLookupTimeout = 2 * time.Second
+
+// conflict traduz chave duplicada em *domain.ConflictError com o id do filme
+// que já ocupa o título/ano (ou legacy_id) e se ele está na lixeira; outros
+// erros voltam inalterados. A busca usa um contexto novo: dentro de
+// transação o erro já abortou a sessão, e o id é só informativo (fica vazio
+// se a busca falhar).
+func (r *MongoRepository) conflict(err error, m *domain.Movie) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	ce := &domain.ConflictError{Fields: []string{domain.FieldTitle, domain.FieldYear}}
+	filter := bson.M{"title": m.Title, "year": m.Year}
+	if strings.Contains(err.Error(), "uniq_legacy_id") {
+		ce.Fields = []string{"id"} // legacy_id é o id externo
+		filter = bson.M{"legacy_id": m.LegacyID}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conflictLookupTimeout)
+	defer cancel()
+	var dbm dbMovie
+	if r.col.FindOne(ctx, filter).Decode(&dbm) == nil {
+		ce.ConflictingID = dbm.toDomain().ID
+		ce.Trashed = dbm.Deleted != nil
+	}
+	return ce
+}
+
 /************** mapeamentos **************/
 
+// idFilter localiza pelo ObjectID quando id é hex; senão pelo legacy_id.
+func idFilter(id string) bson.M {
+	if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+		return bson.M{"_id": oid}
+	}
+	return bson.M{"legacy_id": id}
+}
+
+// live restringe f ao catálogo; trashed, à lixeira.
+func live(f bson.M) bson.M {
+	f["deleted_at"] = nil
+	return f
+}
+
+func trashed(f bson.M) bson.M {
+	f["deleted_at"] = bson.M{"$type": "date"}
+	return f
+}
+
+// withDeleted restringe f ao catálogo ou à lixeira, conforme opts.Deleted.
+func withDeleted(f bson.M, opts domain.ListOptions) bson.M {
+	if opts.Deleted {
+		return trashed(f)
+	}
+	return live(f)
+}
+
+// withVersion restringe f à versão esperada (exceto AnyVersion).
+func withVersion(f bson.M, version int64) bson.M {
+	if version != domain.AnyVersion {
+		f["version"] = version
+	}
+	return f
+}
+
 type dbMovie struct {
 	ID       primitive.ObjectID `bson:"_id,omitempty"`
 	Title    string             `bson:"title"`
+	Fold     string             `bson:"title_fold"` // foldTitle(Title), para title_prefix
 	Year     int                `bson:"year"`
 	LegacyID string             `bson:"legacy_id,omitempty"`
 	Created  time.Time          `bson:"created_at,omitempty"`
+	Updated  time.Time          `bson:"updated_at,omitempty"`
+	Version  int64              `bson:"version"`
+	Deleted  *time.Time         `bson:"deleted_at,omitempty"`
+
+	// opcionais: ausentes em documentos antigos e no seed
+	Genres           []string `bson:"genres,omitempty"`
+	RuntimeMinutes   int      `bson:"runtime_minutes,omitempty"`
+	Directors        []string `bson:"directors,omitempty"`
+	Cast             []string `bson:"cast,omitempty"`
+	Synopsis         string   `bson:"synopsis,omitempty"`
+	OriginalLanguage string   `bson:"original_language,omitempty"`
+	PosterURL        string   `bson:"poster_url,omitempty"`
+	OriginalTitle    string   `bson:"original_title,omitempty"`
 }
 
 func (d dbMovie) toDomain() domain.Movie {
@@ -188,17 +425,83 @@ func (d dbMovie) toDomain() domain.Movie {
 		id = d.ID.Hex()
 	}
 	return domain.Movie{
-		ID:    id,
-		Title: d.Title,
-		Year:  d.Year,
+		ID:               id,
+		Title:            d.Title,
+		Year:             d.Year,
+		Genres:           d.Genres,
+		RuntimeMinutes:   d.RuntimeMinutes,
+		Directors:        d.Directors,
+		Cast:             d.Cast,
+		Synopsis:         d.Synopsis,
+		OriginalLanguage: d.OriginalLanguage,
+		PosterURL:        d.PosterURL,
+		OriginalTitle:    d.OriginalTitle,
+		Version:          d.Version,
+		DeletedAt:        d.Deleted,
 	}
 }
 
 func fromDomain(m domain.Movie) dbMovie {
 	return dbMovie{
-		Title:    m.Title,
-		Year:     m.Year,
-		LegacyID: m.LegacyID,
-		Created:  time.Now().UTC(),
+		Title:            m.Title,
+		Fold:             foldTitle(m.Title),
+		Year:             m.Year,
+		LegacyID:         m.LegacyID,
+		Created:          time.Now().UTC(),
+		Version:          1,
+		Genres:           m.Genres,
+		RuntimeMinutes:   m.RuntimeMinutes,
+		Directors:        m.Directors,
+		Cast:             m.Cast,
+		Synopsis:         m.Synopsis,
+		OriginalLanguage: m.OriginalLanguage,
+		PosterURL:        m.PosterURL,
+		OriginalTitle:    m.OriginalTitle,
+	}
+}
+
+// updateDoc grava os campos editáveis de d e incrementa a versão; opcionais
+// vazios são removidos do documento ($unset), mantendo o mesmo formato de
+// um insert.
+func updateDoc(d dbMovie) bson.M {
+	set := bson.M{
+		"title":      d.Title,
+		"title_fold": d.Fold,
+		"year":       d.Year,
+		"updated_at": time.Now().UTC(),
+	}
+	unset := bson.M{}
+	for field, v := range map[string]interface{}{
+		"genres":            d.Genres,
+		"runtime_minutes":   d.RuntimeMinutes,
+		"directors":         d.Directors,
+		"cast":              d.Cast,
+		"synopsis":          d.Synopsis,
+		"original_language": d.OriginalLanguage,
+		"poster_url":        d.PosterURL,
+		"original_title":    d.OriginalTitle,
+	} {
+		if isZero(v) {
+			unset[field] = ""
+		} else {
+			set[field] = v
+		}
+	}
+	doc := bson.M{"$set": set, "$inc": bson.M{"version": 1}}
+	if len(unset) > 0 {
+		doc["$unset"] = unset
+	}
+	return doc
+}
+
+func isZero(v interface{}) bool {
+	switch x := v.(type) {
+	case []string:
+		return len(x) == 0
+	case int:
+		return x == 0
+	case string:
+		return x == ""
 	}
+	return v == nil
 }
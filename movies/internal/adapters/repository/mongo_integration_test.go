@@ -9,8 +9,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/adapters/repository/repotest"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -37,6 +40,14 @@ func newTestDB(t *testing.T) *mongo.Database {
 	return db
 }
 
+func TestMongoRepository_Conformance_Integration(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) ports.MovieRepository {
+		repo, err := NewMongoRepository(newTestDB(t).Collection("movies"))
+		require.NoError(t, err)
+		return repo
+	})
+}
+
 func TestMongoRepository_Integration(t *testing.T) {
 	db := newTestDB(t)
 	repo, err := NewMongoRepository(db.Collection("movies"))
@@ -61,8 +72,351 @@ func TestMongoRepository_Integration(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, int64(1), cnt)
 
-	err = repo.Delete(ctx, created.ID)
+	deleted, err := repo.Delete(ctx, created.ID, domain.AnyVersion)
 	require.NoError(t, err)
+	require.Equal(t, "Legacy 8", deleted.Title)
+	require.NotNil(t, deleted.DeletedAt)
 	_, err = repo.Get(ctx, created.ID)
 	require.Error(t, err)
+	_, err = repo.GetDeleted(ctx, "8")
+	require.NoError(t, err)
+}
+
+func TestMongoRepository_ListPagination_Integration(t *testing.T) {
+	db := newTestDB(t)
+	repo, err := NewMongoRepository(db.Collection("movies"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "Ten", Year: 1900, LegacyID: "10"},
+		{Title: "Eight", Year: 1900, LegacyID: "8"},
+		{Title: "Twelve", Year: 1900, LegacyID: "12"},
+	})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &domain.Movie{Title: "New B", Year: 2000})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &domain.Movie{Title: "New A", Year: 2000})
+	require.NoError(t, err)
+
+	var titles []string
+	tok := ""
+	for {
+		page, err := repo.List(ctx, domain.ListOptions{PageSize: 2, PageToken: tok})
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.Movies), 2)
+		for _, m := range page.Movies {
+			titles = append(titles, m.Title)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		tok = page.NextPageToken
+	}
+	// sem legacy_id primeiro (por título), depois legacy_id em ordem numérica
+	require.Equal(t, []string{"New A", "New B", "Eight", "Ten", "Twelve"}, titles)
+
+	_, err = repo.List(ctx, domain.ListOptions{PageSize: 2, PageToken: "%%%"})
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken)
+
+	// AfterID: retoma logo depois de um id conhecido (replay com checkpoint)
+	page, err := repo.List(ctx, domain.ListOptions{PageSize: 10, AfterID: "8"})
+	require.NoError(t, err)
+	require.Len(t, page.Movies, 2)
+	require.Equal(t, "Ten", page.Movies[0].Title)
+	require.Equal(t, "Twelve", page.Movies[1].Title)
+
+	_, err = repo.List(ctx, domain.ListOptions{AfterID: "404"})
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestMongoRepository_ListFilterSort_Integration(t *testing.T) {
+	db := newTestDB(t)
+	repo, err := NewMongoRepository(db.Collection("movies"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "The Kid", Year: 1921, LegacyID: "1"},
+		{Title: "The Gold Rush", Year: 1925, LegacyID: "2"},
+		{Title: "Metropolis", Year: 1927, LegacyID: "3"},
+		{Title: "The General", Year: 1926, LegacyID: "4"},
+		{Title: "City Lights", Year: 1931, LegacyID: "5"},
+		{Title: "the thin man", Year: 1934, LegacyID: "6"},
+	})
+	require.NoError(t, err)
+
+	collect := func(opts domain.ListOptions) []string {
+		var titles []string
+		for {
+			page, err := repo.List(ctx, opts)
+			require.NoError(t, err)
+			for _, m := range page.Movies {
+				titles = append(titles, m.Title)
+			}
+			if page.NextPageToken == "" {
+				return titles
+			}
+			opts.PageToken = page.NextPageToken
+		}
+	}
+
+	require.Equal(t,
+		[]string{"The Kid", "The Gold Rush", "The General", "Metropolis"},
+		collect(domain.ListOptions{PageSize: 1, MinYear: 1920, MaxYear: 1929, SortBy: "title", SortOrder: "desc"}),
+	)
+	require.Equal(t,
+		[]string{"The Kid", "The Gold Rush", "The General", "the thin man"},
+		collect(domain.ListOptions{PageSize: 2, TitlePrefix: "the", SortBy: "year"}),
+	)
+
+	// cursor de uma ordenação não vale para outra
+	page, err := repo.List(ctx, domain.ListOptions{PageSize: 1, SortBy: "year"})
+	require.NoError(t, err)
+	_, err = repo.List(ctx, domain.ListOptions{PageSize: 1, SortBy: "title", PageToken: page.NextPageToken})
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken)
+}
+
+func TestMongoRepository_Update_Integration(t *testing.T) {
+	db := newTestDB(t)
+	repo, err := NewMongoRepository(db.Collection("movies"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	legacy, err := repo.Create(ctx, &domain.Movie{Title: "Legacy", Year: 1900, LegacyID: "8"})
+	require.NoError(t, err)
+	created, err := repo.Create(ctx, &domain.Movie{Title: "Created", Year: 2000})
+	require.NoError(t, err)
+
+	// pelo legacy_id
+	got, err := repo.Update(ctx, legacy.ID, &domain.Movie{Title: "Legacy 2", Year: 1901}, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, "8", got.ID)
+	require.Equal(t, "Legacy 2", got.Title)
+
+	// pelo ObjectID
+	got, err = repo.Update(ctx, created.ID, &domain.Movie{Title: "Created 2", Year: 2000}, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, created.ID, got.ID)
+
+	// colisão title+year (uniq_title_year)
+	_, err = repo.Update(ctx, created.ID, &domain.Movie{Title: "Legacy 2", Year: 1901}, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrAlreadyExists)
+	var ce *domain.ConflictError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, "8", ce.ConflictingID)
+	require.Equal(t, []string{domain.FieldTitle, domain.FieldYear}, ce.Fields)
+
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Other", Year: 1950, LegacyID: "8"})
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, []string{"id"}, ce.Fields)
+	require.Equal(t, "8", ce.ConflictingID)
+
+	_, err = repo.Update(ctx, "999", &domain.Movie{Title: "X", Year: 2000}, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// campos opcionais: gravados quando presentes, removidos quando vazios
+	rich := domain.Movie{
+		Title: "Created 2", Year: 2000,
+		Genres: []string{"Drama"}, RuntimeMinutes: 90, Directors: []string{"D"},
+		Cast: []string{"A", "B"}, Synopsis: "S", OriginalLanguage: "en", PosterURL: "https://img.example/p.jpg",
+	}
+	got, err = repo.Update(ctx, created.ID, &rich, domain.AnyVersion)
+	require.NoError(t, err)
+	rich.ID, rich.Version = created.ID, 3
+	require.Equal(t, &rich, got)
+
+	got, err = repo.Update(ctx, created.ID, &domain.Movie{Title: "Created 2", Year: 2000}, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, &domain.Movie{ID: created.ID, Title: "Created 2", Year: 2000, Version: 4}, got)
+	raw, err := db.Collection("movies").FindOne(ctx, idFilter(created.ID)).Raw()
+	require.NoError(t, err)
+	_, err = raw.LookupErr("genres")
+	require.Error(t, err, "genres should be unset")
+}
+
+// documentos gravados antes do campo version passam a ter versão 1
+func TestMongoRepository_VersionBackfill_Integration(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	_, err := db.Collection("movies").InsertOne(ctx, bson.M{"title": "Old", "year": 1900, "legacy_id": "1"})
+	require.NoError(t, err)
+
+	repo, err := NewMongoRepository(db.Collection("movies"))
+	require.NoError(t, err)
+	got, err := repo.Get(ctx, "1")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, got.Version)
+
+	got, err = repo.Update(ctx, "1", &domain.Movie{Title: "Old", Year: 1901}, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, got.Version)
+}
+
+func TestMongoWatcher_Integration(t *testing.T) {
+	db := newTestDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	if !SupportsChangeStreams(ctx, db.Client()) {
+		t.Skip("change streams require a replica set")
+	}
+	repo, err := NewMongoRepository(db.Collection("movies"))
+	require.NoError(t, err)
+	w := NewMongoWatcher(ctx, db.Collection("movies"))
+
+	// watch1: recebe as seis mudanças e guarda o token da primeira
+	changes := make(chan domain.MovieChange, 10)
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Watch(watchCtx, "", func(c domain.MovieChange) error { changes <- c; return nil })
+	}()
+	time.Sleep(500 * time.Millisecond) // change stream aberto antes das escritas
+
+	created, err := repo.Create(ctx, &domain.Movie{Title: "Watched", Year: 2001})
+	require.NoError(t, err)
+	_, err = repo.Update(ctx, created.ID, &domain.Movie{Title: "Watched 2", Year: 2001}, domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = repo.Delete(ctx, created.ID, domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = repo.Restore(ctx, created.ID, domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = repo.Delete(ctx, created.ID, domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = repo.HardDelete(ctx, created.ID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	var got []domain.MovieChange
+	for len(got) < 6 {
+		select {
+		case c := <-changes:
+			got = append(got, c)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for changes")
+		}
+	}
+	stopWatch()
+	require.NoError(t, <-done)
+
+	require.Equal(t, domain.EventMovieCreated, got[0].Type)
+	require.Equal(t, created.ID, got[0].Movie.ID)
+	require.Equal(t, domain.EventMovieUpdated, got[1].Type)
+	require.Equal(t, "Watched 2", got[1].Movie.Title)
+	// ir para a lixeira (update com deleted_at) é deleted; restaurar, created
+	require.Equal(t, domain.EventMovieDeleted, got[2].Type)
+	require.NotNil(t, got[2].Movie.DeletedAt)
+	require.Equal(t, domain.EventMovieCreated, got[3].Type)
+	require.Equal(t, domain.EventMovieDeleted, got[4].Type)
+	// purge: deleted de novo
+	require.Equal(t, domain.EventMovieDeleted, got[5].Type)
+	require.Equal(t, created.ID, got[5].Movie.ID)
+
+	// retomando do token da segunda, vêm lixeira, restore, lixeira e purge
+	var resumed []string
+	err = w.Watch(ctx, got[1].ResumeToken, func(c domain.MovieChange) error {
+		resumed = append(resumed, c.Type)
+		if len(resumed) == 4 {
+			return context.Canceled
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, []string{domain.EventMovieDeleted, domain.EventMovieCreated, domain.EventMovieDeleted, domain.EventMovieDeleted}, resumed)
+
+	require.ErrorIs(t, w.Watch(ctx, "not-a-token!", nil), domain.ErrInvalidResumeToken)
+}
+
+// o backfill de version/title_fold na subida não chega aos assinantes
+func TestMongoWatcher_SkipsBackfill_Integration(t *testing.T) {
+	db := newTestDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	if !SupportsChangeStreams(ctx, db.Client()) {
+		t.Skip("change streams require a replica set")
+	}
+	w := NewMongoWatcher(ctx, db.Collection("movies"))
+
+	changes := make(chan domain.MovieChange, 10)
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Watch(watchCtx, "", func(c domain.MovieChange) error { changes <- c; return nil })
+	}()
+	time.Sleep(500 * time.Millisecond) // change stream aberto antes das escritas
+
+	_, err := db.Collection("movies").InsertOne(ctx, bson.M{"title": "Old", "year": 1900, "legacy_id": "1"})
+	require.NoError(t, err)
+	repo, err := NewMongoRepository(db.Collection("movies")) // backfill
+	require.NoError(t, err)
+	_, err = repo.Update(ctx, "1", &domain.Movie{Title: "Old 2", Year: 1900}, 1)
+	require.NoError(t, err)
+
+	var got []domain.MovieChange
+	for len(got) < 2 {
+		select {
+		case c := <-changes:
+			got = append(got, c)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for changes")
+		}
+	}
+	stopWatch()
+	require.NoError(t, <-done)
+
+	require.Equal(t, domain.EventMovieCreated, got[0].Type)
+	require.Equal(t, domain.EventMovieUpdated, got[1].Type)
+	require.Equal(t, "Old 2", got[1].Movie.Title)
+	require.EqualValues(t, 2, got[1].Movie.Version)
+}
+
+func TestMongoRepository_Search_Integration(t *testing.T) {
+	db := newTestDB(t)
+	repo, err := NewMongoRepository(db.Collection("movies"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "The Great Train Robbery", Year: 1903, LegacyID: "1"},
+		{Title: "A Train Robbery Remake", Year: 1950, LegacyID: "2"},
+		{Title: "The Kid", Year: 1921, LegacyID: "3", Synopsis: "A tramp finds a child; later a robbery goes wrong."},
+		{Title: "La sortie des usines Lumière", Year: 1895, LegacyID: "4"},
+		{Title: "Metropolis", Year: 1927, LegacyID: "5"},
+	})
+	require.NoError(t, err)
+
+	// relevância: título com os dois termos antes da sinopse com um só
+	page, err := repo.Search(ctx, domain.SearchOptions{Query: "train robbery"})
+	require.NoError(t, err)
+	require.Len(t, page.Hits, 3)
+	require.Equal(t, "3", page.Hits[2].Movie.ID)
+	require.Greater(t, page.Hits[0].Score, page.Hits[2].Score)
+
+	// acentos
+	page, err = repo.Search(ctx, domain.SearchOptions{Query: "lumiere"})
+	require.NoError(t, err)
+	require.Len(t, page.Hits, 1)
+	require.Equal(t, "4", page.Hits[0].Movie.ID)
+
+	// filtro de ano + paginação cobre todos os resultados sem repetir
+	var ids []string
+	opts := domain.SearchOptions{Query: "train robbery", PageSize: 1, MaxYear: 1949}
+	for {
+		page, err := repo.Search(ctx, opts)
+		require.NoError(t, err)
+		for _, h := range page.Hits {
+			ids = append(ids, h.Movie.ID)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = page.NextPageToken
+	}
+	require.Equal(t, []string{"1", "3"}, ids)
+
+	_, err = repo.Search(ctx, domain.SearchOptions{Query: "other", PageToken: opts.PageToken})
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken)
 }
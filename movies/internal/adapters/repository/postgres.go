@@ -0,0 +1,453 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ ports.MovieRepository = (*PostgresRepository)(nil)
+
+// PostgresRepository é o MovieRepository sobre Postgres (STORAGE=postgres).
+// Segue a semântica do MongoRepository: id ObjectID (hex) para todo filme,
+// id externo = legacy_id quando existe, uniq_title_year e uniq_legacy_id,
+// mesma ordenação e mesmo formato de page token no List.
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepository aplica as migrações pendentes (como o
+// NewMongoRepository cria os índices).
+func NewPostgresRepository(ctx context.Context, pool *pgxpool.Pool) (*PostgresRepository, error) {
+	if _, err := MigratePostgres(ctx, pool); err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	return &PostgresRepository{pool: pool}, nil
+}
+
+// pgColumns colunas lidas por scanMovie, nessa ordem.
+const pgColumns = `id, legacy_id, title, year, genres, runtime_minutes, directors, cast_members,
+	synopsis, original_language, poster_url, original_title, created_at, updated_at, version, deleted_at`
+
+// pgSearchDoc monta o search_doc a partir dos argumentos @search_*: pesos
+// A–D na ordem de searchWeights (title; original_title; diretores e
+// elenco; gêneros e sinopse).
+const pgSearchDoc = `setweight(to_tsvector('english', @search_a), 'A') ||
+	setweight(to_tsvector('english', @search_b), 'B') ||
+	setweight(to_tsvector('english', @search_c), 'C') ||
+	setweight(to_tsvector('english', @search_d), 'D')`
+
+const pgInsert = `INSERT INTO movies (id, legacy_id, title, year, genres, runtime_minutes, directors, cast_members,
+	synopsis, original_language, poster_url, original_title, created_at, version, search_doc)
+VALUES (@id, @legacy_id, @title, @year, @genres, @runtime_minutes, @directors, @cast_members,
+	@synopsis, @original_language, @poster_url, @original_title, @created_at, @version, ` + pgSearchDoc + `)`
+
+// pgListColumns colunas de cada chave de listKeys.
+var pgListColumns = map[string]string{
+	"legacy_id": "legacy_id",
+	"title":     "title",
+	"year":      "year",
+	"_id":       "id",
+}
+
+func (r *PostgresRepository) List(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error) {
+	opts.Normalize()
+	keys, ok := listKeys[opts.SortBy]
+	if !ok {
+		return domain.MoviePage{}, domain.ErrInvalidSortField
+	}
+	dir := sortDir(opts.SortOrder)
+
+	where, args := pgListFilter(opts)
+	var from *listCursor
+	switch {
+	case opts.PageToken != "":
+		c, err := decodeCursor(opts.PageToken, opts)
+		if err != nil {
+			return domain.MoviePage{}, err
+		}
+		from = &c
+	case opts.AfterID != "":
+		d, err := r.get(ctx, opts.AfterID, opts.Deleted)
+		if err != nil {
+			return domain.MoviePage{}, err
+		}
+		c := cursorFrom(opts, d)
+		from = &c
+	}
+	if from != nil {
+		// comparação de linha: (k0, k1, ...) > (v0, v1, ...) é o keyset
+		// inteiro e usa o índice list_*
+		cols := make([]string, 0, len(keys))
+		vals := make([]string, 0, len(keys))
+		for _, k := range keys {
+			cols = append(cols, pgListColumns[k])
+			vals = append(vals, "@after_"+pgListColumns[k])
+		}
+		op := ">"
+		if dir < 0 {
+			op = "<"
+		}
+		where = append(where, fmt.Sprintf("(%s) %s (%s)", strings.Join(cols, ", "), op, strings.Join(vals, ", ")))
+		args["after_legacy_id"], args["after_title"], args["after_year"], args["after_id"] = from.LegacyID, from.Title, from.Year, from.ID
+	}
+
+	order := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if dir < 0 {
+			order = append(order, pgListColumns[k]+" DESC")
+		} else {
+			order = append(order, pgListColumns[k])
+		}
+	}
+	args["limit"] = opts.PageSize + 1 // +1 para saber se existe próxima página
+
+	q := `SELECT ` + pgColumns + ` FROM movies`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	q += ` ORDER BY ` + strings.Join(order, ", ") + ` LIMIT @limit`
+
+	dbms, err := r.query(ctx, q, args)
+	if err != nil {
+		return domain.MoviePage{}, err
+	}
+
+	var page domain.MoviePage
+	if len(dbms) > opts.PageSize {
+		dbms = dbms[:opts.PageSize]
+		page.NextPageToken = cursorFrom(opts, dbms[len(dbms)-1]).encode()
+	}
+	page.Movies = make([]domain.Movie, 0, len(dbms))
+	for _, d := range dbms {
+		page.Movies = append(page.Movies, d.toDomain())
+	}
+	return page, nil
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, id string) (*domain.Movie, error) {
+	d, err := r.get(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	dm := d.toDomain()
+	return &dm, nil
+}
+
+func (r *PostgresRepository) GetDeleted(ctx context.Context, id string) (*domain.Movie, error) {
+	d, err := r.get(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	dm := d.toDomain()
+	return &dm, nil
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, m *domain.Movie) (*domain.Movie, error) {
+	d := fromDomain(*m)
+	d.ID = primitive.NewObjectID()
+	if _, err := r.pool.Exec(ctx, pgInsert, pgArgs(d)); err != nil {
+		return nil, r.conflict(err, m)
+	}
+
+	cp := *m
+	cp.ID, cp.Version = d.toDomain().ID, d.Version
+	return &cp, nil
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, id string, m *domain.Movie, version int64) (*domain.Movie, error) {
+	// mesmos campos do updateDoc do Mongo; id, legacy_id e created_at ficam
+	where, args := pgVersionFilter(id, version, false)
+	for k, v := range pgArgs(fromDomain(*m)) {
+		args[k] = v
+	}
+	args["updated_at"] = time.Now().UTC()
+	rows, err := r.query(ctx, `UPDATE movies SET
+		title = @title, year = @year, genres = @genres, runtime_minutes = @runtime_minutes,
+		directors = @directors, cast_members = @cast_members, synopsis = @synopsis,
+		original_language = @original_language, poster_url = @poster_url,
+		original_title = @original_title, updated_at = @updated_at, version = version + 1,
+		search_doc = `+pgSearchDoc+`
+	WHERE `+where+` RETURNING `+pgColumns, args)
+	if err != nil {
+		return nil, r.conflict(err, m)
+	}
+	if len(rows) == 0 {
+		return nil, r.noMatch(ctx, id, version, false)
+	}
+	dm := rows[0].toDomain()
+	return &dm, nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	now := time.Now().UTC()
+	return r.setDeleted(ctx, id, version, false, &now)
+}
+
+func (r *PostgresRepository) Restore(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	return r.setDeleted(ctx, id, version, true, nil)
+}
+
+// setDeleted grava deletedAt no filme id (que deve estar na lixeira ou
+// não, conforme deleted) e incrementa a versão.
+func (r *PostgresRepository) setDeleted(ctx context.Context, id string, version int64, deleted bool, deletedAt *time.Time) (*domain.Movie, error) {
+	where, args := pgVersionFilter(id, version, deleted)
+	args["deleted_at"] = deletedAt
+	rows, err := r.query(ctx, `UPDATE movies SET deleted_at = @deleted_at, version = version + 1
+	WHERE `+where+` RETURNING `+pgColumns, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, r.noMatch(ctx, id, version, deleted)
+	}
+	dm := rows[0].toDomain()
+	return &dm, nil
+}
+
+func (r *PostgresRepository) HardDelete(ctx context.Context, id string, deletedBefore time.Time) (*domain.Movie, error) {
+	where, args := pgIDFilter(id)
+	args["deleted_before"] = deletedBefore
+	rows, err := r.query(ctx, `DELETE FROM movies WHERE (`+where+`) AND deleted_at < @deleted_before RETURNING `+pgColumns, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	dm := rows[0].toDomain()
+	return &dm, nil
+}
+
+func (r *PostgresRepository) Count(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.pool.QueryRow(ctx, `SELECT count(*) FROM movies WHERE deleted_at IS NULL`).Scan(&n)
+	return n, err
+}
+
+// pgBulkChunk filmes por batch no BulkInsertIgnoreDuplicates.
+const pgBulkChunk = 1000
+
+// BulkInsertIgnoreDuplicates envia os INSERTs em batches (um round trip por
+// batch); ON CONFLICT DO NOTHING descarta quem viola uniq_title_year ou
+// uniq_legacy_id, inclusive duplicados dentro da própria lista.
+func (r *PostgresRepository) BulkInsertIgnoreDuplicates(ctx context.Context, ms []domain.Movie) (int, error) {
+	inserted := 0
+	for start := 0; start < len(ms); start += pgBulkChunk {
+		chunk := ms[start:min(start+pgBulkChunk, len(ms))]
+		b := &pgx.Batch{}
+		for _, m := range chunk {
+			d := fromDomain(m)
+			d.ID = primitive.NewObjectID()
+			b.Queue(pgInsert+` ON CONFLICT DO NOTHING`, pgArgs(d))
+		}
+		res := r.pool.SendBatch(ctx, b)
+		for range chunk {
+			tag, err := res.Exec()
+			if err != nil {
+				_ = res.Close()
+				return inserted, err
+			}
+			inserted += int(tag.RowsAffected())
+		}
+		if err := res.Close(); err != nil {
+			return inserted, err
+		}
+	}
+	return inserted, nil
+}
+
+// get busca o registro pelo id (ObjectID ou legacy_id), no catálogo ou
+// na lixeira (deleted).
+func (r *PostgresRepository) get(ctx context.Context, id string, deleted bool) (dbMovie, error) {
+	where, args := pgIDFilter(id)
+	rows, err := r.query(ctx, `SELECT `+pgColumns+` FROM movies WHERE (`+where+`) AND `+pgDeleted(deleted), args)
+	if err != nil {
+		return dbMovie{}, err
+	}
+	if len(rows) == 0 {
+		return dbMovie{}, domain.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// noMatch explica a escrita condicional que não alterou linha: o filme não
+// existe (no catálogo ou na lixeira, conforme deleted) ou está em outra
+// versão.
+func (r *PostgresRepository) noMatch(ctx context.Context, id string, version int64, deleted bool) error {
+	if version == domain.AnyVersion {
+		return domain.ErrNotFound
+	}
+	if _, err := r.get(ctx, id, deleted); err != nil {
+		return err
+	}
+	return domain.ErrVersionMismatch
+}
+
+// query executa q e lê todas as linhas com scanMovie.
+func (r *PostgresRepository) query(ctx context.Context, q string, args pgx.NamedArgs) ([]dbMovie, error) {
+	rows, err := r.pool.Query(ctx, q, args)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (dbMovie, error) {
+		return scanMovie(row)
+	})
+}
+
+// conflict traduz violação de unicidade (23505) em *domain.ConflictError
+// com o id do filme que já ocupa o título/ano (ou legacy_id) e se ele está
+// na lixeira; outros erros voltam inalterados. O id é só informativo (fica vazio se a busca falhar).
+func (r *PostgresRepository) conflict(err error, m *domain.Movie) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+	ce := &domain.ConflictError{Fields: []string{domain.FieldTitle, domain.FieldYear}}
+	q, args := `SELECT `+pgColumns+` FROM movies WHERE title = @title AND year = @year`, pgx.NamedArgs{"title": m.Title, "year": m.Year}
+	if pgErr.ConstraintName == "uniq_legacy_id" {
+		ce.Fields = []string{"id"} // legacy_id é o id externo
+		q, args = `SELECT `+pgColumns+` FROM movies WHERE legacy_id = @legacy_id`, pgx.NamedArgs{"legacy_id": m.LegacyID}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conflictLookupTimeout)
+	defer cancel()
+	if rows, err := r.query(ctx, q, args); err == nil && len(rows) > 0 {
+		ce.ConflictingID, ce.Trashed = rows[0].toDomain().ID, rows[0].Deleted != nil
+	}
+	return ce
+}
+
+/************** mapeamentos **************/
+
+// pgIDFilter localiza pelo ObjectID quando id é hex; senão pelo legacy_id
+// (nunca a string vazia, que marca filme sem legacy_id).
+func pgIDFilter(id string) (string, pgx.NamedArgs) {
+	if _, err := primitive.ObjectIDFromHex(id); err == nil {
+		return `id = @key`, pgx.NamedArgs{"key": id}
+	}
+	return `legacy_id = @key AND legacy_id <> ''`, pgx.NamedArgs{"key": id}
+}
+
+// pgVersionFilter pgIDFilter no catálogo ou na lixeira (deleted),
+// restrito à versão esperada (exceto AnyVersion).
+func pgVersionFilter(id string, version int64, deleted bool) (string, pgx.NamedArgs) {
+	where, args := pgIDFilter(id)
+	where = `(` + where + `) AND ` + pgDeleted(deleted)
+	if version != domain.AnyVersion {
+		where += ` AND version = @expected_version`
+		args["expected_version"] = version
+	}
+	return where, args
+}
+
+// pgDeleted separa catálogo (deleted_at NULL) e lixeira.
+func pgDeleted(deleted bool) string {
+	if deleted {
+		return `deleted_at IS NOT NULL`
+	}
+	return `deleted_at IS NULL`
+}
+
+// pgListFilter converte os filtros de ListOptions em condições SQL,
+// restritas ao catálogo ou à lixeira (opts.Deleted).
+func pgListFilter(opts domain.ListOptions) ([]string, pgx.NamedArgs) {
+	where := []string{pgDeleted(opts.Deleted)}
+	args := pgx.NamedArgs{}
+	if opts.Deleted && !opts.DeletedBefore.IsZero() {
+		where = append(where, `deleted_at < @deleted_before`)
+		args["deleted_before"] = opts.DeletedBefore
+	}
+	if opts.MinYear > 0 {
+		where = append(where, `year >= @min_year`)
+		args["min_year"] = opts.MinYear
+	}
+	if opts.MaxYear > 0 {
+		where = append(where, `year <= @max_year`)
+		args["max_year"] = opts.MaxYear
+	}
+	if opts.TitlePrefix != "" {
+		// intervalo no índice title_prefix (LIKE com parâmetro não usa índice
+		// em plano genérico)
+		p := strings.ToLower(opts.TitlePrefix)
+		where = append(where, `lower(title) COLLATE "C" >= @title_from AND lower(title) COLLATE "C" < @title_to`)
+		args["title_from"] = p
+		args["title_to"] = p + string(utf8.MaxRune)
+	}
+	return where, args
+}
+
+// pgArgs argumentos de pgInsert (e do UPDATE) para d. Listas vazias viram
+// NULL e o search_doc é montado do texto sem acentos, como o índice v3 do
+// Mongo.
+func pgArgs(d dbMovie) pgx.NamedArgs {
+	fold := func(vs ...[]string) string {
+		var parts []string
+		for _, v := range vs {
+			parts = append(parts, v...)
+		}
+		return domain.FoldText(strings.Join(parts, " "))
+	}
+	return pgx.NamedArgs{
+		"id":                d.ID.Hex(),
+		"legacy_id":         d.LegacyID,
+		"title":             d.Title,
+		"year":              d.Year,
+		"genres":            nilIfEmpty(d.Genres),
+		"runtime_minutes":   d.RuntimeMinutes,
+		"directors":         nilIfEmpty(d.Directors),
+		"cast_members":      nilIfEmpty(d.Cast),
+		"synopsis":          d.Synopsis,
+		"original_language": d.OriginalLanguage,
+		"poster_url":        d.PosterURL,
+		"original_title":    d.OriginalTitle,
+		"created_at":        d.Created,
+		"version":           d.Version,
+		"search_a":          fold([]string{d.Title}),
+		"search_b":          fold([]string{d.OriginalTitle}),
+		"search_c":          fold(d.Directors, d.Cast),
+		"search_d":          fold(d.Genres, []string{d.Synopsis}),
+	}
+}
+
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+// scanMovie lê as colunas de pgColumns, seguidas de extra.
+func scanMovie(row pgx.Row, extra ...any) (dbMovie, error) {
+	var (
+		d       dbMovie
+		id      string
+		updated *time.Time
+	)
+	dest := append([]any{
+		&id, &d.LegacyID, &d.Title, &d.Year, &d.Genres, &d.RuntimeMinutes, &d.Directors, &d.Cast,
+		&d.Synopsis, &d.OriginalLanguage, &d.PosterURL, &d.OriginalTitle, &d.Created, &updated, &d.Version, &d.Deleted,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return dbMovie{}, err
+	}
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return dbMovie{}, fmt.Errorf("movie id %q: %w", id, err)
+	}
+	d.ID = oid
+	if updated != nil {
+		d.Updated = *updated
+	}
+	return d, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Migrações do schema Postgres, embutidas no binário. Arquivos
+// NNNN_descricao.sql, aplicados em ordem de NNNN; uma migração publicada
+// nunca é editada, só sucedida por outra.
+//
+//go:embed migrations/postgres/*.sql
+var pgMigrations embed.FS
+
+// pgMigrationLock chave do advisory lock que serializa réplicas subindo
+// ao mesmo tempo.
+const pgMigrationLock = 0x6d6f76696573 // "movies"
+
+type pgMigration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// MigratePostgres aplica as migrações ainda não registradas em
+// schema_migrations, cada uma na sua transação. Devolve quantas aplicou.
+func MigratePostgres(ctx context.Context, pool *pgxpool.Pool) (int, error) {
+	migs, err := loadPgMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, pgMigrationLock); err != nil {
+		return 0, fmt.Errorf("migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, pgMigrationLock)
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER     PRIMARY KEY,
+		name       TEXT        NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return 0, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	applied := 0
+	for _, m := range migs {
+		var done bool
+		err := conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, m.version).Scan(&done)
+		if err != nil {
+			return applied, err
+		}
+		if done {
+			continue
+		}
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return applied, err
+		}
+		if _, err := tx.Exec(ctx, m.sql); err != nil {
+			_ = tx.Rollback(ctx)
+			return applied, fmt.Errorf("migration %s: %w", m.name, err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.version, m.name); err != nil {
+			_ = tx.Rollback(ctx)
+			return applied, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return applied, fmt.Errorf("migration %s: %w", m.name, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// loadPgMigrations lê as migrações embutidas em ordem de versão.
+func loadPgMigrations() ([]pgMigration, error) {
+	files, err := fs.Glob(pgMigrations, "migrations/postgres/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	migs := make([]pgMigration, 0, len(files))
+	for _, f := range files {
+		name := strings.TrimSuffix(path.Base(f), ".sql")
+		prefix, _, _ := strings.Cut(name, "_")
+		v, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: name must start with a version number", name)
+		}
+		b, err := pgMigrations.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		migs = append(migs, pgMigration{version: v, name: name, sql: string(b)})
+	}
+	slices.SortFunc(migs, func(a, b pgMigration) int { return a.version - b.version })
+	for i := 1; i < len(migs); i++ {
+		if migs[i].version == migs[i-1].version {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", migs[i-1].name, migs[i].name, migs[i].version)
+		}
+	}
+	return migs, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// pgRankWeights pesos {D, C, B, A} do ts_rank, proporcionais a
+// searchWeights (title 10, original_title 5, diretores/elenco 3,
+// gêneros/sinopse 1–2).
+const pgRankWeights = `'{0.15, 0.3, 0.5, 1.0}'`
+
+// Search do PostgresRepository: full-text search do Postgres sobre o
+// search_doc, com a mesma sintaxe de query do $text (termos em OU,
+// "frases" obrigatórias, -termo exclui) e o mesmo stemming em inglês.
+// O score vem do ts_rank, então não é comparável ao do Mongo; a ordem
+// (score desc, id asc) e o page token seguem o MongoRepository.
+func (r *PostgresRepository) Search(ctx context.Context, opts domain.SearchOptions) (domain.SearchPage, error) {
+	opts.Normalize()
+	tsq, tsArgs := parseTextQuery(opts.Query).tsquery()
+	if tsq == "" {
+		return domain.SearchPage{Hits: []domain.SearchHit{}}, nil // só stop words
+	}
+
+	where, args := pgListFilter(domain.ListOptions{MinYear: opts.MinYear, MaxYear: opts.MaxYear})
+	where = append(where, `search_doc @@ q`)
+	for k, v := range tsArgs {
+		args[k] = v
+	}
+	args["limit"] = opts.PageSize + 1 // +1 para saber se existe próxima página
+
+	after := ""
+	if opts.PageToken != "" {
+		c, err := decodeSearchCursor(opts.PageToken, opts)
+		if err != nil {
+			return domain.SearchPage{}, err
+		}
+		after = ` WHERE score < @after_score OR (score = @after_score AND id > @after_id)`
+		args["after_score"], args["after_id"] = c.Score, c.ID
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT `+pgColumns+`, score FROM (
+		SELECT `+pgColumns+`, ts_rank(`+pgRankWeights+`, search_doc, q)::float8 AS score
+		FROM movies, (SELECT `+tsq+` AS q) query
+		WHERE `+strings.Join(where, " AND ")+`
+	) hits`+after+`
+	ORDER BY score DESC, id
+	LIMIT @limit`, args)
+	if err != nil {
+		return domain.SearchPage{}, err
+	}
+	hits, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (scoredMovie, error) {
+		var h scoredMovie
+		d, err := scanMovie(row, &h.Score)
+		h.dbMovie = d
+		return h, err
+	})
+	if err != nil {
+		return domain.SearchPage{}, err
+	}
+
+	var page domain.SearchPage
+	if len(hits) > opts.PageSize {
+		hits = hits[:opts.PageSize]
+		last := hits[len(hits)-1]
+		page.NextPageToken = searchCursor{Spec: searchSpec(opts), Score: last.Score, ID: last.ID.Hex()}.encode()
+	}
+	page.Hits = make([]domain.SearchHit, 0, len(hits))
+	for _, h := range hits {
+		page.Hits = append(page.Hits, domain.SearchHit{Movie: h.toDomain(), Score: h.Score})
+	}
+	return page, nil
+}
+
+// tsquery monta a query do Postgres para q: to_tsquery dos termos (em
+// OU), AND phraseto_tsquery de cada frase (que respeita as posições das
+// stop words, como o to_tsvector) e AND NOT dos termos negados. Os tokens
+// de textTokens só têm letras e dígitos, então não há o que escapar.
+// expr vazia quando não sobra termo.
+func (q textQuery) tsquery() (expr string, args pgx.NamedArgs) {
+	if len(q.terms) == 0 {
+		return "", nil
+	}
+	args = pgx.NamedArgs{"ts_terms": joinSorted(q.terms, " | ")}
+	expr = `to_tsquery('english', @ts_terms)`
+	for i, p := range q.quoted {
+		name := fmt.Sprintf("ts_phrase_%d", i)
+		expr += ` && phraseto_tsquery('english', @` + name + `)`
+		args[name] = p
+	}
+	if len(q.negated) > 0 {
+		expr += ` && (!!to_tsquery('english', @ts_negated))`
+		args["ts_negated"] = joinSorted(q.negated, " | ")
+	}
+	return expr, args
+}
+
+func joinSorted(set map[string]bool, sep string) string {
+	out := make([]string, 0, len(set))
+	for s := range set {
+		out = append(out, s)
+	}
+	slices.Sort(out)
+	return strings.Join(out, sep)
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPgMigrations(t *testing.T) {
+	migs, err := loadPgMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migs)
+	for i, m := range migs {
+		require.Equal(t, i+1, m.version, "versões em sequência, sem buracos")
+		require.NotEmpty(t, m.sql)
+	}
+}
+
+func TestTextQuery_TSQuery(t *testing.T) {
+	expr, args := parseTextQuery(`"The End of Days" lumière -remake`).tsquery()
+	require.Equal(t, `to_tsquery('english', @ts_terms)`+
+		` && phraseto_tsquery('english', @ts_phrase_0)`+
+		` && (!!to_tsquery('english', @ts_negated))`, expr)
+	require.Equal(t, pgx.NamedArgs{
+		"ts_terms":    "days | end | lumiere",
+		"ts_phrase_0": "the end of days",
+		"ts_negated":  "remake",
+	}, args)
+
+	expr, _ = parseTextQuery("the of -remake").tsquery()
+	require.Empty(t, expr, "só stop words e negação: nada a buscar")
+}
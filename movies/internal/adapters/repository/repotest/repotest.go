@@ -0,0 +1,622 @@
+// Package repotest é a suíte de conformidade do ports.MovieRepository:
+// todo backend (Mongo, Postgres, memória, decorators) roda os mesmos
+// testes e prova o mesmo contrato que o usecase e o grpcserver assumem.
+//
+//	func TestMyRepository_Conformance(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) ports.MovieRepository { return newRepo(t) })
+//	}
+package repotest
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory devolve um repositório vazio e isolado para cada subteste.
+type Factory func(t *testing.T) ports.MovieRepository
+
+// unknownOID ObjectID válido que nenhum backend gera.
+const unknownOID = "000000000000000000000000"
+
+// Run executa a suíte inteira, um subteste por parte do contrato.
+func Run(t *testing.T, newRepo Factory) {
+	for _, tc := range []struct {
+		name string
+		fn   func(*testing.T, ports.MovieRepository)
+	}{
+		{"NotFound", testNotFound},
+		{"IDs", testIDs},
+		{"Duplicates", testDuplicates},
+		{"Update", testUpdate},
+		{"Delete", testDelete},
+		{"Trash", testTrash},
+		{"Versions", testVersions},
+		{"BulkInsert", testBulkInsert},
+		{"Count", testCount},
+		{"ListOrder", testListOrder},
+		{"ListFilters", testListFilters},
+		{"ListPaging", testListPaging},
+		{"Search", testSearch},
+	} {
+		t.Run(tc.name, func(t *testing.T) { tc.fn(t, newRepo(t)) })
+	}
+}
+
+func testNotFound(t *testing.T, repo ports.MovieRepository) {
+	ctx := context.Background()
+	for _, id := range []string{unknownOID, "404", ""} {
+		_, err := repo.Get(ctx, id)
+		require.ErrorIs(t, err, domain.ErrNotFound, "Get(%q)", id)
+		for _, v := range []int64{domain.AnyVersion, 1} {
+			_, err = repo.Update(ctx, id, &domain.Movie{Title: "X", Year: 2000}, v)
+			require.ErrorIs(t, err, domain.ErrNotFound, "Update(%q, %d)", id, v)
+			_, err = repo.Delete(ctx, id, v)
+			require.ErrorIs(t, err, domain.ErrNotFound, "Delete(%q, %d)", id, v)
+			_, err = repo.Restore(ctx, id, v)
+			require.ErrorIs(t, err, domain.ErrNotFound, "Restore(%q, %d)", id, v)
+		}
+		_, err = repo.GetDeleted(ctx, id)
+		require.ErrorIs(t, err, domain.ErrNotFound, "GetDeleted(%q)", id)
+		_, err = repo.HardDelete(ctx, id, time.Now().Add(time.Hour))
+		require.ErrorIs(t, err, domain.ErrNotFound, "HardDelete(%q)", id)
+	}
+	_, err := repo.List(ctx, domain.ListOptions{AfterID: "404"})
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// filme sem legacy_id não é encontrado pelo id vazio
+	_, err = repo.Create(ctx, &domain.Movie{Title: "New", Year: 2000})
+	require.NoError(t, err)
+	_, err = repo.Get(ctx, "")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// testIDs: id externo = legacy_id quando existe; senão ObjectID em hex.
+func testIDs(t *testing.T, repo ports.MovieRepository) {
+	ctx := context.Background()
+
+	legacy, err := repo.Create(ctx, &domain.Movie{Title: "Legacy", Year: 1894, LegacyID: "8"})
+	require.NoError(t, err)
+	require.Equal(t, "8", legacy.ID)
+	require.EqualValues(t, 1, legacy.Version)
+
+	created, err := repo.Create(ctx, &domain.Movie{Title: "Created", Year: 2001, Genres: []string{"Drama"}})
+	require.NoError(t, err)
+	require.True(t, isObjectID(created.ID), "id %q", created.ID)
+	require.Equal(t, []string{"Drama"}, created.Genres)
+
+	got, err := repo.Get(ctx, "8")
+	require.NoError(t, err)
+	require.Equal(t, &domain.Movie{ID: "8", Title: "Legacy", Year: 1894, Version: 1}, got)
+	got, err = repo.Get(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, &domain.Movie{ID: created.ID, Title: "Created", Year: 2001, Genres: []string{"Drama"}, Version: 1}, got)
+
+	// legacy_id é o id externo: o ObjectID interno não vaza
+	page, err := repo.List(ctx, domain.ListOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{created.ID, "8"}, ids(page.Movies))
+}
+
+func testDuplicates(t *testing.T, repo ports.MovieRepository) {
+	ctx := context.Background()
+	first, err := repo.Create(ctx, &domain.Movie{Title: "The Kid", Year: 1921})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Legacy", Year: 1894, LegacyID: "8"})
+	require.NoError(t, err)
+
+	// uniq_title_year: o erro traz os campos e o id de quem ocupa
+	_, err = repo.Create(ctx, &domain.Movie{Title: "The Kid", Year: 1921, Synopsis: "again"})
+	require.ErrorIs(t, err, domain.ErrAlreadyExists)
+	var ce *domain.ConflictError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, []string{domain.FieldTitle, domain.FieldYear}, ce.Fields)
+	require.Equal(t, first.ID, ce.ConflictingID)
+	require.False(t, ce.Trashed)
+
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Legacy", Year: 1894})
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, "8", ce.ConflictingID)
+
+	// uniq_legacy_id: o campo é o id externo
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Other", Year: 1950, LegacyID: "8"})
+	require.ErrorIs(t, err, domain.ErrAlreadyExists)
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, []string{"id"}, ce.Fields)
+	require.Equal(t, "8", ce.ConflictingID)
+
+	// a chave é exata: outro ano ou outra caixa não conflitam
+	_, err = repo.Create(ctx, &domain.Movie{Title: "The Kid", Year: 1922})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &domain.Movie{Title: "the kid", Year: 1921})
+	require.NoError(t, err)
+
+	n, err := repo.Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), n)
+}
+
+func testUpdate(t *testing.T, repo ports.MovieRepository) {
+	ctx := context.Background()
+	legacy, err := repo.Create(ctx, &domain.Movie{Title: "Legacy", Year: 1900, LegacyID: "8"})
+	require.NoError(t, err)
+	created, err := repo.Create(ctx, &domain.Movie{Title: "Created", Year: 2000})
+	require.NoError(t, err)
+
+	// pelo legacy_id e pelo ObjectID; o id não muda
+	got, err := repo.Update(ctx, legacy.ID, &domain.Movie{Title: "Legacy 2", Year: 1901}, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, &domain.Movie{ID: "8", Title: "Legacy 2", Year: 1901, Version: 2}, got)
+	got, err = repo.Update(ctx, created.ID, &domain.Movie{Title: "Created", Year: 2000}, domain.AnyVersion)
+	require.NoError(t, err, "manter o próprio title+year não é conflito")
+	require.Equal(t, created.ID, got.ID)
+
+	// campos opcionais: gravados quando presentes, removidos quando vazios
+	rich := domain.Movie{
+		Title: "Created 2", Year: 2000,
+		Genres: []string{"Drama"}, RuntimeMinutes: 90, Directors: []string{"D"},
+		Cast: []string{"A", "B"}, Synopsis: "S", OriginalLanguage: "en", PosterURL: "https://img.example/p.jpg",
+	}
+	got, err = repo.Update(ctx, created.ID, &rich, domain.AnyVersion)
+	require.NoError(t, err)
+	rich.ID, rich.Version = created.ID, 3
+	require.Equal(t, &rich, got)
+	got, err = repo.Get(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, &rich, got)
+
+	got, err = repo.Update(ctx, created.ID, &domain.Movie{Title: "Created 2", Year: 2000}, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, &domain.Movie{ID: created.ID, Title: "Created 2", Year: 2000, Version: 4}, got)
+
+	// colisão com outro filme; o título antigo fica livre
+	_, err = repo.Update(ctx, created.ID, &domain.Movie{Title: "Legacy 2", Year: 1901}, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrAlreadyExists)
+	var ce *domain.ConflictError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, "8", ce.ConflictingID)
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Legacy", Year: 1900})
+	require.NoError(t, err)
+}
+
+// testDelete: Delete manda para a lixeira; o filme some das leituras, mas
+// continua ocupando title+year e legacy_id.
+func testDelete(t *testing.T, repo ports.MovieRepository) {
+	ctx := context.Background()
+	_, err := repo.Create(ctx, &domain.Movie{Title: "Legacy", Year: 1900, LegacyID: "8", Synopsis: "S"})
+	require.NoError(t, err)
+	created, err := repo.Create(ctx, &domain.Movie{Title: "Created", Year: 2000})
+	require.NoError(t, err)
+
+	// devolve o filme já na lixeira, com nova versão
+	deleted, err := repo.Delete(ctx, "8", domain.AnyVersion)
+	require.NoError(t, err)
+	requireTrashed(t, deleted)
+	deleted.DeletedAt = nil
+	require.Equal(t, &domain.Movie{ID: "8", Title: "Legacy", Year: 1900, Synopsis: "S", Version: 2}, deleted)
+	deleted, err = repo.Delete(ctx, created.ID, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, created.ID, deleted.ID)
+
+	for _, id := range []string{"8", created.ID} {
+		_, err = repo.Get(ctx, id)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = repo.Delete(ctx, id, domain.AnyVersion)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = repo.Update(ctx, id, &domain.Movie{Title: "X", Year: 2001}, domain.AnyVersion)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	}
+	n, err := repo.Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+	page, err := repo.List(ctx, domain.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, page.Movies)
+	hits, err := repo.Search(ctx, domain.SearchOptions{Query: "legacy"})
+	require.NoError(t, err)
+	require.Empty(t, hits.Hits)
+
+	// chaves continuam ocupadas: restaurar nunca conflita, e o conflito
+	// avisa que quem ocupa está na lixeira
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Legacy", Year: 1900})
+	require.ErrorIs(t, err, domain.ErrAlreadyExists)
+	var ce *domain.ConflictError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, "8", ce.ConflictingID)
+	require.True(t, ce.Trashed)
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Other", Year: 1950, LegacyID: "8"})
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, []string{"id"}, ce.Fields)
+	require.True(t, ce.Trashed)
+	ins, err := repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{{Title: "Other", Year: 1900, LegacyID: "8"}})
+	require.NoError(t, err)
+	require.Zero(t, ins)
+}
+
+// testTrash: GetDeleted, List da lixeira, Restore e HardDelete.
+func testTrash(t *testing.T, repo ports.MovieRepository) {
+	ctx := context.Background()
+	a, err := repo.Create(ctx, &domain.Movie{Title: "A", Year: 2000})
+	require.NoError(t, err)
+	b, err := repo.Create(ctx, &domain.Movie{Title: "B", Year: 2000})
+	require.NoError(t, err)
+	live, err := repo.Create(ctx, &domain.Movie{Title: "Live", Year: 2000})
+	require.NoError(t, err)
+	for _, id := range []string{a.ID, b.ID} {
+		_, err = repo.Delete(ctx, id, 1)
+		require.NoError(t, err)
+	}
+
+	got, err := repo.GetDeleted(ctx, a.ID)
+	require.NoError(t, err)
+	requireTrashed(t, got)
+	require.Equal(t, "A", got.Title)
+	require.EqualValues(t, 2, got.Version)
+	_, err = repo.GetDeleted(ctx, live.ID)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// catálogo e lixeira não se misturam
+	page, err := repo.List(ctx, domain.ListOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{live.ID}, ids(page.Movies))
+	page, err = repo.List(ctx, domain.ListOptions{Deleted: true})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{a.ID, b.ID}, ids(page.Movies))
+	for _, m := range page.Movies {
+		requireTrashed(t, &m)
+	}
+	page, err = repo.List(ctx, domain.ListOptions{Deleted: true, DeletedBefore: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	require.Empty(t, page.Movies)
+	page, err = repo.List(ctx, domain.ListOptions{Deleted: true, DeletedBefore: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, page.Movies, 2)
+
+	// Restore condicional à versão; volta com nova versão e sem deleted_at
+	_, err = repo.Restore(ctx, a.ID, 1)
+	require.ErrorIs(t, err, domain.ErrVersionMismatch)
+	restored, err := repo.Restore(ctx, a.ID, 2)
+	require.NoError(t, err)
+	require.Nil(t, restored.DeletedAt)
+	require.EqualValues(t, 3, restored.Version)
+	got, err = repo.Get(ctx, a.ID)
+	require.NoError(t, err)
+	require.Equal(t, restored, got)
+	_, err = repo.Restore(ctx, a.ID, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// HardDelete só remove o que está na lixeira desde antes do corte
+	_, err = repo.HardDelete(ctx, b.ID, time.Now().Add(-time.Hour))
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.HardDelete(ctx, a.ID, time.Now().Add(time.Hour))
+	require.ErrorIs(t, err, domain.ErrNotFound)
+	purged, err := repo.HardDelete(ctx, b.ID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, b.ID, purged.ID)
+	requireTrashed(t, purged)
+	_, err = repo.GetDeleted(ctx, b.ID)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// só agora as chaves ficam livres
+	_, err = repo.Create(ctx, &domain.Movie{Title: "B", Year: 2000})
+	require.NoError(t, err)
+}
+
+func requireTrashed(t *testing.T, m *domain.Movie) {
+	t.Helper()
+	require.NotNil(t, m.DeletedAt, m.ID)
+	require.WithinDuration(t, time.Now(), *m.DeletedAt, time.Minute, m.ID)
+}
+
+// testVersions: Update e Delete condicionais à versão esperada.
+func testVersions(t *testing.T, repo ports.MovieRepository) {
+	ctx := context.Background()
+	_, err := repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{{Title: "Legacy", Year: 1900, LegacyID: "8"}})
+	require.NoError(t, err)
+	created, err := repo.Create(ctx, &domain.Movie{Title: "Created", Year: 2000})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, created.Version)
+
+	for _, id := range []string{"8", created.ID} {
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, got.Version, id)
+
+		got, err = repo.Update(ctx, id, &domain.Movie{Title: got.Title + " 2", Year: got.Year}, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, got.Version)
+
+		// versão velha: nada é gravado
+		_, err = repo.Update(ctx, id, &domain.Movie{Title: "Stale", Year: 1950}, 1)
+		require.ErrorIs(t, err, domain.ErrVersionMismatch)
+		_, err = repo.Delete(ctx, id, 1)
+		require.ErrorIs(t, err, domain.ErrVersionMismatch)
+		got, err = repo.Get(ctx, id)
+		require.NoError(t, err)
+		require.NotEqual(t, "Stale", got.Title)
+		require.EqualValues(t, 2, got.Version)
+
+		deleted, err := repo.Delete(ctx, id, 2)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, deleted.Version)
+	}
+}
+
+func testBulkInsert(t *testing.T, repo ports.MovieRepository) {
+	ctx := context.Background()
+	n, err := repo.BulkInsertIgnoreDuplicates(ctx, nil)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Existing", Year: 1900})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &domain.Movie{Title: "Existing Legacy", Year: 1900, LegacyID: "8"})
+	require.NoError(t, err)
+
+	n, err = repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "A", Year: 1900, LegacyID: "1"},
+		{Title: "A", Year: 1900, LegacyID: "2"},        // title+year repetido na lista
+		{Title: "B", Year: 1900, LegacyID: "1"},        // legacy_id repetido na lista
+		{Title: "Existing", Year: 1900, LegacyID: "3"}, // title+year já gravado
+		{Title: "C", Year: 1900, LegacyID: "8"},        // legacy_id já gravado
+		{Title: "D", Year: 1900, LegacyID: "4", Genres: []string{"Drama"}},
+		{Title: "E", Year: 1900},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	// o primeiro de cada chave é o que fica, com todos os campos
+	got, err := repo.Get(ctx, "1")
+	require.NoError(t, err)
+	require.Equal(t, "A", got.Title)
+	got, err = repo.Get(ctx, "4")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Drama"}, got.Genres)
+	for _, id := range []string{"2", "3"} {
+		_, err = repo.Get(ctx, id)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	}
+
+	// repetir o lote não insere nada
+	n, err = repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "A", Year: 1900, LegacyID: "1"},
+		{Title: "E", Year: 1900},
+	})
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func testCount(t *testing.T, repo ports.MovieRepository) {
+	ctx := context.Background()
+	count := func() int64 {
+		n, err := repo.Count(ctx)
+		require.NoError(t, err)
+		return n
+	}
+	require.Zero(t, count())
+
+	created, err := repo.Create(ctx, &domain.Movie{Title: "A", Year: 2000})
+	require.NoError(t, err)
+	_, err = repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{{Title: "B", Year: 2000}, {Title: "A", Year: 2000}})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count())
+
+	_, err = repo.Create(ctx, &domain.Movie{Title: "B", Year: 2000})
+	require.Error(t, err)
+	require.Equal(t, int64(2), count(), "create com conflito não grava")
+
+	_, err = repo.Delete(ctx, created.ID, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count())
+}
+
+// seedList catálogo do List: legacy_ids que só ordenam certo numericamente,
+// caixas misturadas e um filme criado pela API (sem legacy_id).
+func seedList(t *testing.T, repo ports.MovieRepository) string {
+	ctx := context.Background()
+	_, err := repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "The Kid", Year: 1921, LegacyID: "10"},
+		{Title: "The Gold Rush", Year: 1925, LegacyID: "2"},
+		{Title: "Metropolis", Year: 1927, LegacyID: "8"},
+		{Title: "the thin man", Year: 1934, LegacyID: "9"},
+		{Title: "The General", Year: 1926, LegacyID: "100"},
+		{Title: "City Lights", Year: 1931, LegacyID: "11"},
+	})
+	require.NoError(t, err)
+	created, err := repo.Create(ctx, &domain.Movie{Title: "Zelig", Year: 1983})
+	require.NoError(t, err)
+	return created.ID
+}
+
+func testListOrder(t *testing.T, repo ports.MovieRepository) {
+	seedList(t, repo)
+
+	// id: sem legacy_id primeiro, depois legacy_id em ordem numérica
+	require.Equal(t,
+		[]string{"Zelig", "The Gold Rush", "Metropolis", "the thin man", "The Kid", "City Lights", "The General"},
+		collect(t, repo, domain.ListOptions{}),
+	)
+	require.Equal(t,
+		[]string{"The General", "City Lights", "The Kid", "the thin man", "Metropolis", "The Gold Rush", "Zelig"},
+		collect(t, repo, domain.ListOptions{SortOrder: domain.SortDesc}),
+	)
+	// title: sem diferenciar maiúsculas
+	require.Equal(t,
+		[]string{"City Lights", "Metropolis", "The General", "The Gold Rush", "The Kid", "the thin man", "Zelig"},
+		collect(t, repo, domain.ListOptions{SortBy: domain.SortByTitle}),
+	)
+	require.Equal(t,
+		[]string{"Zelig", "the thin man", "City Lights", "Metropolis", "The General", "The Gold Rush", "The Kid"},
+		collect(t, repo, domain.ListOptions{SortBy: domain.SortByYear, SortOrder: domain.SortDesc}),
+	)
+
+	_, err := repo.List(context.Background(), domain.ListOptions{SortBy: "rating"})
+	require.ErrorIs(t, err, domain.ErrInvalidSortField)
+}
+
+func testListFilters(t *testing.T, repo ports.MovieRepository) {
+	seedList(t, repo)
+
+	require.Equal(t,
+		[]string{"The Gold Rush", "Metropolis", "The General"},
+		collect(t, repo, domain.ListOptions{MinYear: 1922, MaxYear: 1929}),
+	)
+	require.Equal(t,
+		[]string{"The Kid", "The Gold Rush", "The General", "the thin man"},
+		collect(t, repo, domain.ListOptions{TitlePrefix: "THE ", SortBy: domain.SortByYear}),
+	)
+	require.Equal(t,
+		[]string{"The General", "The Gold Rush"},
+		collect(t, repo, domain.ListOptions{TitlePrefix: "the g", MaxYear: 1930, SortBy: domain.SortByTitle}),
+	)
+	// prefixo é literal, não expressão
+	require.Empty(t, collect(t, repo, domain.ListOptions{TitlePrefix: "The.*"}))
+	require.Empty(t, collect(t, repo, domain.ListOptions{TitlePrefix: "The_"}))
+	require.Empty(t, collect(t, repo, domain.ListOptions{TitlePrefix: "%"}))
+
+	// dígitos no fim do prefixo: com ordenação numérica, "Part 10" vem
+	// depois de "Part 2", mas casa com "part 1"
+	_, err := repo.BulkInsertIgnoreDuplicates(context.Background(), []domain.Movie{
+		{Title: "Part 1", Year: 2001}, {Title: "Part 2", Year: 2002}, {Title: "Part 10", Year: 2010},
+	})
+	require.NoError(t, err)
+	require.Equal(t,
+		[]string{"Part 1", "Part 10"},
+		collect(t, repo, domain.ListOptions{TitlePrefix: "part 1", SortBy: domain.SortByTitle}),
+	)
+}
+
+func testListPaging(t *testing.T, repo ports.MovieRepository) {
+	createdID := seedList(t, repo)
+	ctx := context.Background()
+
+	// todas as ordenações, página a página, batem com a página única
+	for _, sortBy := range []string{domain.SortByID, domain.SortByTitle, domain.SortByYear} {
+		for _, order := range []string{domain.SortAsc, domain.SortDesc} {
+			opts := domain.ListOptions{SortBy: sortBy, SortOrder: order}
+			all := collect(t, repo, opts)
+			for _, size := range []int{1, 2, 3} {
+				opts.PageSize = size
+				require.Equal(t, all, collect(t, repo, opts), "%s %s size=%d", sortBy, order, size)
+			}
+		}
+	}
+
+	// última página cheia não gera token
+	page, err := repo.List(ctx, domain.ListOptions{PageSize: 7})
+	require.NoError(t, err)
+	require.Len(t, page.Movies, 7)
+	require.Empty(t, page.NextPageToken)
+
+	// AfterID: continua logo depois do id, na ordem por id
+	page, err = repo.List(ctx, domain.ListOptions{AfterID: "8", PageSize: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"9", "10"}, ids(page.Movies))
+	page, err = repo.List(ctx, domain.ListOptions{AfterID: createdID, PageSize: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2"}, ids(page.Movies))
+	page, err = repo.List(ctx, domain.ListOptions{AfterID: "100"})
+	require.NoError(t, err)
+	require.Empty(t, page.Movies)
+
+	// token vale só para a ordenação que o gerou
+	page, err = repo.List(ctx, domain.ListOptions{PageSize: 1, SortBy: domain.SortByYear})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.NextPageToken)
+	for _, opts := range []domain.ListOptions{
+		{PageToken: page.NextPageToken, SortBy: domain.SortByTitle},
+		{PageToken: page.NextPageToken, SortBy: domain.SortByYear, SortOrder: domain.SortDesc},
+		{PageToken: "%%%"},
+		{PageToken: "e30"}, // {}
+	} {
+		_, err = repo.List(ctx, opts)
+		require.ErrorIs(t, err, domain.ErrInvalidPageToken, "%+v", opts)
+	}
+}
+
+// testSearch só o que todo backend garante: casa termos sem acentos e
+// maiúsculas, ordena por score e pagina sem repetir. Os valores do score e
+// o stemming variam por backend.
+func testSearch(t *testing.T, repo ports.MovieRepository) {
+	ctx := context.Background()
+	_, err := repo.BulkInsertIgnoreDuplicates(ctx, []domain.Movie{
+		{Title: "The Great Train Robbery", Year: 1903, LegacyID: "1"},
+		{Title: "Train Robbery Remake", Year: 1950, LegacyID: "2"},
+		{Title: "The Kid", Year: 1921, LegacyID: "3", Synopsis: "A tramp finds a child after a robbery."},
+		{Title: "La sortie des usines Lumière", Year: 1895, LegacyID: "4"},
+		{Title: "Metropolis", Year: 1927, LegacyID: "5", Directors: []string{"Fritz Lang"}},
+	})
+	require.NoError(t, err)
+
+	search := func(opts domain.SearchOptions) []string {
+		var out []string
+		last := -1.0
+		for {
+			page, err := repo.Search(ctx, opts)
+			require.NoError(t, err)
+			for _, h := range page.Hits {
+				require.Positive(t, h.Score)
+				if last >= 0 {
+					require.LessOrEqual(t, h.Score, last, "score decrescente")
+				}
+				last = h.Score
+				out = append(out, h.Movie.ID)
+			}
+			if page.NextPageToken == "" {
+				return out
+			}
+			opts.PageToken = page.NextPageToken
+		}
+	}
+
+	all := search(domain.SearchOptions{Query: "train robbery"})
+	require.ElementsMatch(t, []string{"1", "2", "3"}, all)
+	require.Equal(t, "3", all[2], "título pesa mais que sinopse")
+	require.Equal(t, all, search(domain.SearchOptions{Query: "train robbery", PageSize: 1}))
+	require.ElementsMatch(t, []string{"1", "3"}, search(domain.SearchOptions{Query: "train robbery", MaxYear: 1949}))
+	require.Equal(t, []string{"4"}, search(domain.SearchOptions{Query: "LUMIERE"}))
+	require.Equal(t, []string{"5"}, search(domain.SearchOptions{Query: "lang"}))
+	require.Equal(t, []string{"1", "3"}, search(domain.SearchOptions{Query: "robbery -remake"}))
+	require.Empty(t, search(domain.SearchOptions{Query: "nosferatu"}))
+
+	page, err := repo.Search(ctx, domain.SearchOptions{Query: "robbery", PageSize: 1})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.NextPageToken)
+	_, err = repo.Search(ctx, domain.SearchOptions{Query: "train", PageToken: page.NextPageToken})
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken, "token de outra query")
+}
+
+// collect percorre todas as páginas e devolve os títulos na ordem.
+func collect(t *testing.T, repo ports.MovieRepository, opts domain.ListOptions) []string {
+	t.Helper()
+	var titles []string
+	for {
+		page, err := repo.List(context.Background(), opts)
+		require.NoError(t, err)
+		for _, m := range page.Movies {
+			titles = append(titles, m.Title)
+		}
+		if page.NextPageToken == "" {
+			return titles
+		}
+		opts.PageToken = page.NextPageToken
+	}
+}
+
+func ids(ms []domain.Movie) []string {
+	out := make([]string, 0, len(ms))
+	for _, m := range ms {
+		out = append(out, m.ID)
+	}
+	return out
+}
+
+func isObjectID(id string) bool {
+	b, err := hex.DecodeString(id)
+	return err == nil && len(b) == 12
+}
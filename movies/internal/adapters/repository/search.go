@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// searchWeights campos do índice de texto e seu peso no score. Campos
+// ausentes no documento (seed antigo) simplesmente não contribuem.
+var searchWeights = bson.D{
+	{Key: "title", Value: 10},
+	{Key: "original_title", Value: 5},
+	{Key: "directors", Value: 3},
+	{Key: "cast", Value: 3},
+	{Key: "genres", Value: 2},
+	{Key: "synopsis", Value: 1},
+}
+
+// searchIndex índice de texto (só pode haver um por coleção). A versão 3
+// do índice ignora acentos e maiúsculas: "lumiere" casa com "Lumière".
+// language_override aponta para um campo inexistente para que nenhum
+// documento troque o idioma de stemming.
+func searchIndex() mongo.IndexModel {
+	keys := make(bson.D, 0, len(searchWeights))
+	for _, w := range searchWeights {
+		keys = append(keys, bson.E{Key: w.Key, Value: "text"})
+	}
+	return mongo.IndexModel{
+		Keys: keys,
+		Options: options.Index().
+			SetName("search_text").
+			SetWeights(searchWeights).
+			SetDefaultLanguage("english").
+			SetLanguageOverride("search_language").
+			SetTextVersion(3),
+	}
+}
+
+// scoreField campo calculado com o textScore no pipeline da busca.
+const scoreField = "_score"
+
+// Search busca por relevância com $text. A ordem (score desc, _id asc) é
+// total, então a paginação usa keyset sobre o par, como o List.
+func (r *MongoRepository) Search(ctx context.Context, opts domain.SearchOptions) (domain.SearchPage, error) {
+	opts.Normalize()
+	match := bson.D{{Key: "$text", Value: bson.D{
+		{Key: "$search", Value: opts.Query},
+		{Key: "$caseSensitive", Value: false},
+		{Key: "$diacriticSensitive", Value: false},
+	}}}
+	match = append(match, listFilter(domain.ListOptions{MinYear: opts.MinYear, MaxYear: opts.MaxYear})...)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.D{{Key: scoreField, Value: bson.D{{Key: "$meta", Value: "textScore"}}}}}},
+	}
+	if opts.PageToken != "" {
+		c, err := decodeSearchCursor(opts.PageToken, opts)
+		if err != nil {
+			return domain.SearchPage{}, err
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: c.after()}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: bson.D{{Key: scoreField, Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$limit", Value: int64(opts.PageSize) + 1}}, // +1 para saber se existe próxima página
+	)
+
+	cur, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return domain.SearchPage{}, err
+	}
+	defer cur.Close(ctx)
+
+	var hits []scoredMovie
+	if err := cur.All(ctx, &hits); err != nil {
+		return domain.SearchPage{}, err
+	}
+
+	var page domain.SearchPage
+	if len(hits) > opts.PageSize {
+		hits = hits[:opts.PageSize]
+		last := hits[len(hits)-1]
+		page.NextPageToken = searchCursor{
+			Spec:  searchSpec(opts),
+			Score: last.Score,
+			ID:    last.ID.Hex(),
+		}.encode()
+	}
+	page.Hits = make([]domain.SearchHit, 0, len(hits))
+	for _, h := range hits {
+		page.Hits = append(page.Hits, domain.SearchHit{Movie: h.toDomain(), Score: h.Score})
+	}
+	return page, nil
+}
+
+type scoredMovie struct {
+	dbMovie `bson:",inline"`
+	Score   float64 `bson:"_score"`
+}
+
+// searchCursor score e _id do último resultado entregue, mais um hash da
+// query e filtros que o geraram.
+type searchCursor struct {
+	Spec  string  `json:"q"`
+	Score float64 `json:"s"`
+	ID    string  `json:"i"`
+}
+
+func searchSpec(opts domain.SearchOptions) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%d|%d", opts.Query, opts.MinYear, opts.MaxYear)
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+func (c searchCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeSearchCursor rejeita tokens malformados ou de outra busca.
+func decodeSearchCursor(tok string, opts domain.SearchOptions) (searchCursor, error) {
+	var c searchCursor
+	b, err := base64.RawURLEncoding.DecodeString(tok)
+	if err != nil {
+		return c, domain.ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, domain.ErrInvalidPageToken
+	}
+	if c.Spec != searchSpec(opts) {
+		return c, domain.ErrInvalidPageToken
+	}
+	if _, err := primitive.ObjectIDFromHex(c.ID); err != nil {
+		return c, domain.ErrInvalidPageToken
+	}
+	return c, nil
+}
+
+// after filtra "estritamente depois do cursor" em (score desc, _id asc).
+func (c searchCursor) after() bson.D {
+	oid, _ := primitive.ObjectIDFromHex(c.ID)
+	return bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: scoreField, Value: bson.D{{Key: "$lt", Value: c.Score}}}},
+		bson.D{{Key: scoreField, Value: c.Score}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: oid}}}},
+	}}}
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSearchCursor_RoundTripAndQueryBinding(t *testing.T) {
+	opts := domain.SearchOptions{Query: "train robbery", MinYear: 1900}
+	oid := primitive.NewObjectID()
+
+	tok := searchCursor{Spec: searchSpec(opts), Score: 7.25, ID: oid.Hex()}.encode()
+	c, err := decodeSearchCursor(tok, opts)
+	require.NoError(t, err)
+	require.Equal(t, bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: scoreField, Value: bson.D{{Key: "$lt", Value: 7.25}}}},
+		bson.D{{Key: scoreField, Value: 7.25}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: oid}}}},
+	}}}, c.after())
+
+	for _, other := range []domain.SearchOptions{
+		{Query: "train", MinYear: 1900},
+		{Query: "train robbery"},
+	} {
+		_, err = decodeSearchCursor(tok, other)
+		require.ErrorIs(t, err, domain.ErrInvalidPageToken)
+	}
+	_, err = decodeSearchCursor("not-base64!", opts)
+	require.ErrorIs(t, err, domain.ErrInvalidPageToken)
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var _ ports.Transactor = (*MongoTransactor)(nil)
+
+// ErrTransactionsUnavailable indica um Mongo standalone, sem transações
+// multi-documento.
+var ErrTransactionsUnavailable = errors.New("mongo: transactions unavailable (standalone server; use a replica set)")
+
+// MongoTransactor usa transações multi-documento do Mongo (exige replica set
+// ou mongos). Sem elas o outbox perderia a atomicidade, então o construtor
+// falha em vez de gravar filme e evento separadamente.
+type MongoTransactor struct {
+	client *mongo.Client
+}
+
+func NewMongoTransactor(ctx context.Context, client *mongo.Client) (*MongoTransactor, error) {
+	if !supportsTransactions(ctx, client) {
+		return nil, ErrTransactionsUnavailable
+	}
+	return &MongoTransactor{client: client}, nil
+}
+
+func supportsTransactions(ctx context.Context, client *mongo.Client) bool {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid"
+}
+
+func (t *MongoTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	sess, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
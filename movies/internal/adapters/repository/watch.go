@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ ports.MovieWatcher = (*MongoWatcher)(nil)
+
+// Códigos do servidor para resume token que não está mais no oplog.
+const (
+	codeChangeStreamHistoryLost = 286
+	codeChangeStreamFatalError  = 280
+)
+
+// MongoWatcher implementa Watch com change streams na coleção de filmes
+// (exige replica set ou mongos). O resume token é o do próprio change
+// stream, em base64url.
+type MongoWatcher struct {
+	col *mongo.Collection
+}
+
+// NewMongoWatcher liga pre-images na coleção (Mongo 6+) para que remoções
+// tragam o filme completo; sem elas o delete traz só o id.
+func NewMongoWatcher(ctx context.Context, col *mongo.Collection) *MongoWatcher {
+	err := col.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: col.Name()},
+		{Key: "changeStreamPreAndPostImages", Value: bson.D{{Key: "enabled", Value: true}}},
+	}).Err()
+	if err != nil {
+		log.Printf("mongo: change stream pre-images unavailable (%v); deletes will carry only the id", err)
+	}
+	return &MongoWatcher{col: col}
+}
+
+// SupportsChangeStreams diz se o servidor oferece change streams (mesma
+// condição das transações: replica set ou mongos).
+func SupportsChangeStreams(ctx context.Context, client *mongo.Client) bool {
+	return supportsTransactions(ctx, client)
+}
+
+type changeEvent struct {
+	OperationType string   `bson:"operationType"`
+	FullDocument  *dbMovie `bson:"fullDocument"`
+	BeforeChange  *dbMovie `bson:"fullDocumentBeforeChange"`
+	DocumentKey   struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	UpdateDescription struct {
+		UpdatedFields bson.M   `bson:"updatedFields"`
+		RemovedFields []string `bson:"removedFields"`
+	} `bson:"updateDescription"`
+}
+
+var changeTypes = map[string]string{
+	"insert":  domain.EventMovieCreated,
+	"update":  domain.EventMovieUpdated,
+	"replace": domain.EventMovieUpdated,
+	"delete":  domain.EventMovieDeleted,
+}
+
+func (w *MongoWatcher) Watch(ctx context.Context, resumeToken string, fn func(domain.MovieChange) error) error {
+	opts := options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+	if resumeToken != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(resumeToken)
+		if err != nil || bson.Raw(raw).Validate() != nil {
+			return domain.ErrInvalidResumeToken
+		}
+		opts.SetResumeAfter(bson.Raw(raw))
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "operationType", Value: bson.D{{Key: "$in", Value: bson.A{"insert", "update", "replace", "delete"}}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "$expr", Value: bson.D{{Key: "$not", Value: bson.A{backfillOnly}}}}}}},
+	}
+
+	cs, err := w.col.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return watchErr(err)
+	}
+	defer cs.Close(context.Background())
+
+	for cs.Next(ctx) {
+		var ev changeEvent
+		if err := cs.Decode(&ev); err != nil {
+			return err
+		}
+		if err := fn(ev.toDomain(base64.RawURLEncoding.EncodeToString(cs.ResumeToken()))); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return watchErr(cs.Err())
+}
+
+// backfillOnly casa updates que só gravam backfillFields (o $and para no
+// primeiro falso, então os campos de updateDescription só são lidos em
+// update).
+var backfillOnly = bson.D{{Key: "$and", Value: bson.A{
+	bson.D{{Key: "$eq", Value: bson.A{"$operationType", "update"}}},
+	bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$size", Value: "$updateDescription.removedFields"}}, 0}}},
+	bson.D{{Key: "$setIsSubset", Value: bson.A{
+		bson.D{{Key: "$map", Value: bson.D{
+			{Key: "input", Value: bson.D{{Key: "$objectToArray", Value: "$updateDescription.updatedFields"}}},
+			{Key: "in", Value: "$$this.k"},
+		}}},
+		backfillFields,
+	}}},
+}}}
+
+func watchErr(err error) error {
+	var se mongo.ServerError
+	if errors.As(err, &se) && (se.HasErrorCode(codeChangeStreamHistoryLost) || se.HasErrorCode(codeChangeStreamFatalError)) {
+		return domain.ErrInvalidResumeToken
+	}
+	return err
+}
+
+// changeType traduz a operação. Update que grava deleted_at é o filme indo
+// para a lixeira (DELETED) e update que o remove é o restore (CREATED),
+// como os eventos que o serviço publica; o delete de fato é o purge.
+func (e changeEvent) changeType() string {
+	if e.OperationType == "update" {
+		if _, ok := e.UpdateDescription.UpdatedFields["deleted_at"]; ok {
+			return domain.EventMovieDeleted
+		}
+		if slices.Contains(e.UpdateDescription.RemovedFields, "deleted_at") {
+			return domain.EventMovieCreated
+		}
+	}
+	return changeTypes[e.OperationType]
+}
+
+// toDomain usa o documento depois da mudança; no delete, o pre-image se
+// houver, senão só o id.
+func (e changeEvent) toDomain(token string) domain.MovieChange {
+	c := domain.MovieChange{Type: e.changeType(), ResumeToken: token}
+	switch {
+	case e.FullDocument != nil:
+		c.Movie = e.FullDocument.toDomain()
+	case e.BeforeChange != nil:
+		c.Movie = e.BeforeChange.toDomain()
+	default:
+		c.Movie = dbMovie{ID: e.DocumentKey.ID}.toDomain()
+	}
+	return c
+}
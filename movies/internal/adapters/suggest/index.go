@@ -0,0 +1,245 @@
+package suggest
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+)
+
+var _ ports.TitleIndex = (*Index)(nil)
+
+// Index é o TitleIndex em memória. Cada título gera uma chave por palavra
+// (domain.FoldText a partir dela), para que "robbery" também sugira "The
+// Great Train Robbery". As chaves ficam em baldes na ordem do ranking do
+// Suggest (começo do título antes, depois os anos mais recentes), cada
+// balde ordenado por texto: um prefixo vira uma busca binária por balde, e
+// a busca para no primeiro balde que completa o limite.
+// Só enxerga as escritas deste processo (ver Load).
+type Index struct {
+	mu      sync.RWMutex
+	buckets map[bucket][]key // cada um ordenado por (text, id)
+	order   []bucket         // baldes na ordem do ranking
+	movies  map[string]entry
+	gone    map[string]int64 // versão com que cada filme saiu (Remove)
+}
+
+type key struct {
+	text  string
+	id    string
+	start bool // chave a partir da primeira palavra do título
+}
+
+// bucket agrupa as chaves de mesmo start e mesmo ano do filme.
+type bucket struct {
+	start bool
+	year  int
+}
+
+type entry struct {
+	s       domain.TitleSuggestion
+	folded  string // título normalizado (desempate alfabético)
+	version int64
+	keys    []key
+}
+
+func NewIndex() *Index {
+	return &Index{buckets: make(map[bucket][]key), movies: make(map[string]entry), gone: make(map[string]int64)}
+}
+
+// Load substitui o conteúdo do índice pelo catálogo do repositório
+// (chamado no boot, depois do seed) e devolve quantos filmes indexou.
+func (x *Index) Load(ctx context.Context, repo ports.MovieRepository) (int, error) {
+	movies := make(map[string]entry)
+	buckets := make(map[bucket][]key)
+	opts := domain.ListOptions{PageSize: domain.MaxPageSize}
+	for {
+		page, err := repo.List(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		for _, m := range page.Movies {
+			e := newEntry(m)
+			movies[m.ID] = e
+			for _, k := range e.keys {
+				b := bucket{k.start, m.Year}
+				buckets[b] = append(buckets[b], k)
+			}
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = page.NextPageToken
+	}
+	order := make([]bucket, 0, len(buckets))
+	for b, keys := range buckets {
+		sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+		order = append(order, b)
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i].compare(order[j]) < 0 })
+
+	x.mu.Lock()
+	x.buckets, x.order, x.movies, x.gone = buckets, order, movies, make(map[string]int64)
+	x.mu.Unlock()
+	return len(movies), nil
+}
+
+// Put indexa m, substituindo a versão anterior (título pode ter mudado).
+// O serviço chama Put depois do commit, então duas escritas concorrentes
+// podem chegar fora de ordem: versão mais velha que a indexada, ou que a
+// do Remove, é ignorada.
+func (x *Index) Put(m domain.Movie) {
+	e := newEntry(m)
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if cur, ok := x.movies[m.ID]; ok && m.Version < cur.version {
+		return
+	}
+	if v, ok := x.gone[m.ID]; ok {
+		if m.Version <= v {
+			return
+		}
+		delete(x.gone, m.ID)
+	}
+	x.remove(m.ID)
+	for _, k := range e.keys {
+		x.insert(bucket{k.start, m.Year}, k)
+	}
+	x.movies[m.ID] = e
+}
+
+// Remove tira id do índice, salvo se ele já foi indexado numa versão mais
+// nova (restore que chegou antes). A versão fica guardada para barrar um
+// Put atrasado de antes da remoção.
+func (x *Index) Remove(id string, version int64) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if cur, ok := x.movies[id]; ok && version < cur.version {
+		return
+	}
+	x.remove(id)
+	x.gone[id] = max(x.gone[id], version)
+}
+
+func (x *Index) insert(b bucket, k key) {
+	keys, ok := x.buckets[b]
+	if !ok {
+		i := sort.Search(len(x.order), func(i int) bool { return x.order[i].compare(b) >= 0 })
+		x.order = slices.Insert(x.order, i, b)
+	}
+	i := sort.Search(len(keys), func(i int) bool { return !keys[i].less(k) })
+	x.buckets[b] = slices.Insert(keys, i, k)
+}
+
+func (x *Index) remove(id string) {
+	e, ok := x.movies[id]
+	if !ok {
+		return
+	}
+	for _, k := range e.keys {
+		b := bucket{k.start, e.s.Year}
+		keys := x.buckets[b]
+		i := sort.Search(len(keys), func(i int) bool { return !keys[i].less(k) })
+		if i < len(keys) && keys[i] == k {
+			keys = slices.Delete(keys, i, i+1)
+		}
+		if len(keys) > 0 {
+			x.buckets[b] = keys
+			continue
+		}
+		delete(x.buckets, b)
+		if j, found := slices.BinarySearchFunc(x.order, b, bucket.compare); found {
+			x.order = slices.Delete(x.order, j, j+1)
+		}
+	}
+	delete(x.movies, id)
+}
+
+// Suggest devolve até opts.Limit filmes com alguma palavra do título
+// começando pelo prefixo (sem diferenciar maiúsculas e acentos). Títulos
+// que começam pelo prefixo vêm antes; depois, os mais recentes; empate
+// em ordem alfabética.
+func (x *Index) Suggest(opts domain.SuggestOptions) []domain.TitleSuggestion {
+	prefix := domain.FoldText(opts.Prefix)
+	if prefix == "" || opts.Limit <= 0 {
+		return nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	seen := map[string]bool{}
+	var out []domain.TitleSuggestion
+	for _, b := range x.order {
+		if len(out) >= opts.Limit {
+			break
+		}
+		// o balde inteiro entra antes do corte: o desempate alfabético é
+		// pelo título, não pela chave
+		keys := x.buckets[b]
+		var hits []entry
+		i := sort.Search(len(keys), func(i int) bool { return keys[i].text >= prefix })
+		for ; i < len(keys) && strings.HasPrefix(keys[i].text, prefix); i++ {
+			if id := keys[i].id; !seen[id] {
+				seen[id] = true
+				hits = append(hits, x.movies[id])
+			}
+		}
+		sort.Slice(hits, func(i, j int) bool {
+			if hits[i].folded != hits[j].folded {
+				return hits[i].folded < hits[j].folded
+			}
+			return hits[i].s.ID < hits[j].s.ID
+		})
+		for _, h := range hits {
+			out = append(out, h.s)
+		}
+	}
+	if len(out) > opts.Limit {
+		out = out[:opts.Limit]
+	}
+	return out
+}
+
+// newEntry gera uma chave por palavra do título (sem repetir texto).
+func newEntry(m domain.Movie) entry {
+	e := entry{
+		s:       domain.TitleSuggestion{ID: m.ID, Title: m.Title, Year: m.Year},
+		folded:  domain.FoldText(m.Title),
+		version: m.Version,
+	}
+	words := strings.Fields(e.folded)
+	seen := make(map[string]bool, len(words))
+	for i := range words {
+		text := strings.Join(words[i:], " ")
+		if seen[text] {
+			continue
+		}
+		seen[text] = true
+		e.keys = append(e.keys, key{text: text, id: m.ID, start: i == 0})
+	}
+	return e
+}
+
+func (k key) less(o key) bool {
+	if k.text != o.text {
+		return k.text < o.text
+	}
+	return k.id < o.id
+}
+
+// compare ordena os baldes como o ranking: começo do título antes, depois
+// os anos mais recentes.
+func (b bucket) compare(o bucket) int {
+	switch {
+	case b.start != o.start && b.start:
+		return -1
+	case b.start != o.start:
+		return 1
+	}
+	return cmp.Compare(o.year, b.year)
+}
@@ -0,0 +1,136 @@
+package suggest
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+	"github.com/stretchr/testify/require"
+)
+
+// listRepo serve o catálogo em páginas de 2; o resto de MovieRepository
+// não é usado pelo Load.
+type listRepo struct {
+	ports.MovieRepository
+	movies []domain.Movie
+}
+
+func (r listRepo) List(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error) {
+	start, _ := strconv.Atoi(opts.PageToken)
+	end := min(start+2, len(r.movies))
+	page := domain.MoviePage{Movies: r.movies[start:end]}
+	if end < len(r.movies) {
+		page.NextPageToken = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+func ids(ss []domain.TitleSuggestion) []string {
+	out := make([]string, 0, len(ss))
+	for _, s := range ss {
+		out = append(out, s.ID)
+	}
+	return out
+}
+
+func newTestIndex() *Index {
+	x := NewIndex()
+	for _, m := range []domain.Movie{
+		{ID: "1", Title: "The Great Train Robbery", Year: 1903},
+		{ID: "2", Title: "La sortie des usines Lumière", Year: 1895},
+		{ID: "3", Title: "Lumière and Company", Year: 1995},
+		{ID: "4", Title: "The Kid", Year: 1921},
+		{ID: "5", Title: "The Kid Brother", Year: 1927},
+		{ID: "6", Title: "Train of Thought", Year: 1921},
+	} {
+		x.Put(m)
+	}
+	return x
+}
+
+func TestIndex_CaseAndAccentInsensitive(t *testing.T) {
+	x := newTestIndex()
+
+	// começo do título antes de palavra do meio; depois, mais recentes primeiro
+	require.Equal(t, []string{"3", "2"}, ids(x.Suggest(domain.SuggestOptions{Prefix: "LUMIE", Limit: 10})))
+	require.Equal(t, []string{"3", "2"}, ids(x.Suggest(domain.SuggestOptions{Prefix: "lumiè", Limit: 10})))
+	require.Equal(t, []string{"6", "1"}, ids(x.Suggest(domain.SuggestOptions{Prefix: "tra", Limit: 10})))
+	require.Equal(t, []string{"5", "4", "1"}, ids(x.Suggest(domain.SuggestOptions{Prefix: "the", Limit: 10})))
+	require.Equal(t, []string{"5", "4"}, ids(x.Suggest(domain.SuggestOptions{Prefix: "the  kid", Limit: 10})))
+	require.Equal(t, []string{"5"}, ids(x.Suggest(domain.SuggestOptions{Prefix: "the", Limit: 1})))
+	require.Empty(t, x.Suggest(domain.SuggestOptions{Prefix: "zzz", Limit: 10}))
+
+	got := x.Suggest(domain.SuggestOptions{Prefix: "great", Limit: 10})
+	require.Equal(t, []domain.TitleSuggestion{{ID: "1", Title: "The Great Train Robbery", Year: 1903}}, got)
+}
+
+func TestIndex_PutReplacesAndRemove(t *testing.T) {
+	x := newTestIndex()
+
+	x.Put(domain.Movie{ID: "1", Title: "The Great Heist", Year: 1903})
+	require.Equal(t, []string{"6"}, ids(x.Suggest(domain.SuggestOptions{Prefix: "train", Limit: 10})))
+	require.Equal(t, []string{"1"}, ids(x.Suggest(domain.SuggestOptions{Prefix: "heist", Limit: 10})))
+
+	x.Remove("6", 0)
+	x.Remove("missing", 0)
+	require.Empty(t, x.Suggest(domain.SuggestOptions{Prefix: "train", Limit: 10}))
+	n := 0
+	for _, keys := range x.buckets {
+		n += len(keys)
+	}
+	require.Equal(t, 3+5+3+2+3, n) // uma chave por palavra dos filmes restantes
+	require.Len(t, x.order, len(x.buckets))
+}
+
+// escritas concorrentes chegam fora de ordem: vale a versão mais nova
+func TestIndex_IgnoresStaleWrites(t *testing.T) {
+	x := NewIndex()
+	x.Put(domain.Movie{ID: "1", Title: "New Title", Year: 2000, Version: 3})
+	x.Put(domain.Movie{ID: "1", Title: "Old Title", Year: 2000, Version: 2})
+	require.Equal(t, []string{"1"}, ids(x.Suggest(domain.SuggestOptions{Prefix: "new", Limit: 10})))
+	require.Empty(t, x.Suggest(domain.SuggestOptions{Prefix: "old", Limit: 10}))
+
+	// update atrasado depois do delete não ressuscita o filme
+	x.Remove("1", 4)
+	x.Put(domain.Movie{ID: "1", Title: "New Title", Year: 2000, Version: 3})
+	require.Empty(t, x.Suggest(domain.SuggestOptions{Prefix: "new", Limit: 10}))
+
+	// restore (versão seguinte) volta; o delete velho que chega depois, não apaga
+	x.Put(domain.Movie{ID: "1", Title: "New Title", Year: 2000, Version: 6})
+	x.Remove("1", 4)
+	require.Equal(t, []string{"1"}, ids(x.Suggest(domain.SuggestOptions{Prefix: "new", Limit: 10})))
+}
+
+// o corte por baldes devolve o mesmo que ordenar todos os resultados
+func TestIndex_LimitMatchesFullRanking(t *testing.T) {
+	x := NewIndex()
+	var want []domain.TitleSuggestion
+	for i := range 300 {
+		title := "Movie " + strconv.Itoa(i)
+		if i%3 == 0 {
+			title = "The Movie " + strconv.Itoa(i) // só casa por palavra do meio
+		}
+		m := domain.Movie{ID: strconv.Itoa(i), Title: title, Year: 1990 + i%7}
+		x.Put(m)
+		want = append(want, domain.TitleSuggestion{ID: m.ID, Title: m.Title, Year: m.Year})
+	}
+	// começo do título antes, depois mais recentes, depois alfabético
+	sort.Slice(want, func(i, j int) bool {
+		a, b := want[i], want[j]
+		as, bs := !strings.HasPrefix(a.Title, "The"), !strings.HasPrefix(b.Title, "The")
+		switch {
+		case as != bs:
+			return as
+		case a.Year != b.Year:
+			return a.Year > b.Year
+		}
+		return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+	})
+	for _, limit := range []int{1, 5, 42, 199, 200, 201, 299, 300, 1000} {
+		require.Equal(t, want[:min(limit, len(want))], x.Suggest(domain.SuggestOptions{Prefix: "mov", Limit: limit}), limit)
+	}
+}
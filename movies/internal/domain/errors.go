@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// Restrições reportadas em FieldViolation.Constraint.
+const (
+	ConstraintRequired = "required"
+	ConstraintRange    = "range"
+	ConstraintUnique   = "unique"
+	ConstraintInvalid  = "invalid"
+)
+
+// FieldViolation descreve um campo inválido (nomes iguais aos do proto/JSON).
+type FieldViolation struct {
+	Field       string
+	Constraint  string
+	Description string
+}
+
+// ValidationError reúne as violações encontradas numa validação.
+// errors.Is(err, ErrValidation) continua valendo.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+": "+v.Description)
+	}
+	return ErrValidation.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool { return target == ErrValidation }
+
+// add acumula uma violação.
+func (e *ValidationError) add(field, constraint, desc string) {
+	e.Violations = append(e.Violations, FieldViolation{Field: field, Constraint: constraint, Description: desc})
+}
+
+// orNil devolve nil quando não houve violações.
+func (e *ValidationError) orNil() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ConflictError filme que violaria a unicidade de Fields (ex.: title+year)
+// por já existir ConflictingID. errors.Is(err, ErrAlreadyExists) continua
+// valendo.
+type ConflictError struct {
+	Fields        []string
+	ConflictingID string // vazio se não foi possível identificar
+	// Trashed o filme conflitante está na lixeira: em vez de recriar, o
+	// cliente pode restaurá-lo.
+	Trashed bool
+}
+
+func (e *ConflictError) Error() string {
+	msg := ErrAlreadyExists.Error() + " (" + strings.Join(e.Fields, "+") + ")"
+	if e.ConflictingID != "" {
+		msg += ": id " + e.ConflictingID
+	}
+	if e.Trashed {
+		msg += " (in trash)"
+	}
+	return msg
+}
+
+func (e *ConflictError) Is(target error) bool { return target == ErrAlreadyExists }
+
+// Violations lista as violações de err (ValidationError ou ConflictError);
+// nil para outros erros.
+func Violations(err error) []FieldViolation {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ve.Violations
+	}
+	var ce *ConflictError
+	if errors.As(err, &ce) {
+		desc := "another movie has the same " + strings.Join(ce.Fields, "+")
+		switch {
+		case ce.ConflictingID != "" && ce.Trashed:
+			desc += " (id " + ce.ConflictingID + ", in trash)"
+		case ce.ConflictingID != "":
+			desc += " (id " + ce.ConflictingID + ")"
+		case ce.Trashed:
+			desc += " (in trash)"
+		}
+		out := make([]FieldViolation, 0, len(ce.Fields))
+		for _, f := range ce.Fields {
+			out = append(out, FieldViolation{Field: f, Constraint: ConstraintUnique, Description: desc})
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Tipos de evento de domínio publicados pelo serviço movies.
+const (
+	EventMovieCreated = "movies.created"
+	EventMovieUpdated = "movies.updated"
+	EventMovieDeleted = "movies.deleted"
+	// EventMovieSnapshot republica o estado atual de um filme (replay para
+	// popular consumidores novos); não corresponde a uma alteração.
+	EventMovieSnapshot = "movies.snapshot"
+)
+
+// MovieEvent é um evento de filme como entregue aos consumidores.
+type MovieEvent struct {
+	ID         string
+	Type       string
+	OccurredAt time.Time
+	// Movie é o filme criado/removido/republicado ou, em movies.updated, o
+	// estado depois.
+	Movie Movie
+	// Before só vem em movies.updated.
+	Before *Movie
+}
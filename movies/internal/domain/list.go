@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidPageToken = errors.New("invalid page token")
+	ErrInvalidSortField = errors.New("invalid sort field")
+	ErrInvalidSortOrder = errors.New("invalid sort order")
+	ErrInvalidYearRange = errors.New("invalid year range")
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Campos aceitos em ListOptions.SortBy. Vazio equivale a SortByID
+// (ordem do catálogo: legacy_id numérico, depois title).
+const (
+	SortByID    = "id"
+	SortByTitle = "title"
+	SortByYear  = "year"
+)
+
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// ListOptions parâmetros de filtro, ordenação e paginação por cursor (keyset) do List.
+type ListOptions struct {
+	PageSize  int
+	PageToken string // opaco; vazio = primeira página
+	// AfterID começa logo depois do filme com este id, na ordenação pedida.
+	// Alternativa ao PageToken para retomar de um id conhecido (checkpoint).
+	AfterID string
+
+	MinYear     int    // 0 = sem limite
+	MaxYear     int    // 0 = sem limite
+	TitlePrefix string // case-insensitive
+
+	SortBy    string
+	SortOrder string
+
+	// Deleted lista a lixeira (filmes apagados e ainda não purgados) em
+	// vez do catálogo.
+	Deleted bool
+	// DeletedBefore com Deleted, só filmes apagados antes deste instante
+	// (zero = sem limite).
+	DeletedBefore time.Time
+}
+
+// Normalize aplica defaults e teto ao tamanho da página.
+func (o *ListOptions) Normalize() {
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultPageSize
+	}
+	if o.PageSize > MaxPageSize {
+		o.PageSize = MaxPageSize
+	}
+	o.TitlePrefix = strings.TrimSpace(o.TitlePrefix)
+	o.SortBy = strings.ToLower(strings.TrimSpace(o.SortBy))
+	if o.SortBy == "" {
+		o.SortBy = SortByID
+	}
+	o.SortOrder = strings.ToLower(strings.TrimSpace(o.SortOrder))
+	if o.SortOrder == "" {
+		o.SortOrder = SortAsc
+	}
+}
+
+// Validate espera opções já normalizadas.
+func (o ListOptions) Validate() error {
+	switch o.SortBy {
+	case SortByID, SortByTitle, SortByYear:
+	default:
+		return ErrInvalidSortField
+	}
+	switch o.SortOrder {
+	case SortAsc, SortDesc:
+	default:
+		return ErrInvalidSortOrder
+	}
+	if o.MinYear < 0 || o.MaxYear < 0 || (o.MaxYear > 0 && o.MinYear > o.MaxYear) {
+		return ErrInvalidYearRange
+	}
+	if o.PageToken != "" && o.AfterID != "" {
+		return ErrInvalidPageToken
+	}
+	return nil
+}
+
+// MoviePage é uma página de resultados; NextPageToken vazio indica o fim.
+type MoviePage struct {
+	Movies        []Movie
+	NextPageToken string
+}
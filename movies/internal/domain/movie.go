@@ -2,34 +2,200 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+// Movie filme do catálogo. Só Title e Year são obrigatórios; os demais
+// campos são opcionais (zero = não informado) e ausentes em filmes antigos.
 type Movie struct {
 	ID       string `json:"id"`
 	Title    string `json:"title"`
 	Year     int    `json:"year"`
 	LegacyID string `bson:"legacy_id,omitempty" json:"-"`
+
+	Genres           []string `bson:"genres,omitempty" json:"genres,omitempty"`
+	RuntimeMinutes   int      `bson:"runtime_minutes,omitempty" json:"runtime_minutes,omitempty"`
+	Directors        []string `bson:"directors,omitempty" json:"directors,omitempty"`
+	Cast             []string `bson:"cast,omitempty" json:"cast,omitempty"` // elenco principal, em ordem de billing
+	Synopsis         string   `bson:"synopsis,omitempty" json:"synopsis,omitempty"`
+	OriginalLanguage string   `bson:"original_language,omitempty" json:"original_language,omitempty"` // ISO 639-1
+	PosterURL        string   `bson:"poster_url,omitempty" json:"poster_url,omitempty"`
+
+	// OriginalTitle título como veio na importação, quando foi normalizado
+	// (ver SplitTitleYear). Não é editável via Update.
+	OriginalTitle string `bson:"original_title,omitempty" json:"original_title,omitempty"`
+
+	// Version começa em 1 e aumenta a cada Update; é a versão esperada
+	// (expectedVersion) de Update e Delete. Definida pelo repositório.
+	Version int64 `bson:"version,omitempty" json:"version,omitempty"`
+
+	// DeletedAt quando o filme foi para a lixeira (nil = no catálogo).
+	// Definido pelo repositório em Delete; Restore limpa.
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
 }
 
 var (
 	ErrInvalidID  = errors.New("invalid id")
 	ErrNotFound   = errors.New("movie not found")
 	ErrValidation = errors.New("validation error")
+
+	ErrAlreadyExists     = errors.New("movie already exists")
+	ErrInvalidUpdateMask = errors.New("invalid update mask")
+
+	// ErrVersionMismatch o filme mudou desde a versão esperada: nada foi
+	// gravado; é preciso reler o filme e refazer a alteração.
+	ErrVersionMismatch = errors.New("movie version mismatch")
+
+	// ErrVersionRequired alteração pela API sem a versão lida pelo cliente.
+	ErrVersionRequired = errors.New("expected version required")
 )
 
+// AnyVersion como expectedVersion, grava sem conferir a versão atual. A API
+// não aceita (ErrVersionRequired); fica para usos internos do serviço.
+const AnyVersion int64 = 0
+
+// Campos editáveis via Update (mesmos nomes do proto/JSON).
+const (
+	FieldTitle            = "title"
+	FieldYear             = "year"
+	FieldGenres           = "genres"
+	FieldRuntimeMinutes   = "runtime_minutes"
+	FieldDirectors        = "directors"
+	FieldCast             = "cast"
+	FieldSynopsis         = "synopsis"
+	FieldOriginalLanguage = "original_language"
+	FieldPosterURL        = "poster_url"
+)
+
+// UpdatableFields usados quando a máscara de update vem vazia (substituição completa).
+var UpdatableFields = []string{
+	FieldTitle, FieldYear, FieldGenres, FieldRuntimeMinutes, FieldDirectors,
+	FieldCast, FieldSynopsis, FieldOriginalLanguage, FieldPosterURL,
+}
+
+// Normalize apara espaços, põe o idioma em minúsculas e remove itens
+// vazios ou repetidos (sem diferenciar maiúsculas) das listas.
 func (m *Movie) Normalize() {
 	m.Title = strings.TrimSpace(m.Title)
+	m.Genres = normalizeList(m.Genres)
+	m.Directors = normalizeList(m.Directors)
+	m.Cast = normalizeList(m.Cast)
+	m.Synopsis = strings.TrimSpace(m.Synopsis)
+	m.OriginalLanguage = strings.ToLower(strings.TrimSpace(m.OriginalLanguage))
+	m.PosterURL = strings.TrimSpace(m.PosterURL)
+}
+
+func normalizeList(xs []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(xs))
+	for _, x := range xs {
+		x = strings.TrimSpace(x)
+		k := strings.ToLower(x)
+		if x == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, x)
+	}
+	return out
 }
 
-// Validação mínima de domínio usada no usecase.
+// ApplyUpdate copia de src apenas os campos listados; lista vazia copia
+// todos os UpdatableFields. Campo desconhecido retorna ErrInvalidUpdateMask.
+func (m *Movie) ApplyUpdate(src Movie, fields []string) error {
+	if len(fields) == 0 {
+		fields = UpdatableFields
+	}
+	for _, f := range fields {
+		switch f {
+		case FieldTitle:
+			m.Title = src.Title
+		case FieldYear:
+			m.Year = src.Year
+		case FieldGenres:
+			m.Genres = src.Genres
+		case FieldRuntimeMinutes:
+			m.RuntimeMinutes = src.RuntimeMinutes
+		case FieldDirectors:
+			m.Directors = src.Directors
+		case FieldCast:
+			m.Cast = src.Cast
+		case FieldSynopsis:
+			m.Synopsis = src.Synopsis
+		case FieldOriginalLanguage:
+			m.OriginalLanguage = src.OriginalLanguage
+		case FieldPosterURL:
+			m.PosterURL = src.PosterURL
+		default:
+			return ErrInvalidUpdateMask
+		}
+	}
+	return nil
+}
+
+// Limites dos campos de Movie.
+const (
+	MinYear = 1800
+	MaxYear = 3000
+
+	MaxRuntimeMinutes = 1440
+	MaxGenres         = 10
+	MaxDirectors      = 10
+	MaxCast           = 20
+	MaxSynopsisLen    = 4000 // em caracteres
+	MaxPosterURLLen   = 2048
+)
+
+// Validate valida os campos do filme; falhas vêm como *ValidationError com
+// uma violação por campo. Campos opcionais vazios são sempre válidos.
 func (m *Movie) Validate() error {
+	ve := &ValidationError{}
 	if strings.TrimSpace(m.Title) == "" {
-		return errors.New("title required")
+		ve.add(FieldTitle, ConstraintRequired, "title is required")
+	}
+	if m.Year < MinYear || m.Year > MaxYear {
+		ve.add(FieldYear, ConstraintRange, fmt.Sprintf("year must be between %d and %d", MinYear, MaxYear))
+	}
+	if m.RuntimeMinutes < 0 || m.RuntimeMinutes > MaxRuntimeMinutes {
+		ve.add(FieldRuntimeMinutes, ConstraintRange, fmt.Sprintf("runtime_minutes must be between 0 and %d", MaxRuntimeMinutes))
 	}
+	for _, l := range []struct {
+		field string
+		items []string
+		max   int
+	}{
+		{FieldGenres, m.Genres, MaxGenres},
+		{FieldDirectors, m.Directors, MaxDirectors},
+		{FieldCast, m.Cast, MaxCast},
+	} {
+		if len(l.items) > l.max {
+			ve.add(l.field, ConstraintRange, fmt.Sprintf("%s accepts at most %d items", l.field, l.max))
+		}
+	}
+	if utf8.RuneCountInString(m.Synopsis) > MaxSynopsisLen {
+		ve.add(FieldSynopsis, ConstraintRange, fmt.Sprintf("synopsis must have at most %d characters", MaxSynopsisLen))
+	}
+	if m.OriginalLanguage != "" && !isLanguageCode(m.OriginalLanguage) {
+		ve.add(FieldOriginalLanguage, ConstraintInvalid, "original_language must be a two-letter ISO 639-1 code")
+	}
+	if m.PosterURL != "" && !isPosterURL(m.PosterURL) {
+		ve.add(FieldPosterURL, ConstraintInvalid, "poster_url must be an absolute http(s) URL")
+	}
+	return ve.orNil()
+}
+
+func isLanguageCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'a' && s[0] <= 'z' && s[1] >= 'a' && s[1] <= 'z'
+}
 
-	if m.Year < 1800 || m.Year > 3000 {
-		return errors.New("year out of range")
+func isPosterURL(s string) bool {
+	if len(s) > MaxPosterURLLen {
+		return false
 	}
-	return nil
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
 }
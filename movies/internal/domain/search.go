@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidQuery query de busca vazia ou longa demais.
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// MaxQueryLen tamanho máximo (em bytes) da query de busca.
+const MaxQueryLen = 200
+
+// SearchOptions parâmetros da busca textual. Paginação por cursor como no
+// List, mas na ordem de relevância.
+type SearchOptions struct {
+	Query     string
+	PageSize  int
+	PageToken string // opaco; vale só para a mesma query e filtros
+
+	MinYear int // 0 = sem limite
+	MaxYear int // 0 = sem limite
+}
+
+// Normalize aplica defaults e teto ao tamanho da página e junta espaços da query.
+func (o *SearchOptions) Normalize() {
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultPageSize
+	}
+	if o.PageSize > MaxPageSize {
+		o.PageSize = MaxPageSize
+	}
+	o.Query = strings.Join(strings.Fields(o.Query), " ")
+}
+
+// Validate espera opções já normalizadas.
+func (o SearchOptions) Validate() error {
+	if o.Query == "" || len(o.Query) > MaxQueryLen {
+		return ErrInvalidQuery
+	}
+	if o.MinYear < 0 || o.MaxYear < 0 || (o.MaxYear > 0 && o.MinYear > o.MaxYear) {
+		return ErrInvalidYearRange
+	}
+	return nil
+}
+
+// SearchHit filme encontrado e sua relevância para a query (maior = melhor).
+type SearchHit struct {
+	Movie Movie
+	Score float64
+}
+
+// SearchPage página de resultados por relevância; NextPageToken vazio indica o fim.
+type SearchPage struct {
+	Hits          []SearchHit
+	NextPageToken string
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrInvalidPrefix      = errors.New("invalid suggest prefix")
+	ErrSuggestUnavailable = errors.New("title suggestions unavailable")
+)
+
+const (
+	DefaultSuggestLimit = 10
+	MaxSuggestLimit     = 50
+	MaxPrefixLen        = 100
+)
+
+// SuggestOptions parâmetros do autocomplete de títulos.
+type SuggestOptions struct {
+	Prefix string
+	Limit  int
+}
+
+// Normalize aplica default e teto ao limite e junta espaços do prefixo.
+func (o *SuggestOptions) Normalize() {
+	if o.Limit <= 0 {
+		o.Limit = DefaultSuggestLimit
+	}
+	if o.Limit > MaxSuggestLimit {
+		o.Limit = MaxSuggestLimit
+	}
+	o.Prefix = strings.Join(strings.Fields(o.Prefix), " ")
+}
+
+// Validate espera opções já normalizadas.
+func (o SuggestOptions) Validate() error {
+	if o.Prefix == "" || len(o.Prefix) > MaxPrefixLen {
+		return ErrInvalidPrefix
+	}
+	return nil
+}
+
+// TitleSuggestion item do autocomplete: só o necessário para exibir e
+// navegar até o filme.
+type TitleSuggestion struct {
+	ID    string
+	Title string
+	Year  int
+}
+
+// FoldText forma de comparação de textos: minúsculas, sem acentos e com
+// espaços normalizados ("  Lumière " → "lumiere").
+func FoldText(s string) string {
+	// NFD separa as marcas combinantes ("è" → "e" + "̀"), que são descartadas.
+	// O Transformer guarda estado: um por chamada.
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.ToLower(strings.Join(strings.Fields(folded), " "))
+}
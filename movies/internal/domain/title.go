@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// TitleFix resultado de Movie.SplitTitleYear.
+type TitleFix int
+
+const (
+	TitleUnchanged    TitleFix = iota // título sem "(YYYY)" no fim
+	TitleSplit                        // "(YYYY)" igual a Year removido do título
+	TitleYearMismatch                 // "(YYYY)" diferente de Year: título mantido
+)
+
+// trailingYear casa "Título (1894)"; o grupo 1 é o título sem o ano.
+var trailingYear = regexp.MustCompile(`^(.*\S)\s*\((\d{4})\)$`)
+
+// SplitTitleYear remove do título o "(YYYY)" final quando ele repete Year
+// (formato do seed), guardando o título como veio em OriginalTitle (se ainda
+// vazio). Quando o ano entre parênteses difere de Year nada é alterado: o
+// resultado TitleYearMismatch serve para sinalizar o dado para revisão.
+func (m *Movie) SplitTitleYear() TitleFix {
+	match := trailingYear.FindStringSubmatch(m.Title)
+	if match == nil {
+		return TitleUnchanged
+	}
+	if y, _ := strconv.Atoi(match[2]); y != m.Year {
+		return TitleYearMismatch
+	}
+	if m.OriginalTitle == "" {
+		m.OriginalTitle = m.Title
+	}
+	m.Title = match[1]
+	return TitleSplit
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidResumeToken = errors.New("invalid or expired resume token")
+	ErrWatchUnavailable   = errors.New("watch unavailable")
+)
+
+// MovieChange é uma mudança no catálogo entregue por Watch. Type usa as
+// mesmas constantes dos eventos (EventMovieCreated/Updated/Deleted).
+type MovieChange struct {
+	Type  string
+	Movie Movie
+	// ResumeToken retoma o Watch logo depois desta mudança.
+	ResumeToken string
+}
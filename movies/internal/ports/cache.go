@@ -0,0 +1,9 @@
+package ports
+
+// CacheInvalidator descarta entradas de um cache de leitura. Com outbox, o
+// cache decorando o repositório invalida dentro da transação, e um Get
+// concorrente pode reler a versão antiga antes do commit e guardá-la de
+// novo; o serviço chama Invalidate outra vez depois do commit.
+type CacheInvalidator interface {
+	Invalidate(ids ...string)
+}
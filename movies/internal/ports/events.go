@@ -8,5 +8,20 @@ import (
 
 type EventPublisher interface {
 	MovieCreated(ctx context.Context, m domain.Movie) error
-	MovieDeleted(ctx context.Context, id string) error
+	// MovieUpdated carrega o estado antes e depois da alteração.
+	MovieUpdated(ctx context.Context, before, after domain.Movie) error
+	// MovieDeleted carrega o filme completo como estava ao ir para a
+	// lixeira (com DeletedAt) e, de novo, ao ser purgado. O restore sai
+	// como MovieCreated.
+	MovieDeleted(ctx context.Context, m domain.Movie) error
+	// MovieSnapshot republica o estado atual de um filme (replay/backfill).
+	MovieSnapshot(ctx context.Context, m domain.Movie) error
+}
+
+// EventHandler processa eventos consumidos do broker. A entrega é
+// at-least-once: o mesmo evento (mesmo ID) pode chegar mais de uma vez e
+// fora de ordem, então Handle deve ser idempotente. Erro = retentativa.
+type EventHandler interface {
+	Name() string
+	Handle(ctx context.Context, ev domain.MovieEvent) error
 }
@@ -2,15 +2,36 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
 )
 
+// MovieRepository guarda o catálogo. Filmes apagados ficam na lixeira
+// (DeletedAt) até o HardDelete: somem de List (exceto com
+// ListOptions.Deleted), Get, Search e Count, mas continuam ocupando
+// title+year e legacy_id.
 type MovieRepository interface {
-	List(ctx context.Context) ([]domain.Movie, error)
+	List(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error)
 	Get(ctx context.Context, id string) (*domain.Movie, error)
 	Create(ctx context.Context, m *domain.Movie) (*domain.Movie, error)
-	Delete(ctx context.Context, id string) error
+	// Update grava os campos editáveis de m no filme id (ObjectID ou legacy_id)
+	// e incrementa a versão. Se o filme não está em version, nada é gravado
+	// e o erro é domain.ErrVersionMismatch (domain.AnyVersion não confere).
+	Update(ctx context.Context, id string, m *domain.Movie, version int64) (*domain.Movie, error)
+	// Delete move o filme id para a lixeira (DeletedAt = agora), incrementa
+	// a versão e devolve o filme como ficou; version como no Update.
+	Delete(ctx context.Context, id string, version int64) (*domain.Movie, error)
+	// GetDeleted é o Get da lixeira.
+	GetDeleted(ctx context.Context, id string) (*domain.Movie, error)
+	// Restore tira o filme id da lixeira e incrementa a versão; version
+	// como no Update.
+	Restore(ctx context.Context, id string, version int64) (*domain.Movie, error)
+	// HardDelete remove de vez o filme id se ele está na lixeira desde antes
+	// de deletedBefore (senão domain.ErrNotFound) e devolve o filme removido.
+	HardDelete(ctx context.Context, id string, deletedBefore time.Time) (*domain.Movie, error)
+	// Search busca por relevância (ver domain.SearchOptions).
+	Search(ctx context.Context, opts domain.SearchOptions) (domain.SearchPage, error)
 
 	// Suporte a seed idempotente
 	Count(ctx context.Context) (int64, error)
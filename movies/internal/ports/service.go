@@ -2,15 +2,39 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
 )
 
 type MovieService interface {
-	List(ctx context.Context) ([]domain.Movie, error)
+	List(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error)
 	Get(ctx context.Context, id string) (*domain.Movie, error)
 	Create(ctx context.Context, m domain.Movie) (*domain.Movie, error)
-	Delete(ctx context.Context, id string) error
+	// Update aplica em id os campos de m listados em fields (vazio = todos).
+	// Com expectedVersion (≠ domain.AnyVersion), só grava se o filme ainda
+	// estiver nessa versão; senão, domain.ErrVersionMismatch.
+	Update(ctx context.Context, id string, m domain.Movie, fields []string, expectedVersion int64) (*domain.Movie, error)
+	// Delete move id para a lixeira (movies.deleted) e devolve o filme
+	// apagado, na nova versão; expectedVersion como no Update.
+	Delete(ctx context.Context, id string, expectedVersion int64) (*domain.Movie, error)
+	// ListDeleted lista a lixeira, com os mesmos filtros e paginação do List.
+	ListDeleted(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error)
+	// GetDeleted busca um filme na lixeira (ErrNotFound se está no catálogo).
+	GetDeleted(ctx context.Context, id string) (*domain.Movie, error)
+	// Restore devolve id da lixeira ao catálogo (movies.created);
+	// expectedVersion como no Update.
+	Restore(ctx context.Context, id string, expectedVersion int64) (*domain.Movie, error)
+	// PurgeDeleted remove de vez os filmes na lixeira desde antes de
+	// deletedBefore, publicando movies.deleted de cada um (o segundo, depois
+	// do Delete).
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (purged int, err error)
+	// Search busca textual por relevância, sem diferenciar maiúsculas e acentos.
+	Search(ctx context.Context, opts domain.SearchOptions) (domain.SearchPage, error)
+	// SuggestTitles autocomplete de títulos por prefixo (ver TitleIndex).
+	SuggestTitles(ctx context.Context, opts domain.SuggestOptions) ([]domain.TitleSuggestion, error)
+	// Watch acompanha mudanças do catálogo (ver MovieWatcher).
+	Watch(ctx context.Context, resumeToken string, fn func(domain.MovieChange) error) error
 
 	// Usado no bootstrap do servidor para popular base, se necessário
 	EnsureSeed(ctx context.Context, seed []domain.Movie) (inserted int, err error)
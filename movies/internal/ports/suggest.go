@@ -0,0 +1,13 @@
+package ports
+
+import "github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+
+// TitleIndex índice de títulos do autocomplete. O serviço o mantém em dia
+// depois de cada escrita confirmada (Put em create/update/restore, Remove
+// em delete). As chamadas podem chegar fora de ordem: a versão do filme
+// decide qual vale.
+type TitleIndex interface {
+	Suggest(opts domain.SuggestOptions) []domain.TitleSuggestion
+	Put(m domain.Movie)
+	Remove(id string, version int64)
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+// Transactor executa fn numa transação; repositório e publisher que usarem
+// o ctx recebido participam dela. Erro em fn desfaz todas as escritas.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+)
+
+// MovieWatcher entrega mudanças do catálogo a fn, em ordem, até ctx ser
+// cancelado ou fn falhar. resumeToken vazio = a partir de agora; senão,
+// logo depois da mudança que gerou o token (domain.ErrInvalidResumeToken
+// se ele não for mais utilizável).
+type MovieWatcher interface {
+	Watch(ctx context.Context, resumeToken string, fn func(domain.MovieChange) error) error
+}
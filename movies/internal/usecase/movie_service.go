@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
@@ -12,22 +14,106 @@ import (
 var _ ports.MovieService = (*movieService)(nil)
 
 type movieService struct {
-	repo ports.MovieRepository
-	pub  ports.EventPublisher // opcional: pode ser nil
+	repo    ports.MovieRepository
+	pub     ports.EventPublisher // opcional: pode ser nil
+	tx      ports.Transactor     // opcional: com tx, pub grava no outbox na mesma transação
+	watcher ports.MovieWatcher   // opcional: sem ele Watch devolve ErrWatchUnavailable
+	titles  ports.TitleIndex     // opcional: sem ele SuggestTitles devolve ErrSuggestUnavailable
+	cache   ports.CacheInvalidator
+}
+
+// Option configura dependências opcionais comuns a todos os construtores.
+type Option func(*movieService)
+
+// WithWatcher habilita Watch.
+func WithWatcher(w ports.MovieWatcher) Option {
+	return func(s *movieService) { s.watcher = w }
+}
+
+// WithTitleIndex habilita SuggestTitles; o índice é atualizado depois de
+// cada escrita confirmada (com outbox, só após o commit).
+func WithTitleIndex(x ports.TitleIndex) Option {
+	return func(s *movieService) { s.titles = x }
+}
+
+// WithCacheInvalidator invalida no cache, depois de cada escrita
+// confirmada, os ids que ela tocou; também descarta a entrada quando a
+// versão lida diverge da esperada pelo cliente (ver current).
+func WithCacheInvalidator(c ports.CacheInvalidator) Option {
+	return func(s *movieService) { s.cache = c }
+}
+
+func newMovieService(s *movieService, opts []Option) ports.MovieService {
+	for _, o := range opts {
+		o(s)
+	}
+	return s
 }
 
 // Construtor compatível (sem publisher)
-func NewMovieService(repo ports.MovieRepository) ports.MovieService {
-	return &movieService{repo: repo}
+func NewMovieService(repo ports.MovieRepository, opts ...Option) ports.MovieService {
+	return newMovieService(&movieService{repo: repo}, opts)
 }
 
 // Construtor com publisher (event-driven)
-func NewMovieServiceWithPublisher(repo ports.MovieRepository, pub ports.EventPublisher) ports.MovieService {
-	return &movieService{repo: repo, pub: pub}
+func NewMovieServiceWithPublisher(repo ports.MovieRepository, pub ports.EventPublisher, opts ...Option) ports.MovieService {
+	return newMovieService(&movieService{repo: repo, pub: pub}, opts)
+}
+
+// Construtor com outbox: a escrita do filme e a do evento (pub) acontecem na
+// mesma transação; falha ao gravar o evento desfaz a operação.
+func NewMovieServiceWithOutbox(repo ports.MovieRepository, pub ports.EventPublisher, tx ports.Transactor, opts ...Option) ports.MovieService {
+	return newMovieService(&movieService{repo: repo, pub: pub, tx: tx}, opts)
+}
+
+func (s *movieService) atomically(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.tx == nil {
+		return fn(ctx)
+	}
+	return s.tx.WithinTx(ctx, fn)
+}
+
+// committed roda depois de uma escrita confirmada: invalida os ids no
+// cache (se houver) de novo, agora que nenhuma leitura pode ver o estado
+// anterior.
+func (s *movieService) committed(ids ...string) {
+	if s.cache != nil {
+		s.cache.Invalidate(ids...)
+	}
+}
+
+// emit publica um evento. Com outbox (tx) o erro aborta a transação;
+// sem ele é best-effort: não falha a request se mensageria estiver fora.
+func (s *movieService) emit(publish func(pub ports.EventPublisher) error) error {
+	if s.pub == nil {
+		return nil
+	}
+	if err := publish(s.pub); err != nil && s.tx != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *movieService) List(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error) {
+	opts.Normalize()
+	if err := opts.Validate(); err != nil {
+		return domain.MoviePage{}, err
+	}
+	return s.repo.List(ctx, opts)
 }
 
-func (s *movieService) List(ctx context.Context) ([]domain.Movie, error) {
-	return s.repo.List(ctx)
+// ListDeleted é o List da lixeira.
+func (s *movieService) ListDeleted(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error) {
+	opts.Deleted = true
+	return s.List(ctx, opts)
+}
+
+func (s *movieService) Search(ctx context.Context, opts domain.SearchOptions) (domain.SearchPage, error) {
+	opts.Normalize()
+	if err := opts.Validate(); err != nil {
+		return domain.SearchPage{}, err
+	}
+	return s.repo.Search(ctx, opts)
 }
 
 func (s *movieService) Get(ctx context.Context, id string) (*domain.Movie, error) {
@@ -37,34 +123,243 @@ func (s *movieService) Get(ctx context.Context, id string) (*domain.Movie, error
 	return s.repo.Get(ctx, id)
 }
 
+func (s *movieService) GetDeleted(ctx context.Context, id string) (*domain.Movie, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidID
+	}
+	return s.repo.GetDeleted(ctx, id)
+}
+
 func (s *movieService) Create(ctx context.Context, m domain.Movie) (*domain.Movie, error) {
 	m.Normalize()
 	if err := m.Validate(); err != nil {
 		return nil, err
 	}
-	created, err := s.repo.Create(ctx, &m)
+	var created *domain.Movie
+	err := s.atomically(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, &m); err != nil {
+			return err
+		}
+		return s.emit(func(pub ports.EventPublisher) error {
+			return pub.MovieCreated(ctx, *created)
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
-	// best-effort: não falha a request se mensageria estiver fora
-	if s.pub != nil && created != nil {
-		_ = s.pub.MovieCreated(ctx, *created)
+	s.committed(created.ID)
+	if s.titles != nil {
+		s.titles.Put(*created)
 	}
 	return created, nil
 }
 
-func (s *movieService) Delete(ctx context.Context, id string) error {
+// updateAttempts limita as tentativas de Update, Delete e Restore sem
+// expectedVersion quando outra escrita muda o filme entre a leitura e a
+// gravação.
+const updateAttempts = 3
+
+// retrying repete fn enquanto ela falha com ErrVersionMismatch, só quando o
+// cliente não fixou expectedVersion.
+func retrying(expectedVersion int64, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if errors.Is(err, domain.ErrVersionMismatch) && expectedVersion == domain.AnyVersion && attempt < updateAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+// checkVersion confere a versão lida com a esperada pelo cliente.
+func checkVersion(cur *domain.Movie, expectedVersion int64) error {
+	if expectedVersion != domain.AnyVersion && cur.Version != expectedVersion {
+		return domain.ErrVersionMismatch
+	}
+	return nil
+}
+
+// current lê id com get e confere expectedVersion. get pode passar pelo
+// cache, cuja entrada pode estar velha (escrita de outra réplica ainda não
+// invalidada): na divergência o id sai do cache e o filme é relido uma vez,
+// senão o cliente com a versão certa levaria 412 até a entrada expirar.
+func (s *movieService) current(ctx context.Context, id string, expectedVersion int64,
+	get func(ctx context.Context, id string) (*domain.Movie, error),
+) (*domain.Movie, error) {
+	cur, err := get(ctx, id)
+	if err == nil && checkVersion(cur, expectedVersion) != nil && s.cache != nil {
+		s.cache.Invalidate(id)
+		cur, err = get(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(cur, expectedVersion); err != nil {
+		return nil, err
+	}
+	return cur, nil
+}
+
+func (s *movieService) Update(ctx context.Context, id string, m domain.Movie, fields []string, expectedVersion int64) (*domain.Movie, error) {
 	if id == "" {
-		return domain.ErrInvalidID
+		return nil, domain.ErrInvalidID
 	}
-	if err := s.repo.Delete(ctx, id); err != nil {
+	var updated *domain.Movie
+	err := retrying(expectedVersion, func() (err error) {
+		updated, err = s.update(ctx, id, m, fields, expectedVersion)
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	// best-effort
-	if s.pub != nil {
-		_ = s.pub.MovieDeleted(ctx, id)
+	s.committed(id, updated.ID)
+	if s.titles != nil {
+		s.titles.Put(*updated)
 	}
-	return nil
+	return updated, nil
+}
+
+// update lê o filme, aplica fields e grava condicionado à versão lida: uma
+// escrita concorrente nunca é sobrescrita com base em dado velho.
+func (s *movieService) update(ctx context.Context, id string, m domain.Movie, fields []string, expectedVersion int64) (*domain.Movie, error) {
+	var updated *domain.Movie
+	err := s.atomically(ctx, func(ctx context.Context) error {
+		cur, err := s.current(ctx, id, expectedVersion, s.repo.Get)
+		if err != nil {
+			return err
+		}
+		next := *cur
+		if err := next.ApplyUpdate(m, fields); err != nil {
+			return err
+		}
+		next.Normalize()
+		if err := next.Validate(); err != nil {
+			return err
+		}
+		if updated, err = s.repo.Update(ctx, id, &next, cur.Version); err != nil {
+			return err
+		}
+		return s.emit(func(pub ports.EventPublisher) error {
+			return pub.MovieUpdated(ctx, *cur, *updated)
+		})
+	})
+	return updated, err
+}
+
+// Delete move o filme para a lixeira. Para os consumidores o filme sai do
+// catálogo: movies.deleted, com deleted_at; o purge publica movies.deleted
+// de novo quando o filme deixa de existir.
+func (s *movieService) Delete(ctx context.Context, id string, expectedVersion int64) (*domain.Movie, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidID
+	}
+	deleted, err := s.setDeleted(ctx, id, expectedVersion, s.repo.Get, s.repo.Delete, ports.EventPublisher.MovieDeleted)
+	if err != nil {
+		return nil, err
+	}
+	s.committed(id, deleted.ID)
+	if s.titles != nil {
+		s.titles.Remove(deleted.ID, deleted.Version)
+	}
+	return deleted, nil
+}
+
+// Restore devolve o filme ao catálogo: movies.created, como se acabasse de
+// ser criado.
+func (s *movieService) Restore(ctx context.Context, id string, expectedVersion int64) (*domain.Movie, error) {
+	if id == "" {
+		return nil, domain.ErrInvalidID
+	}
+	restored, err := s.setDeleted(ctx, id, expectedVersion, s.repo.GetDeleted, s.repo.Restore, ports.EventPublisher.MovieCreated)
+	if err != nil {
+		return nil, err
+	}
+	s.committed(id, restored.ID)
+	if s.titles != nil {
+		s.titles.Put(*restored)
+	}
+	return restored, nil
+}
+
+// setDeleted lê o filme com get, grava com write (Delete ou Restore)
+// condicionado à versão lida, como o update, e publica o resultado com
+// publish.
+func (s *movieService) setDeleted(ctx context.Context, id string, expectedVersion int64,
+	get func(ctx context.Context, id string) (*domain.Movie, error),
+	write func(ctx context.Context, id string, version int64) (*domain.Movie, error),
+	publish func(pub ports.EventPublisher, ctx context.Context, m domain.Movie) error,
+) (*domain.Movie, error) {
+	var after *domain.Movie
+	err := retrying(expectedVersion, func() error {
+		return s.atomically(ctx, func(ctx context.Context) error {
+			cur, err := s.current(ctx, id, expectedVersion, get)
+			if err != nil {
+				return err
+			}
+			if after, err = write(ctx, id, cur.Version); err != nil {
+				return err
+			}
+			return s.emit(func(pub ports.EventPublisher) error {
+				return publish(pub, ctx, *after)
+			})
+		})
+	})
+	return after, err
+}
+
+// PurgeDeleted percorre a lixeira e remove cada filme apagado antes de
+// deletedBefore. Filme restaurado ou já removido (outra réplica) no meio
+// do caminho é ignorado.
+func (s *movieService) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	opts := domain.ListOptions{PageSize: domain.MaxPageSize, Deleted: true, DeletedBefore: deletedBefore}
+	purged := 0
+	for {
+		page, err := s.repo.List(ctx, opts)
+		if err != nil {
+			return purged, err
+		}
+		for _, m := range page.Movies {
+			err := s.atomically(ctx, func(ctx context.Context) error {
+				gone, err := s.repo.HardDelete(ctx, m.ID, deletedBefore)
+				if err != nil {
+					return err
+				}
+				return s.emit(func(pub ports.EventPublisher) error {
+					return pub.MovieDeleted(ctx, *gone)
+				})
+			})
+			switch {
+			case err == nil:
+				s.committed(m.ID)
+				purged++
+			case !errors.Is(err, domain.ErrNotFound):
+				return purged, fmt.Errorf("purge %s: %w", m.ID, err)
+			}
+		}
+		if page.NextPageToken == "" {
+			return purged, nil
+		}
+		opts.PageToken = page.NextPageToken
+	}
+}
+
+func (s *movieService) SuggestTitles(ctx context.Context, opts domain.SuggestOptions) ([]domain.TitleSuggestion, error) {
+	if s.titles == nil {
+		return nil, domain.ErrSuggestUnavailable
+	}
+	opts.Normalize()
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return s.titles.Suggest(opts), nil
+}
+
+func (s *movieService) Watch(ctx context.Context, resumeToken string, fn func(domain.MovieChange) error) error {
+	if s.watcher == nil {
+		return domain.ErrWatchUnavailable
+	}
+	return s.watcher.Watch(ctx, resumeToken, fn)
 }
 
 func (s *movieService) EnsureSeed(ctx context.Context, seed []domain.Movie) (int, error) {
@@ -75,6 +370,11 @@ func (s *movieService) EnsureSeed(ctx context.Context, seed []domain.Movie) (int
 	clean := make([]domain.Movie, 0, len(seed))
 	for i := range seed {
 		seed[i].Normalize()
+		// títulos do seed trazem o ano: "Título (1894)"
+		if seed[i].SplitTitleYear() == domain.TitleYearMismatch {
+			log.Printf("seed: title/year mismatch legacy_id=%s title=%q year=%d (title kept as is)",
+				seed[i].LegacyID, seed[i].Title, seed[i].Year)
+		}
 
 		if seed[i].Title == "" || seed[i].Year == 0 {
 			continue
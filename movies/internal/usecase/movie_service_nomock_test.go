@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
@@ -27,20 +28,33 @@ func newMemRepo() *memRepo {
 
 func keyOf(m domain.Movie) string { return m.Title + "|" + strconv.Itoa(m.Year) }
 
-func (r *memRepo) List(ctx context.Context) ([]domain.Movie, error) {
+func (r *memRepo) List(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error) {
 	out := make([]domain.Movie, 0, len(r.byID))
 	for _, m := range r.byID {
+		if (m.DeletedAt != nil) != opts.Deleted {
+			continue
+		}
+		if !opts.DeletedBefore.IsZero() && !m.DeletedAt.Before(opts.DeletedBefore) {
+			continue
+		}
 		out = append(out, m)
 	}
-	return out, nil
+	return domain.MoviePage{Movies: out}, nil
 }
 func (r *memRepo) Get(ctx context.Context, id string) (*domain.Movie, error) {
 	m, ok := r.byID[id]
-	if !ok {
+	if !ok || m.DeletedAt != nil {
 		return nil, errors.New("not found")
 	}
 	return &m, nil
 }
+func (r *memRepo) GetDeleted(ctx context.Context, id string) (*domain.Movie, error) {
+	m, ok := r.byID[id]
+	if !ok || m.DeletedAt == nil {
+		return nil, domain.ErrNotFound
+	}
+	return &m, nil
+}
 func (r *memRepo) Create(ctx context.Context, m *domain.Movie) (*domain.Movie, error) {
 	k := keyOf(*m)
 	if _, dup := r.byKey[k]; dup {
@@ -49,21 +63,71 @@ func (r *memRepo) Create(ctx context.Context, m *domain.Movie) (*domain.Movie, e
 	id := "gen-" + strconv.Itoa(r.next)
 	r.next++
 	cp := *m
-	cp.ID = id
+	cp.ID, cp.Version = id, 1
 	r.byID[id] = cp
 	r.byKey[k] = id
 	return &cp, nil
 }
-func (r *memRepo) Delete(ctx context.Context, id string) error {
+func (r *memRepo) Update(ctx context.Context, id string, m *domain.Movie, version int64) (*domain.Movie, error) {
+	old, ok := r.byID[id]
+	if !ok || old.DeletedAt != nil {
+		return nil, errors.New("not found")
+	}
+	if version != domain.AnyVersion && old.Version != version {
+		return nil, domain.ErrVersionMismatch
+	}
+	k := keyOf(*m)
+	if owner, dup := r.byKey[k]; dup && owner != id {
+		return nil, domain.ErrAlreadyExists
+	}
+	delete(r.byKey, keyOf(old))
+	cp := *m
+	cp.ID, cp.Version = id, old.Version+1
+	r.byID[id] = cp
+	r.byKey[k] = id
+	return &cp, nil
+}
+func (r *memRepo) Delete(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	now := time.Now().UTC()
+	return r.setDeleted(id, version, &now)
+}
+func (r *memRepo) Restore(ctx context.Context, id string, version int64) (*domain.Movie, error) {
+	return r.setDeleted(id, version, nil)
+}
+func (r *memRepo) setDeleted(id string, version int64, at *time.Time) (*domain.Movie, error) {
+	m, ok := r.byID[id]
+	if !ok || (m.DeletedAt == nil) == (at == nil) {
+		return nil, domain.ErrNotFound
+	}
+	if version != domain.AnyVersion && m.Version != version {
+		return nil, domain.ErrVersionMismatch
+	}
+	m.DeletedAt = at
+	m.Version++
+	r.byID[id] = m
+	return &m, nil
+}
+func (r *memRepo) HardDelete(ctx context.Context, id string, deletedBefore time.Time) (*domain.Movie, error) {
 	m, ok := r.byID[id]
-	if !ok {
-		return errors.New("not found")
+	if !ok || m.DeletedAt == nil || !m.DeletedAt.Before(deletedBefore) {
+		return nil, domain.ErrNotFound
 	}
 	delete(r.byID, id)
 	delete(r.byKey, keyOf(m))
-	return nil
+	return &m, nil
+}
+func (r *memRepo) Search(ctx context.Context, opts domain.SearchOptions) (domain.SearchPage, error) {
+	return domain.SearchPage{}, errors.New("not implemented")
+}
+func (r *memRepo) Count(ctx context.Context) (int64, error) {
+	n := 0
+	for _, m := range r.byID {
+		if m.DeletedAt == nil {
+			n++
+		}
+	}
+	return int64(n), nil
 }
-func (r *memRepo) Count(ctx context.Context) (int64, error) { return int64(len(r.byID)), nil }
 func (r *memRepo) BulkInsertIgnoreDuplicates(ctx context.Context, ms []domain.Movie) (int, error) {
 	ins := 0
 	for i := range ms {
@@ -74,7 +138,7 @@ func (r *memRepo) BulkInsertIgnoreDuplicates(ctx context.Context, ms []domain.Mo
 		id := "gen-" + strconv.Itoa(r.next)
 		r.next++
 		cp := ms[i]
-		cp.ID = id
+		cp.ID, cp.Version = id, 1
 		r.byID[id] = cp
 		r.byKey[k] = id
 		ins++
@@ -100,15 +164,170 @@ func TestUsecase_NoMock(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, m.ID)
 
-	all, err := svc.List(context.Background())
+	all, err := svc.List(context.Background(), domain.ListOptions{})
 	require.NoError(t, err)
-	require.Len(t, all, 2)
+	require.Len(t, all.Movies, 2)
 
 	got, err := svc.Get(context.Background(), m.ID)
 	require.NoError(t, err)
 	require.Equal(t, "B", got.Title)
 
-	require.NoError(t, svc.Delete(context.Background(), m.ID))
+	upd, err := svc.Update(context.Background(), m.ID, domain.Movie{Title: "  B2 "}, []string{domain.FieldTitle}, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, "B2", upd.Title)
+	require.Equal(t, 2001, upd.Year)
+
+	_, err = svc.Update(context.Background(), m.ID, domain.Movie{Title: "A", Year: 1999}, nil, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrAlreadyExists)
+
+	_, err = svc.Delete(context.Background(), m.ID, domain.AnyVersion)
+	require.NoError(t, err)
 	_, err = svc.Get(context.Background(), m.ID)
 	require.Error(t, err)
 }
+
+// racyRepo simula races escritas de outro cliente entre o Get e o Update
+// do serviço.
+type racyRepo struct {
+	*memRepo
+	races int
+}
+
+func (r *racyRepo) Update(ctx context.Context, id string, m *domain.Movie, version int64) (*domain.Movie, error) {
+	if r.races > 0 {
+		r.races--
+		other := r.byID[id]
+		other.Synopsis = "concurrent"
+		if _, err := r.memRepo.Update(ctx, id, &other, domain.AnyVersion); err != nil {
+			return nil, err
+		}
+	}
+	return r.memRepo.Update(ctx, id, m, version)
+}
+
+func TestUsecase_ExpectedVersion(t *testing.T) {
+	repo := &racyRepo{memRepo: newMemRepo()}
+	svc := NewMovieService(repo)
+	ctx := context.Background()
+	title := []string{domain.FieldTitle}
+
+	m, err := svc.Create(ctx, domain.Movie{Title: "A", Year: 2000})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, m.Version)
+
+	upd, err := svc.Update(ctx, m.ID, domain.Movie{Title: "B"}, title, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, upd.Version)
+
+	// versão velha: nada é gravado
+	_, err = svc.Update(ctx, m.ID, domain.Movie{Title: "C"}, title, 1)
+	require.ErrorIs(t, err, domain.ErrVersionMismatch)
+	_, err = svc.Delete(ctx, m.ID, 1)
+	require.ErrorIs(t, err, domain.ErrVersionMismatch)
+	require.Equal(t, "B", repo.byID[m.ID].Title)
+
+	// escrita concorrente depois da leitura: com versão esperada, falha
+	repo.races = 1
+	_, err = svc.Update(ctx, m.ID, domain.Movie{Title: "C"}, title, 2)
+	require.ErrorIs(t, err, domain.ErrVersionMismatch)
+	require.Equal(t, "B", repo.byID[m.ID].Title)
+
+	// sem versão esperada, relê e refaz sem perder a outra escrita
+	repo.byID[m.ID] = domain.Movie{ID: m.ID, Title: "B", Year: 2000, Version: 3}
+	repo.races = 1
+	upd, err = svc.Update(ctx, m.ID, domain.Movie{Title: "C"}, title, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, "C", upd.Title)
+	require.Equal(t, "concurrent", upd.Synopsis)
+	require.EqualValues(t, 5, upd.Version)
+
+	// ... até updateAttempts vezes
+	repo.races = updateAttempts
+	_, err = svc.Update(ctx, m.ID, domain.Movie{Title: "D"}, title, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrVersionMismatch)
+
+	_, err = svc.Delete(ctx, m.ID, repo.byID[m.ID].Version)
+	require.NoError(t, err)
+}
+
+func TestUsecase_Trash(t *testing.T) {
+	repo := newMemRepo()
+	pub := &recPublisher{}
+	svc := NewMovieServiceWithPublisher(repo, pub)
+	ctx := context.Background()
+
+	a, err := svc.Create(ctx, domain.Movie{Title: "A", Year: 2000})
+	require.NoError(t, err)
+	b, err := svc.Create(ctx, domain.Movie{Title: "B", Year: 2000})
+	require.NoError(t, err)
+	trashedA, err := svc.Delete(ctx, a.ID, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, trashedA.Version)
+	_, err = svc.Delete(ctx, b.ID, domain.AnyVersion)
+	require.NoError(t, err)
+	got, err := svc.GetDeleted(ctx, a.ID)
+	require.NoError(t, err)
+	require.Equal(t, *trashedA, *got)
+
+	// ir para a lixeira é um MovieDeleted com deleted_at
+	require.Empty(t, pub.updated)
+	require.Len(t, pub.deleted, 2)
+	require.NotNil(t, pub.deleted[0].DeletedAt)
+
+	trash, err := svc.ListDeleted(ctx, domain.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, trash.Movies, 2)
+	live, err := svc.List(ctx, domain.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, live.Movies)
+
+	_, err = svc.Restore(ctx, a.ID, 1)
+	require.ErrorIs(t, err, domain.ErrVersionMismatch)
+	restored, err := svc.Restore(ctx, a.ID, 2)
+	require.NoError(t, err)
+	require.Nil(t, restored.DeletedAt)
+	require.EqualValues(t, 3, restored.Version)
+	// restaurar é um MovieCreated: o filme volta ao catálogo
+	require.Equal(t, []domain.Movie{*a, *b, *restored}, pub.created)
+	_, err = svc.Restore(ctx, a.ID, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	// purge: só o que está na lixeira desde antes do corte, um movies.deleted cada
+	n, err := svc.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, n)
+	n, err = svc.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, pub.deleted, 3)
+	require.Equal(t, b.ID, pub.deleted[2].ID)
+	_, err = svc.Get(ctx, a.ID)
+	require.NoError(t, err)
+}
+
+// vanishingRepo simula outra réplica purgando (ou restaurando) entre o List
+// e o HardDelete.
+type vanishingRepo struct{ *memRepo }
+
+func (r vanishingRepo) HardDelete(ctx context.Context, id string, deletedBefore time.Time) (*domain.Movie, error) {
+	if id == "gen-1" {
+		return nil, domain.ErrNotFound
+	}
+	return r.memRepo.HardDelete(ctx, id, deletedBefore)
+}
+
+func TestUsecase_PurgeSkipsVanished(t *testing.T) {
+	repo := vanishingRepo{newMemRepo()}
+	svc := NewMovieService(repo)
+	ctx := context.Background()
+	for _, title := range []string{"A", "B"} {
+		m, err := svc.Create(ctx, domain.Movie{Title: title, Year: 2000})
+		require.NoError(t, err)
+		_, err = svc.Delete(ctx, m.ID, domain.AnyVersion)
+		require.NoError(t, err)
+	}
+
+	n, err := svc.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
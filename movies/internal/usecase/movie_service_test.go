@@ -3,7 +3,9 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
 	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports/mocks"
@@ -18,14 +20,89 @@ func TestList_OK(t *testing.T) {
 	mockRepo := mocks.NewMockMovieRepository(ctrl)
 	svc := NewMovieService(mockRepo)
 
-	want := []domain.Movie{{ID: "8", Title: "Movie 8", Year: 1999}}
-	mockRepo.EXPECT().List(gomock.Any()).Return(want, nil)
+	want := domain.MoviePage{
+		Movies:        []domain.Movie{{ID: "8", Title: "Movie 8", Year: 1999}},
+		NextPageToken: "tok",
+	}
+	mockRepo.EXPECT().
+		List(gomock.Any(), domain.ListOptions{
+			PageSize:  domain.DefaultPageSize,
+			SortBy:    domain.SortByID,
+			SortOrder: domain.SortAsc,
+		}).
+		Return(want, nil)
 
-	got, err := svc.List(context.Background())
+	got, err := svc.List(context.Background(), domain.ListOptions{})
 	require.NoError(t, err)
 	require.Equal(t, want, got)
 }
 
+func TestList_ClampsPageSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	svc := NewMovieService(mockRepo)
+
+	mockRepo.EXPECT().
+		List(gomock.Any(), domain.ListOptions{
+			PageSize:  domain.MaxPageSize,
+			PageToken: "abc",
+			SortBy:    domain.SortByYear,
+			SortOrder: domain.SortDesc,
+		}).
+		Return(domain.MoviePage{}, nil)
+
+	_, err := svc.List(context.Background(), domain.ListOptions{
+		PageSize:  10_000,
+		PageToken: "abc",
+		SortBy:    " Year ",
+		SortOrder: "DESC",
+	})
+	require.NoError(t, err)
+}
+
+func TestList_InvalidOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	svc := NewMovieService(mockRepo)
+
+	_, err := svc.List(context.Background(), domain.ListOptions{SortBy: "rating"})
+	require.ErrorIs(t, err, domain.ErrInvalidSortField)
+
+	_, err = svc.List(context.Background(), domain.ListOptions{SortOrder: "up"})
+	require.ErrorIs(t, err, domain.ErrInvalidSortOrder)
+
+	_, err = svc.List(context.Background(), domain.ListOptions{MinYear: 1930, MaxYear: 1920})
+	require.ErrorIs(t, err, domain.ErrInvalidYearRange)
+}
+
+func TestSearch_NormalizesAndValidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	svc := NewMovieService(mockRepo)
+
+	mockRepo.EXPECT().
+		Search(gomock.Any(), domain.SearchOptions{
+			Query:    "train robbery",
+			PageSize: domain.DefaultPageSize,
+			MinYear:  1900,
+		}).
+		Return(domain.SearchPage{Hits: []domain.SearchHit{{Movie: domain.Movie{ID: "7"}, Score: 2}}}, nil)
+
+	page, err := svc.Search(context.Background(), domain.SearchOptions{Query: " train\trobbery ", MinYear: 1900})
+	require.NoError(t, err)
+	require.Len(t, page.Hits, 1)
+
+	for _, q := range []string{"", "   ", strings.Repeat("a", domain.MaxQueryLen+1)} {
+		_, err = svc.Search(context.Background(), domain.SearchOptions{Query: q})
+		require.ErrorIs(t, err, domain.ErrInvalidQuery)
+	}
+	_, err = svc.Search(context.Background(), domain.SearchOptions{Query: "x", MinYear: 1930, MaxYear: 1920})
+	require.ErrorIs(t, err, domain.ErrInvalidYearRange)
+}
+
 func TestGet_InvalidID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -43,7 +120,10 @@ func TestCreate_Validation(t *testing.T) {
 	svc := NewMovieService(mockRepo)
 
 	_, err := svc.Create(context.Background(), domain.Movie{Title: "", Year: 1999})
-	require.Error(t, err)
+	require.ErrorIs(t, err, domain.ErrValidation)
+	require.Equal(t, []domain.FieldViolation{
+		{Field: domain.FieldTitle, Constraint: domain.ConstraintRequired, Description: "title is required"},
+	}, domain.Violations(err))
 
 	in := domain.Movie{Title: "Ok", Year: 2000}
 	out := in
@@ -58,14 +138,133 @@ func TestCreate_Validation(t *testing.T) {
 	require.Equal(t, &out, got)
 }
 
+func TestCreate_OptionalFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	svc := NewMovieService(mockRepo)
+
+	// inválidos: cada campo opcional gera sua violação
+	_, err := svc.Create(context.Background(), domain.Movie{
+		Title: "Ok", Year: 2000,
+		RuntimeMinutes:   -5,
+		OriginalLanguage: "english",
+		PosterURL:        "ftp://posters/x.jpg",
+	})
+	fields := map[string]string{}
+	for _, v := range domain.Violations(err) {
+		fields[v.Field] = v.Constraint
+	}
+	require.Equal(t, map[string]string{
+		domain.FieldRuntimeMinutes:   domain.ConstraintRange,
+		domain.FieldOriginalLanguage: domain.ConstraintInvalid,
+		domain.FieldPosterURL:        domain.ConstraintInvalid,
+	}, fields)
+
+	// válidos chegam ao repositório normalizados
+	want := domain.Movie{
+		Title: "Metropolis", Year: 1927,
+		Genres:           []string{"Drama", "Sci-Fi"},
+		RuntimeMinutes:   153,
+		Directors:        []string{"Fritz Lang"},
+		Cast:             []string{"Brigitte Helm", "Alfred Abel"},
+		Synopsis:         "A city of the future.",
+		OriginalLanguage: "de",
+		PosterURL:        "https://img.example/metropolis.jpg",
+	}
+	mockRepo.EXPECT().Create(gomock.Any(), &want).Return(&want, nil)
+	_, err = svc.Create(context.Background(), domain.Movie{
+		Title: "Metropolis", Year: 1927,
+		Genres:           []string{" Drama", "Sci-Fi ", "", "sci-fi"},
+		RuntimeMinutes:   153,
+		Directors:        []string{"Fritz Lang", "fritz lang"},
+		Cast:             []string{"Brigitte Helm", " ", "Alfred Abel"},
+		Synopsis:         " A city of the future. ",
+		OriginalLanguage: "DE",
+		PosterURL:        " https://img.example/metropolis.jpg",
+	})
+	require.NoError(t, err)
+}
+
 func TestDelete_OK(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockRepo := mocks.NewMockMovieRepository(ctrl)
 	svc := NewMovieService(mockRepo)
 
-	mockRepo.EXPECT().Delete(gomock.Any(), "8").Return(nil)
-	require.NoError(t, svc.Delete(context.Background(), "8"))
+	// grava condicionado à versão lida
+	mockRepo.EXPECT().Get(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Title: "X", Year: 2000, Version: 3}, nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), "8", int64(3)).Return(&domain.Movie{ID: "8", Title: "X", Year: 2000, Version: 4}, nil)
+	deleted, err := svc.Delete(context.Background(), "8", domain.AnyVersion)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, deleted.Version) // versão na lixeira, para o restore
+}
+
+type recPublisher struct {
+	created  []domain.Movie
+	updated  [][2]domain.Movie
+	deleted  []domain.Movie
+	snapshot []domain.Movie
+
+	createdErr error // devolvido por MovieCreated
+	updatedErr error // devolvido por MovieUpdated
+	deletedErr error // devolvido por MovieDeleted
+}
+
+func (p *recPublisher) MovieCreated(ctx context.Context, m domain.Movie) error {
+	p.created = append(p.created, m)
+	return p.createdErr
+}
+func (p *recPublisher) MovieUpdated(ctx context.Context, before, after domain.Movie) error {
+	p.updated = append(p.updated, [2]domain.Movie{before, after})
+	return p.updatedErr
+}
+func (p *recPublisher) MovieDeleted(ctx context.Context, m domain.Movie) error {
+	p.deleted = append(p.deleted, m)
+	return p.deletedErr
+}
+func (p *recPublisher) MovieSnapshot(ctx context.Context, m domain.Movie) error {
+	p.snapshot = append(p.snapshot, m)
+	return nil
+}
+
+func TestEvents_UpdateAndDeleteCarrySnapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	pub := &recPublisher{}
+	svc := NewMovieServiceWithPublisher(mockRepo, pub)
+
+	deletedAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	before := domain.Movie{ID: "8", Title: "Old", Year: 1894, Version: 1}
+	after := domain.Movie{ID: "8", Title: "New", Year: 1894, Version: 2}
+	trashed := domain.Movie{ID: "8", Title: "New", Year: 1894, Version: 3, DeletedAt: &deletedAt}
+	gomock.InOrder(
+		mockRepo.EXPECT().Get(gomock.Any(), "8").Return(&before, nil),
+		mockRepo.EXPECT().Update(gomock.Any(), "8", gomock.Any(), int64(1)).Return(&after, nil),
+		mockRepo.EXPECT().Get(gomock.Any(), "8").Return(&after, nil),
+		mockRepo.EXPECT().Delete(gomock.Any(), "8", int64(2)).Return(&trashed, nil),
+	)
+
+	_, err := svc.Update(context.Background(), "8", domain.Movie{Title: "New"}, []string{domain.FieldTitle}, domain.AnyVersion)
+	require.NoError(t, err)
+	_, err = svc.Delete(context.Background(), "8", domain.AnyVersion)
+	require.NoError(t, err)
+
+	// ir para a lixeira já é movies.deleted, com deleted_at
+	require.Equal(t, [][2]domain.Movie{{before, after}}, pub.updated)
+	require.Equal(t, []domain.Movie{trashed}, pub.deleted)
+
+	// o purge publica movies.deleted de novo
+	pub.deletedErr = errors.New("nats down") // best-effort: não deve falhar a operação
+	cutoff := deletedAt.Add(time.Hour)
+	mockRepo.EXPECT().List(gomock.Any(), domain.ListOptions{PageSize: domain.MaxPageSize, Deleted: true, DeletedBefore: cutoff}).
+		Return(domain.MoviePage{Movies: []domain.Movie{trashed}}, nil)
+	mockRepo.EXPECT().HardDelete(gomock.Any(), "8", cutoff).Return(&trashed, nil)
+	n, err := svc.PurgeDeleted(context.Background(), cutoff)
+	require.NoError(t, err) // publicação best-effort falhou, mas o purge vale
+	require.Equal(t, 1, n)
+	require.Equal(t, []domain.Movie{trashed, trashed}, pub.deleted)
 }
 
 func TestDelete_InvalidID(t *testing.T) {
@@ -74,7 +273,8 @@ func TestDelete_InvalidID(t *testing.T) {
 	mockRepo := mocks.NewMockMovieRepository(ctrl)
 	svc := NewMovieService(mockRepo)
 
-	require.ErrorIs(t, svc.Delete(context.Background(), ""), domain.ErrInvalidID)
+	_, err := svc.Delete(context.Background(), "", domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrInvalidID)
 }
 
 func TestEnsureSeed_FiltersAndInserts(t *testing.T) {
@@ -103,6 +303,37 @@ func TestEnsureSeed_FiltersAndInserts(t *testing.T) {
 	require.Equal(t, 1, ins)
 }
 
+func TestEnsureSeed_SplitsYearFromTitle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	svc := NewMovieService(mockRepo)
+
+	seed := []domain.Movie{
+		{Title: "Blacksmith Scene (1893)", Year: 1893},
+		{Title: "Miss Jerry (1894)", Year: 1895}, // ano divergente: mantém
+		{Title: "Clean", Year: 1900},
+	}
+
+	mockRepo.
+		EXPECT().
+		BulkInsertIgnoreDuplicates(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, ms []domain.Movie) (int, error) {
+			require.Len(t, ms, 3)
+			require.Equal(t, "Blacksmith Scene", ms[0].Title)
+			require.Equal(t, "Blacksmith Scene (1893)", ms[0].OriginalTitle)
+			require.Equal(t, "Miss Jerry (1894)", ms[1].Title)
+			require.Empty(t, ms[1].OriginalTitle)
+			require.Equal(t, "Clean", ms[2].Title)
+			require.Empty(t, ms[2].OriginalTitle)
+			return 3, nil
+		})
+
+	ins, err := svc.EnsureSeed(context.Background(), seed)
+	require.NoError(t, err)
+	require.Equal(t, 3, ins)
+}
+
 func TestEnsureSeed_NoValidItems(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -119,3 +350,231 @@ func TestEnsureSeed_NoValidItems(t *testing.T) {
 	require.True(t, errors.Is(err, errors.New("no valid items to seed")) || err != nil)
 	require.Equal(t, 0, ins)
 }
+
+func TestUpdate_AppliesMaskAndValidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	svc := NewMovieService(mockRepo)
+
+	cur := &domain.Movie{ID: "8", Title: "Sneeze (1894)", Year: 1894, LegacyID: "8"}
+	mockRepo.EXPECT().Get(gomock.Any(), "8").Return(cur, nil).Times(2)
+
+	want := domain.Movie{ID: "8", Title: "Sneeze", Year: 1894, LegacyID: "8"}
+	mockRepo.EXPECT().Update(gomock.Any(), "8", &want, gomock.Any()).Return(&want, nil)
+
+	// só title entra na máscara: year do input (0) é ignorado
+	got, err := svc.Update(context.Background(), "8", domain.Movie{Title: " Sneeze "}, []string{domain.FieldTitle}, domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, &want, got)
+
+	// máscara vazia = substituição completa -> year 0 falha na validação
+	_, err = svc.Update(context.Background(), "8", domain.Movie{Title: "Sneeze"}, nil, domain.AnyVersion)
+	var ve *domain.ValidationError
+	require.ErrorAs(t, err, &ve)
+	require.Equal(t, domain.FieldYear, ve.Violations[0].Field)
+	require.Equal(t, domain.ConstraintRange, ve.Violations[0].Constraint)
+}
+
+func TestUpdate_InvalidMaskAndNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	svc := NewMovieService(mockRepo)
+
+	_, err := svc.Update(context.Background(), "", domain.Movie{}, nil, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrInvalidID)
+
+	mockRepo.EXPECT().Get(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Title: "X", Year: 2000}, nil)
+	_, err = svc.Update(context.Background(), "8", domain.Movie{}, []string{"rating"}, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrInvalidUpdateMask)
+
+	mockRepo.EXPECT().Get(gomock.Any(), "404").Return(nil, domain.ErrNotFound)
+	_, err = svc.Update(context.Background(), "404", domain.Movie{Title: "X", Year: 2000}, nil, domain.AnyVersion)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+type fakeTx struct{ calls int }
+
+func (f *fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.calls++
+	return fn(ctx)
+}
+
+func TestOutbox_PublishFailureAbortsWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	pub := &recPublisher{}
+	tx := &fakeTx{}
+	svc := NewMovieServiceWithOutbox(mockRepo, pub, tx)
+
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&domain.Movie{ID: "new", Title: "Ok", Year: 2000}, nil)
+	_, err := svc.Create(context.Background(), domain.Movie{Title: "Ok", Year: 2000})
+	require.NoError(t, err)
+	require.Len(t, pub.created, 1)
+
+	// publicação falha no purge: com outbox o erro volta (transação desfeita)
+	pub.deletedErr = errors.New("outbox down")
+	cutoff := time.Now()
+	mockRepo.EXPECT().List(gomock.Any(), gomock.Any()).Return(domain.MoviePage{Movies: []domain.Movie{{ID: "new"}}}, nil)
+	mockRepo.EXPECT().HardDelete(gomock.Any(), "new", cutoff).Return(&domain.Movie{ID: "new"}, nil)
+	n, err := svc.PurgeDeleted(context.Background(), cutoff)
+	require.Error(t, err)
+	require.Zero(t, n)
+	require.Equal(t, 2, tx.calls)
+}
+
+// recIndex registra as atualizações do TitleIndex.
+type recIndex struct {
+	put     []string
+	removed []string
+	got     domain.SuggestOptions
+}
+
+func (x *recIndex) Suggest(opts domain.SuggestOptions) []domain.TitleSuggestion {
+	x.got = opts
+	return []domain.TitleSuggestion{{ID: "1", Title: "Lumière", Year: 1895}}
+}
+func (x *recIndex) Put(m domain.Movie)              { x.put = append(x.put, m.ID+":"+m.Title) }
+func (x *recIndex) Remove(id string, version int64) { x.removed = append(x.removed, id) }
+
+func TestTitleIndex_SyncedAfterCommittedWrites(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	idx := &recIndex{}
+	pub := &recPublisher{}
+	svc := NewMovieServiceWithOutbox(mockRepo, pub, &fakeTx{}, WithTitleIndex(idx))
+
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&domain.Movie{ID: "new", Title: "Ok", Year: 2000}, nil)
+	_, err := svc.Create(context.Background(), domain.Movie{Title: "Ok", Year: 2000})
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().Get(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Title: "Old", Year: 2000}, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), "8", gomock.Any(), gomock.Any()).Return(&domain.Movie{ID: "8", Title: "New", Year: 2000}, nil)
+	_, err = svc.Update(context.Background(), "8", domain.Movie{Title: "New"}, []string{domain.FieldTitle}, domain.AnyVersion)
+	require.NoError(t, err)
+
+	// delete por legacy_id remove pelo id devolvido; falha no outbox não mexe no índice
+	pub.deletedErr = errors.New("outbox down")
+	mockRepo.EXPECT().Get(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Version: 2}, nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), "8", int64(2)).Return(&domain.Movie{ID: "8"}, nil)
+	_, err = svc.Delete(context.Background(), "8", 2)
+	require.Error(t, err)
+
+	require.Equal(t, []string{"new:Ok", "8:New"}, idx.put)
+	require.Empty(t, idx.removed)
+
+	ok := NewMovieService(mockRepo, WithTitleIndex(idx))
+	mockRepo.EXPECT().Get(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Version: 2}, nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), "8", int64(2)).Return(&domain.Movie{ID: "8"}, nil)
+	_, err = ok.Delete(context.Background(), "8", domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, []string{"8"}, idx.removed)
+
+	// restaurar devolve o título ao índice
+	mockRepo.EXPECT().GetDeleted(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Title: "New", Version: 3}, nil)
+	mockRepo.EXPECT().Restore(gomock.Any(), "8", int64(3)).Return(&domain.Movie{ID: "8", Title: "New", Version: 4}, nil)
+	_, err = ok.Restore(context.Background(), "8", domain.AnyVersion)
+	require.NoError(t, err)
+	require.Equal(t, []string{"new:Ok", "8:New", "8:New"}, idx.put)
+}
+
+// recInvalidator registra as invalidações e se houve alguma com a
+// transação ainda aberta.
+type recInvalidator struct {
+	tx       *inTx
+	ids      []string
+	duringTx bool
+}
+
+func (c *recInvalidator) Invalidate(ids ...string) {
+	c.ids = append(c.ids, ids...)
+	c.duringTx = c.duringTx || (c.tx != nil && c.tx.open)
+}
+
+// inTx é um Transactor que sabe se fn ainda está rodando.
+type inTx struct{ open bool }
+
+func (t *inTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.open = true
+	defer func() { t.open = false }()
+	return fn(ctx)
+}
+
+func TestCacheInvalidator_CalledAfterCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	tx := &inTx{}
+	c := &recInvalidator{tx: tx}
+	pub := &recPublisher{}
+	svc := NewMovieServiceWithOutbox(mockRepo, pub, tx, WithCacheInvalidator(c))
+
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&domain.Movie{ID: "new", Title: "Ok", Year: 2000}, nil)
+	_, err := svc.Create(context.Background(), domain.Movie{Title: "Ok", Year: 2000})
+	require.NoError(t, err)
+
+	// update por legacy_id invalida também o id devolvido
+	mockRepo.EXPECT().Get(gomock.Any(), "tt8").Return(&domain.Movie{ID: "8", Title: "Old", Year: 2000, Version: 1}, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), "tt8", gomock.Any(), int64(1)).Return(&domain.Movie{ID: "8", Title: "New", Year: 2000, Version: 2}, nil)
+	_, err = svc.Update(context.Background(), "tt8", domain.Movie{Title: "New"}, []string{domain.FieldTitle}, domain.AnyVersion)
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().Get(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Version: 2}, nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), "8", int64(2)).Return(&domain.Movie{ID: "8", Version: 3}, nil)
+	_, err = svc.Delete(context.Background(), "8", domain.AnyVersion)
+	require.NoError(t, err)
+
+	// escrita desfeita (outbox fora) não invalida de novo
+	pub.createdErr = errors.New("outbox down")
+	mockRepo.EXPECT().GetDeleted(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Version: 3}, nil)
+	mockRepo.EXPECT().Restore(gomock.Any(), "8", int64(3)).Return(&domain.Movie{ID: "8", Version: 4}, nil)
+	_, err = svc.Restore(context.Background(), "8", domain.AnyVersion)
+	require.Error(t, err)
+
+	require.Equal(t, []string{"new", "tt8", "8", "8", "8"}, c.ids)
+	require.False(t, c.duringTx)
+}
+
+func TestCacheInvalidator_StaleEntryRereadOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockMovieRepository(ctrl)
+	c := &recInvalidator{}
+	svc := NewMovieService(mockRepo, WithCacheInvalidator(c))
+
+	// o cache ainda tem a versão 2; o banco já está na 3, que o cliente leu
+	gomock.InOrder(
+		mockRepo.EXPECT().Get(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Title: "Old", Year: 2000, Version: 2}, nil),
+		mockRepo.EXPECT().Get(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Title: "Old", Year: 2000, Version: 3}, nil),
+	)
+	mockRepo.EXPECT().Update(gomock.Any(), "8", gomock.Any(), int64(3)).Return(&domain.Movie{ID: "8", Title: "New", Year: 2000, Version: 4}, nil)
+	got, err := svc.Update(context.Background(), "8", domain.Movie{Title: "New"}, []string{domain.FieldTitle}, 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), got.Version)
+	require.Equal(t, []string{"8", "8", "8"}, c.ids) // releitura + pós-commit
+
+	// divergência real: relê uma vez só e devolve 412
+	c.ids = nil
+	mockRepo.EXPECT().GetDeleted(gomock.Any(), "8").Return(&domain.Movie{ID: "8", Version: 5}, nil).Times(2)
+	_, err = svc.Restore(context.Background(), "8", 4)
+	require.ErrorIs(t, err, domain.ErrVersionMismatch)
+	require.Equal(t, []string{"8"}, c.ids)
+}
+
+func TestSuggestTitles_ValidatesAndNeedsIndex(t *testing.T) {
+	_, err := NewMovieService(nil).SuggestTitles(context.Background(), domain.SuggestOptions{Prefix: "lu"})
+	require.ErrorIs(t, err, domain.ErrSuggestUnavailable)
+
+	idx := &recIndex{}
+	svc := NewMovieService(nil, WithTitleIndex(idx))
+	_, err = svc.SuggestTitles(context.Background(), domain.SuggestOptions{Prefix: "  "})
+	require.ErrorIs(t, err, domain.ErrInvalidPrefix)
+
+	got, err := svc.SuggestTitles(context.Background(), domain.SuggestOptions{Prefix: " lu ", Limit: 500})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, domain.SuggestOptions{Prefix: "lu", Limit: domain.MaxSuggestLimit}, idx.got)
+}
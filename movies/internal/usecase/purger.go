@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+)
+
+// Purger esvazia a lixeira periodicamente: filmes apagados há mais de
+// retention são removidos de vez (PurgeDeleted). Com várias réplicas,
+// cada uma roda o seu; a remoção é condicional, então cada filme sai uma
+// vez só.
+type Purger struct {
+	svc       ports.MovieService
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+}
+
+func NewPurger(svc ports.MovieService, retention, interval time.Duration) *Purger {
+	return &Purger{
+		svc:       svc,
+		retention: retention,
+		interval:  interval,
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run purga ao iniciar e depois a cada interval, até ctx ser cancelado.
+func (p *Purger) Run(ctx context.Context) {
+	tick := time.NewTicker(p.interval)
+	defer tick.Stop()
+	for {
+		if n, err := p.PurgeOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("purger: %v (purged %d)", err, n)
+		} else if n > 0 {
+			log.Printf("purger: %d movies purged from trash", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// PurgeOnce remove os filmes na lixeira há mais de retention.
+func (p *Purger) PurgeOnce(ctx context.Context) (int, error) {
+	return p.svc.PurgeDeleted(ctx, p.now().Add(-p.retention))
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurger_PurgeOnce(t *testing.T) {
+	repo := newMemRepo()
+	svc := NewMovieService(repo)
+	ctx := context.Background()
+
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	for id, deletedAt := range map[string]time.Time{
+		"old":    now.Add(-31 * 24 * time.Hour),
+		"recent": now.Add(-time.Hour),
+	} {
+		repo.byID[id] = domain.Movie{ID: id, Title: id, Year: 2000, Version: 2, DeletedAt: &deletedAt}
+	}
+
+	p := NewPurger(svc, 30*24*time.Hour, time.Hour)
+	p.now = func() time.Time { return now }
+	n, err := p.PurgeOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.NotContains(t, repo.byID, "old")
+	require.Contains(t, repo.byID, "recent")
+}
+
+func TestPurger_RunPurgesOnStart(t *testing.T) {
+	repo := newMemRepo()
+	deletedAt := time.Now().Add(-2 * time.Hour)
+	repo.byID["old"] = domain.Movie{ID: "old", Title: "old", Year: 2000, Version: 2, DeletedAt: &deletedAt}
+
+	// ctx já cancelado: Run faz o primeiro purge e sai
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		NewPurger(NewMovieService(repo), time.Hour, time.Hour).Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run não saiu após cancel")
+	}
+	require.NotContains(t, repo.byID, "old")
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+)
+
+// ReplayOptions controla o replay do catálogo.
+type ReplayOptions struct {
+	FromID    string // retoma logo depois deste id (checkpoint); vazio = do início
+	Rate      int    // eventos por segundo; 0 = sem limite
+	BatchSize int    // filmes lidos por página do repositório
+	DryRun    bool   // só conta o que seria publicado
+	// Checkpoint, se definido, recebe o id do último filme publicado ao fim
+	// de cada página e antes de retornar erro.
+	Checkpoint func(lastID string) error
+}
+
+// Replayer publica um movies.snapshot por filme do catálogo atual, na ordem
+// do catálogo (SortByID), para popular consumidores novos.
+type Replayer struct {
+	repo ports.MovieRepository
+	pub  ports.EventPublisher
+}
+
+func NewReplayer(repo ports.MovieRepository, pub ports.EventPublisher) *Replayer {
+	return &Replayer{repo: repo, pub: pub}
+}
+
+// Run devolve quantos filmes foram publicados (ou contados, em DryRun).
+func (r *Replayer) Run(ctx context.Context, opts ReplayOptions) (int, error) {
+	if !opts.DryRun && r.pub == nil {
+		return 0, errors.New("replay: event publisher required")
+	}
+
+	var tick <-chan time.Time
+	if opts.Rate > 0 && !opts.DryRun {
+		t := time.NewTicker(time.Second / time.Duration(opts.Rate))
+		defer t.Stop()
+		tick = t.C
+	}
+
+	list := domain.ListOptions{PageSize: opts.BatchSize, AfterID: opts.FromID}
+	n, last := 0, opts.FromID
+	checkpoint := func() error {
+		if opts.Checkpoint == nil || opts.DryRun || last == "" {
+			return nil
+		}
+		return opts.Checkpoint(last)
+	}
+
+	for {
+		page, err := r.repo.List(ctx, list)
+		if err != nil {
+			return n, fmt.Errorf("list: %w", err)
+		}
+		for _, m := range page.Movies {
+			if opts.DryRun {
+				n++
+				continue
+			}
+			if tick != nil {
+				select {
+				case <-ctx.Done():
+					return n, errors.Join(ctx.Err(), checkpoint())
+				case <-tick:
+				}
+			}
+			if err := r.pub.MovieSnapshot(ctx, m); err != nil {
+				return n, errors.Join(fmt.Errorf("snapshot %s: %w", m.ID, err), checkpoint())
+			}
+			n++
+			last = m.ID
+		}
+		if err := checkpoint(); err != nil {
+			return n, fmt.Errorf("checkpoint: %w", err)
+		}
+		if page.NextPageToken == "" {
+			return n, nil
+		}
+		list.PageToken, list.AfterID = page.NextPageToken, ""
+	}
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedRepo pagina o memRepo em ordem de id com PageToken/AfterID.
+type pagedRepo struct {
+	*memRepo
+	lists int
+}
+
+func (r *pagedRepo) List(ctx context.Context, opts domain.ListOptions) (domain.MoviePage, error) {
+	r.lists++
+	ids := make([]string, 0, len(r.byID))
+	for id := range r.byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	after := opts.AfterID
+	if opts.PageToken != "" {
+		after = opts.PageToken
+	}
+	start := 0
+	if after != "" {
+		start = sort.SearchStrings(ids, after)
+		if start == len(ids) || ids[start] != after {
+			return domain.MoviePage{}, domain.ErrNotFound
+		}
+		start++
+	}
+	var page domain.MoviePage
+	for _, id := range ids[start:] {
+		if len(page.Movies) == opts.PageSize {
+			page.NextPageToken = page.Movies[len(page.Movies)-1].ID
+			break
+		}
+		page.Movies = append(page.Movies, r.byID[id])
+	}
+	return page, nil
+}
+
+func seededPagedRepo(t *testing.T, n int) *pagedRepo {
+	r := &pagedRepo{memRepo: newMemRepo()}
+	for i := 0; i < n; i++ {
+		_, err := r.Create(context.Background(), &domain.Movie{Title: "M" + strconv.Itoa(i), Year: 1900 + i})
+		require.NoError(t, err)
+	}
+	return r
+}
+
+func TestReplay_PublishesSnapshotPerMovieAndCheckpoints(t *testing.T) {
+	repo := seededPagedRepo(t, 5)
+	pub := &recPublisher{}
+	var checkpoints []string
+
+	n, err := NewReplayer(repo, pub).Run(context.Background(), ReplayOptions{
+		BatchSize:  2,
+		Checkpoint: func(id string) error { checkpoints = append(checkpoints, id); return nil },
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	require.Len(t, pub.snapshot, 5)
+	require.Empty(t, pub.created) // replay não finge ser criação
+	require.Equal(t, []string{"gen-2", "gen-4", "gen-5"}, checkpoints)
+	require.Equal(t, 3, repo.lists)
+}
+
+func TestReplay_ResumesFromCheckpoint(t *testing.T) {
+	repo := seededPagedRepo(t, 5)
+	pub := &recPublisher{}
+
+	n, err := NewReplayer(repo, pub).Run(context.Background(), ReplayOptions{FromID: "gen-3", BatchSize: 10})
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, "gen-4", pub.snapshot[0].ID)
+	require.Equal(t, "gen-5", pub.snapshot[1].ID)
+}
+
+func TestReplay_DryRunOnlyCounts(t *testing.T) {
+	repo := seededPagedRepo(t, 3)
+
+	n, err := NewReplayer(repo, nil).Run(context.Background(), ReplayOptions{DryRun: true, BatchSize: 2, Rate: 1})
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+}
+
+type failingSnapshots struct {
+	recPublisher
+	failOn string
+}
+
+func (p *failingSnapshots) MovieSnapshot(ctx context.Context, m domain.Movie) error {
+	if m.ID == p.failOn {
+		return errors.New("nats down")
+	}
+	return p.recPublisher.MovieSnapshot(ctx, m)
+}
+
+func TestReplay_PublishErrorCheckpointsLastSent(t *testing.T) {
+	repo := seededPagedRepo(t, 5)
+	pub := &failingSnapshots{failOn: "gen-3"}
+	var last string
+
+	n, err := NewReplayer(repo, pub).Run(context.Background(), ReplayOptions{
+		BatchSize:  10,
+		Rate:       1000,
+		Checkpoint: func(id string) error { last = id; return nil },
+	})
+	require.Error(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, "gen-2", last)
+}
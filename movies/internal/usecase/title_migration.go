@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/ports"
+)
+
+// TitleMigrationReport resumo de TitleMigrator.Run.
+type TitleMigrationReport struct {
+	Scanned   int
+	Updated   int      // títulos com o ano removido (ou que seriam, em DryRun)
+	Mismatch  []string // ids cujo "(YYYY)" difere de year: não alterados
+	Conflicts []string // ids cujo título limpo já existe com o mesmo ano: não alterados
+	Changed   []string // ids alterados por outra escrita durante a migração: não alterados
+}
+
+// TitleMigrator aplica Movie.SplitTitleYear aos filmes já gravados (seed
+// importado antes da normalização). É idempotente: filmes já migrados não
+// têm mais o "(YYYY)" no título.
+type TitleMigrator struct {
+	repo ports.MovieRepository
+	pub  ports.EventPublisher // opcional: publica movies.updated por filme alterado
+}
+
+func NewTitleMigrator(repo ports.MovieRepository, pub ports.EventPublisher) *TitleMigrator {
+	return &TitleMigrator{repo: repo, pub: pub}
+}
+
+// Run percorre o catálogo em páginas de batchSize. Falha ao publicar o
+// evento não interrompe a migração (como no serviço sem outbox). Alterar o
+// título durante a paginação é seguro: só remove um sufixo, então o filme
+// nunca passa para depois do cursor.
+func (t *TitleMigrator) Run(ctx context.Context, batchSize int, dryRun bool) (TitleMigrationReport, error) {
+	var rep TitleMigrationReport
+	list := domain.ListOptions{PageSize: batchSize}
+	for {
+		page, err := t.repo.List(ctx, list)
+		if err != nil {
+			return rep, fmt.Errorf("list: %w", err)
+		}
+		for _, cur := range page.Movies {
+			rep.Scanned++
+			next := cur
+			switch next.SplitTitleYear() {
+			case domain.TitleUnchanged:
+				continue
+			case domain.TitleYearMismatch:
+				rep.Mismatch = append(rep.Mismatch, cur.ID)
+				continue
+			}
+			if dryRun {
+				rep.Updated++
+				continue
+			}
+			updated, err := t.repo.Update(ctx, cur.ID, &next, cur.Version)
+			if errors.Is(err, domain.ErrAlreadyExists) {
+				rep.Conflicts = append(rep.Conflicts, cur.ID)
+				continue
+			}
+			if errors.Is(err, domain.ErrVersionMismatch) {
+				rep.Changed = append(rep.Changed, cur.ID)
+				continue
+			}
+			if err != nil {
+				return rep, fmt.Errorf("update %s: %w", cur.ID, err)
+			}
+			rep.Updated++
+			if t.pub != nil {
+				_ = t.pub.MovieUpdated(ctx, cur, *updated)
+			}
+		}
+		if page.NextPageToken == "" {
+			return rep, nil
+		}
+		list.PageToken = page.NextPageToken
+	}
+}
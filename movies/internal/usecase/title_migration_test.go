@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/caiqueborghese/sipubtech-challenge/movies/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTitleMigrator_SplitsYearAndReports(t *testing.T) {
+	repo := &pagedRepo{memRepo: newMemRepo()}
+	ctx := context.Background()
+	for _, m := range []domain.Movie{
+		{Title: "Edison Kinetoscopic Record of a Sneeze (1894)", Year: 1894},
+		{Title: "La sortie des usines Lumière (1895)", Year: 1896}, // divergente
+		{Title: "Already Clean", Year: 1900},
+		{Title: "Taken (1901)", Year: 1901},
+		{Title: "Taken", Year: 1901}, // título limpo já existe
+	} {
+		m := m
+		_, err := repo.Create(ctx, &m)
+		require.NoError(t, err)
+	}
+	pub := &recPublisher{}
+
+	// dry-run não grava
+	rep, err := NewTitleMigrator(repo, pub).Run(ctx, 2, true)
+	require.NoError(t, err)
+	require.Equal(t, 5, rep.Scanned)
+	require.Equal(t, 2, rep.Updated)
+	require.Equal(t, "Edison Kinetoscopic Record of a Sneeze (1894)", repo.byID["gen-1"].Title)
+	require.Empty(t, pub.updated)
+
+	rep, err = NewTitleMigrator(repo, pub).Run(ctx, 2, false)
+	require.NoError(t, err)
+	require.Equal(t, TitleMigrationReport{
+		Scanned:   5,
+		Updated:   1,
+		Mismatch:  []string{"gen-2"},
+		Conflicts: []string{"gen-4"},
+	}, rep)
+
+	got := repo.byID["gen-1"]
+	require.Equal(t, "Edison Kinetoscopic Record of a Sneeze", got.Title)
+	require.Equal(t, "Edison Kinetoscopic Record of a Sneeze (1894)", got.OriginalTitle)
+	require.Equal(t, "La sortie des usines Lumière (1895)", repo.byID["gen-2"].Title)
+	require.Len(t, pub.updated, 1)
+
+	// idempotente
+	rep, err = NewTitleMigrator(repo, pub).Run(ctx, 2, false)
+	require.NoError(t, err)
+	require.Equal(t, 0, rep.Updated)
+	require.Equal(t, "Edison Kinetoscopic Record of a Sneeze (1894)", repo.byID["gen-1"].OriginalTitle)
+}
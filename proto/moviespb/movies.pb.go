@@ -9,7 +9,8 @@ package moviespb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -22,13 +23,84 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MovieChange_Type int32
+
+const (
+	MovieChange_TYPE_UNSPECIFIED MovieChange_Type = 0
+	MovieChange_CREATED          MovieChange_Type = 1
+	MovieChange_UPDATED          MovieChange_Type = 2
+	MovieChange_DELETED          MovieChange_Type = 3
+)
+
+// Enum value maps for MovieChange_Type.
+var (
+	MovieChange_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "CREATED",
+		2: "UPDATED",
+		3: "DELETED",
+	}
+	MovieChange_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"CREATED":          1,
+		"UPDATED":          2,
+		"DELETED":          3,
+	}
+)
+
+func (x MovieChange_Type) Enum() *MovieChange_Type {
+	p := new(MovieChange_Type)
+	*p = x
+	return p
+}
+
+func (x MovieChange_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MovieChange_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_moviespb_movies_proto_enumTypes[0].Descriptor()
+}
+
+func (MovieChange_Type) Type() protoreflect.EnumType {
+	return &file_moviespb_movies_proto_enumTypes[0]
+}
+
+func (x MovieChange_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MovieChange_Type.Descriptor instead.
+func (MovieChange_Type) EnumDescriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{14, 0}
+}
+
+// Campos 4+ são opcionais (vazio/0 = não informado); filmes antigos e o
+// seed só têm id, title e year.
 type Movie struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Director      string                 `protobuf:"bytes,3,opt,name=director,proto3" json:"director,omitempty"`
-	Year          int32                  `protobuf:"varint,4,opt,name=year,proto3" json:"year,omitempty"`
-	Genre         string                 `protobuf:"bytes,5,opt,name=genre,proto3" json:"genre,omitempty"`
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Year           int32                  `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
+	Genres         []string               `protobuf:"bytes,4,rep,name=genres,proto3" json:"genres,omitempty"`
+	RuntimeMinutes int32                  `protobuf:"varint,5,opt,name=runtime_minutes,json=runtimeMinutes,proto3" json:"runtime_minutes,omitempty"`
+	Directors      []string               `protobuf:"bytes,6,rep,name=directors,proto3" json:"directors,omitempty"`
+	// Elenco principal, na ordem de billing.
+	Cast     []string `protobuf:"bytes,7,rep,name=cast,proto3" json:"cast,omitempty"`
+	Synopsis string   `protobuf:"bytes,8,opt,name=synopsis,proto3" json:"synopsis,omitempty"`
+	// Código ISO 639-1 (ex.: "en", "pt").
+	OriginalLanguage string `protobuf:"bytes,9,opt,name=original_language,json=originalLanguage,proto3" json:"original_language,omitempty"`
+	PosterUrl        string `protobuf:"bytes,10,opt,name=poster_url,json=posterUrl,proto3" json:"poster_url,omitempty"`
+	// Somente leitura: título como veio na importação do seed, quando o ano
+	// embutido "(YYYY)" foi removido de title.
+	OriginalTitle string `protobuf:"bytes,11,opt,name=original_title,json=originalTitle,proto3" json:"original_title,omitempty"`
+	// Somente leitura: começa em 1 e aumenta a cada alteração do filme. Vai
+	// em expected_version de Update/Delete para evitar sobrescrever edições
+	// concorrentes.
+	Version int64 `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`
+	// Somente leitura: quando o filme foi para a lixeira (só em
+	// ListDeletedMovies e nas mudanças de WatchMovies).
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -77,23 +149,177 @@ func (x *Movie) GetTitle() string {
 	return ""
 }
 
-func (x *Movie) GetDirector() string {
+func (x *Movie) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Movie) GetGenres() []string {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+func (x *Movie) GetRuntimeMinutes() int32 {
+	if x != nil {
+		return x.RuntimeMinutes
+	}
+	return 0
+}
+
+func (x *Movie) GetDirectors() []string {
+	if x != nil {
+		return x.Directors
+	}
+	return nil
+}
+
+func (x *Movie) GetCast() []string {
+	if x != nil {
+		return x.Cast
+	}
+	return nil
+}
+
+func (x *Movie) GetSynopsis() string {
+	if x != nil {
+		return x.Synopsis
+	}
+	return ""
+}
+
+func (x *Movie) GetOriginalLanguage() string {
 	if x != nil {
-		return x.Director
+		return x.OriginalLanguage
 	}
 	return ""
 }
 
-func (x *Movie) GetYear() int32 {
+func (x *Movie) GetPosterUrl() string {
 	if x != nil {
-		return x.Year
+		return x.PosterUrl
+	}
+	return ""
+}
+
+func (x *Movie) GetOriginalTitle() string {
+	if x != nil {
+		return x.OriginalTitle
+	}
+	return ""
+}
+
+func (x *Movie) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Movie) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+// Paginação por cursor (keyset): page_token vazio começa do início;
+// next_page_token vazio indica que não há mais páginas.
+// O page_token só vale para o mesmo sort_by/sort_order que o gerou.
+type ListMoviesRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PageSize  int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Filtros (0/vazio = sem filtro). title_prefix é case-insensitive.
+	MinYear     int32  `protobuf:"varint,3,opt,name=min_year,json=minYear,proto3" json:"min_year,omitempty"`
+	MaxYear     int32  `protobuf:"varint,4,opt,name=max_year,json=maxYear,proto3" json:"max_year,omitempty"`
+	TitlePrefix string `protobuf:"bytes,5,opt,name=title_prefix,json=titlePrefix,proto3" json:"title_prefix,omitempty"`
+	// sort_by: "id" (default), "title" ou "year"; sort_order: "asc" (default) ou "desc".
+	// Valores inválidos retornam INVALID_ARGUMENT.
+	SortBy        string `protobuf:"bytes,6,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	SortOrder     string `protobuf:"bytes,7,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMoviesRequest) Reset() {
+	*x = ListMoviesRequest{}
+	mi := &file_moviespb_movies_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMoviesRequest) ProtoMessage() {}
+
+func (x *ListMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMoviesRequest.ProtoReflect.Descriptor instead.
+func (*ListMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListMoviesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListMoviesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListMoviesRequest) GetMinYear() int32 {
+	if x != nil {
+		return x.MinYear
+	}
+	return 0
+}
+
+func (x *ListMoviesRequest) GetMaxYear() int32 {
+	if x != nil {
+		return x.MaxYear
 	}
 	return 0
 }
 
-func (x *Movie) GetGenre() string {
+func (x *ListMoviesRequest) GetTitlePrefix() string {
+	if x != nil {
+		return x.TitlePrefix
+	}
+	return ""
+}
+
+func (x *ListMoviesRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListMoviesRequest) GetSortOrder() string {
 	if x != nil {
-		return x.Genre
+		return x.SortOrder
 	}
 	return ""
 }
@@ -101,13 +327,14 @@ func (x *Movie) GetGenre() string {
 type ListMoviesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Movies        []*Movie               `protobuf:"bytes,1,rep,name=movies,proto3" json:"movies,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListMoviesResponse) Reset() {
 	*x = ListMoviesResponse{}
-	mi := &file_moviespb_movies_proto_msgTypes[1]
+	mi := &file_moviespb_movies_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -119,7 +346,7 @@ func (x *ListMoviesResponse) String() string {
 func (*ListMoviesResponse) ProtoMessage() {}
 
 func (x *ListMoviesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_moviespb_movies_proto_msgTypes[1]
+	mi := &file_moviespb_movies_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -132,7 +359,7 @@ func (x *ListMoviesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListMoviesResponse.ProtoReflect.Descriptor instead.
 func (*ListMoviesResponse) Descriptor() ([]byte, []int) {
-	return file_moviespb_movies_proto_rawDescGZIP(), []int{1}
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ListMoviesResponse) GetMovies() []*Movie {
@@ -142,6 +369,13 @@ func (x *ListMoviesResponse) GetMovies() []*Movie {
 	return nil
 }
 
+func (x *ListMoviesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type GetMovieRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -151,7 +385,7 @@ type GetMovieRequest struct {
 
 func (x *GetMovieRequest) Reset() {
 	*x = GetMovieRequest{}
-	mi := &file_moviespb_movies_proto_msgTypes[2]
+	mi := &file_moviespb_movies_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -163,7 +397,7 @@ func (x *GetMovieRequest) String() string {
 func (*GetMovieRequest) ProtoMessage() {}
 
 func (x *GetMovieRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_moviespb_movies_proto_msgTypes[2]
+	mi := &file_moviespb_movies_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -176,7 +410,7 @@ func (x *GetMovieRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMovieRequest.ProtoReflect.Descriptor instead.
 func (*GetMovieRequest) Descriptor() ([]byte, []int) {
-	return file_moviespb_movies_proto_rawDescGZIP(), []int{2}
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetMovieRequest) GetId() string {
@@ -195,7 +429,7 @@ type GetMovieResponse struct {
 
 func (x *GetMovieResponse) Reset() {
 	*x = GetMovieResponse{}
-	mi := &file_moviespb_movies_proto_msgTypes[3]
+	mi := &file_moviespb_movies_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -207,7 +441,7 @@ func (x *GetMovieResponse) String() string {
 func (*GetMovieResponse) ProtoMessage() {}
 
 func (x *GetMovieResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_moviespb_movies_proto_msgTypes[3]
+	mi := &file_moviespb_movies_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -220,7 +454,7 @@ func (x *GetMovieResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMovieResponse.ProtoReflect.Descriptor instead.
 func (*GetMovieResponse) Descriptor() ([]byte, []int) {
-	return file_moviespb_movies_proto_rawDescGZIP(), []int{3}
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetMovieResponse) GetMovie() *Movie {
@@ -231,18 +465,23 @@ func (x *GetMovieResponse) GetMovie() *Movie {
 }
 
 type CreateMovieRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Director      string                 `protobuf:"bytes,2,opt,name=director,proto3" json:"director,omitempty"`
-	Year          int32                  `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
-	Genre         string                 `protobuf:"bytes,4,opt,name=genre,proto3" json:"genre,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Title            string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Year             int32                  `protobuf:"varint,2,opt,name=year,proto3" json:"year,omitempty"`
+	Genres           []string               `protobuf:"bytes,3,rep,name=genres,proto3" json:"genres,omitempty"`
+	RuntimeMinutes   int32                  `protobuf:"varint,4,opt,name=runtime_minutes,json=runtimeMinutes,proto3" json:"runtime_minutes,omitempty"`
+	Directors        []string               `protobuf:"bytes,5,rep,name=directors,proto3" json:"directors,omitempty"`
+	Cast             []string               `protobuf:"bytes,6,rep,name=cast,proto3" json:"cast,omitempty"`
+	Synopsis         string                 `protobuf:"bytes,7,opt,name=synopsis,proto3" json:"synopsis,omitempty"`
+	OriginalLanguage string                 `protobuf:"bytes,8,opt,name=original_language,json=originalLanguage,proto3" json:"original_language,omitempty"`
+	PosterUrl        string                 `protobuf:"bytes,9,opt,name=poster_url,json=posterUrl,proto3" json:"poster_url,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *CreateMovieRequest) Reset() {
 	*x = CreateMovieRequest{}
-	mi := &file_moviespb_movies_proto_msgTypes[4]
+	mi := &file_moviespb_movies_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -254,7 +493,7 @@ func (x *CreateMovieRequest) String() string {
 func (*CreateMovieRequest) ProtoMessage() {}
 
 func (x *CreateMovieRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_moviespb_movies_proto_msgTypes[4]
+	mi := &file_moviespb_movies_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -267,7 +506,7 @@ func (x *CreateMovieRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateMovieRequest.ProtoReflect.Descriptor instead.
 func (*CreateMovieRequest) Descriptor() ([]byte, []int) {
-	return file_moviespb_movies_proto_rawDescGZIP(), []int{4}
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateMovieRequest) GetTitle() string {
@@ -277,23 +516,58 @@ func (x *CreateMovieRequest) GetTitle() string {
 	return ""
 }
 
-func (x *CreateMovieRequest) GetDirector() string {
+func (x *CreateMovieRequest) GetYear() int32 {
 	if x != nil {
-		return x.Director
+		return x.Year
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateMovieRequest) GetYear() int32 {
+func (x *CreateMovieRequest) GetGenres() []string {
 	if x != nil {
-		return x.Year
+		return x.Genres
+	}
+	return nil
+}
+
+func (x *CreateMovieRequest) GetRuntimeMinutes() int32 {
+	if x != nil {
+		return x.RuntimeMinutes
 	}
 	return 0
 }
 
-func (x *CreateMovieRequest) GetGenre() string {
+func (x *CreateMovieRequest) GetDirectors() []string {
+	if x != nil {
+		return x.Directors
+	}
+	return nil
+}
+
+func (x *CreateMovieRequest) GetCast() []string {
+	if x != nil {
+		return x.Cast
+	}
+	return nil
+}
+
+func (x *CreateMovieRequest) GetSynopsis() string {
+	if x != nil {
+		return x.Synopsis
+	}
+	return ""
+}
+
+func (x *CreateMovieRequest) GetOriginalLanguage() string {
+	if x != nil {
+		return x.OriginalLanguage
+	}
+	return ""
+}
+
+func (x *CreateMovieRequest) GetPosterUrl() string {
 	if x != nil {
-		return x.Genre
+		return x.PosterUrl
 	}
 	return ""
 }
@@ -307,7 +581,7 @@ type CreateMovieResponse struct {
 
 func (x *CreateMovieResponse) Reset() {
 	*x = CreateMovieResponse{}
-	mi := &file_moviespb_movies_proto_msgTypes[5]
+	mi := &file_moviespb_movies_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -319,7 +593,7 @@ func (x *CreateMovieResponse) String() string {
 func (*CreateMovieResponse) ProtoMessage() {}
 
 func (x *CreateMovieResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_moviespb_movies_proto_msgTypes[5]
+	mi := &file_moviespb_movies_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -332,7 +606,7 @@ func (x *CreateMovieResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateMovieResponse.ProtoReflect.Descriptor instead.
 func (*CreateMovieResponse) Descriptor() ([]byte, []int) {
-	return file_moviespb_movies_proto_rawDescGZIP(), []int{5}
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateMovieResponse) GetMovie() *Movie {
@@ -342,28 +616,38 @@ func (x *CreateMovieResponse) GetMovie() *Movie {
 	return nil
 }
 
-type DeleteMovieRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Atualização parcial: só os campos em update_mask (nomes dos campos de
+// Movie, ex.: "title", "genres", "poster_url") são copiados de movie; máscara vazia substitui todos os campos editáveis.
+// movie.id é ignorado (o alvo é sempre id).
+// expected_version (obrigatório em Update, Delete e Restore): versão do
+// filme que o cliente leu; 0 (ausente) é INVALID_ARGUMENT. Se o filme mudou
+// desde então, nada é gravado e a resposta é ABORTED; o cliente deve reler
+// o filme e refazer a alteração.
+type UpdateMovieRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Movie           *Movie                 `protobuf:"bytes,2,opt,name=movie,proto3" json:"movie,omitempty"`
+	UpdateMask      *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *DeleteMovieRequest) Reset() {
-	*x = DeleteMovieRequest{}
-	mi := &file_moviespb_movies_proto_msgTypes[6]
+func (x *UpdateMovieRequest) Reset() {
+	*x = UpdateMovieRequest{}
+	mi := &file_moviespb_movies_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteMovieRequest) String() string {
+func (x *UpdateMovieRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteMovieRequest) ProtoMessage() {}
+func (*UpdateMovieRequest) ProtoMessage() {}
 
-func (x *DeleteMovieRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_moviespb_movies_proto_msgTypes[6]
+func (x *UpdateMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -374,40 +658,61 @@ func (x *DeleteMovieRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteMovieRequest.ProtoReflect.Descriptor instead.
-func (*DeleteMovieRequest) Descriptor() ([]byte, []int) {
-	return file_moviespb_movies_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use UpdateMovieRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMovieRequest) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *DeleteMovieRequest) GetId() string {
+func (x *UpdateMovieRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-type DeleteMovieResponse struct {
+func (x *UpdateMovieRequest) GetMovie() *Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+func (x *UpdateMovieRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateMovieRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type UpdateMovieResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Movie         *Movie                 `protobuf:"bytes,1,opt,name=movie,proto3" json:"movie,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteMovieResponse) Reset() {
-	*x = DeleteMovieResponse{}
-	mi := &file_moviespb_movies_proto_msgTypes[7]
+func (x *UpdateMovieResponse) Reset() {
+	*x = UpdateMovieResponse{}
+	mi := &file_moviespb_movies_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteMovieResponse) String() string {
+func (x *UpdateMovieResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteMovieResponse) ProtoMessage() {}
+func (*UpdateMovieResponse) ProtoMessage() {}
 
-func (x *DeleteMovieResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_moviespb_movies_proto_msgTypes[7]
+func (x *UpdateMovieResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -418,52 +723,803 @@ func (x *DeleteMovieResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteMovieResponse.ProtoReflect.Descriptor instead.
-func (*DeleteMovieResponse) Descriptor() ([]byte, []int) {
-	return file_moviespb_movies_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use UpdateMovieResponse.ProtoReflect.Descriptor instead.
+func (*UpdateMovieResponse) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *DeleteMovieResponse) GetSuccess() bool {
+func (x *UpdateMovieResponse) GetMovie() *Movie {
 	if x != nil {
-		return x.Success
+		return x.Movie
 	}
-	return false
+	return nil
 }
 
-var File_moviespb_movies_proto protoreflect.FileDescriptor
+// DeleteMovie move o filme para a lixeira: some de List/Get/Search, mas
+// pode voltar com RestoreMovie até ser purgado (remoção definitiva, depois
+// do prazo de retenção do servidor).
+type DeleteMovieRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteMovieRequest) Reset() {
+	*x = DeleteMovieRequest{}
+	mi := &file_moviespb_movies_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMovieRequest) ProtoMessage() {}
+
+func (x *DeleteMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMovieRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMovieRequest) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteMovieRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteMovieRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+// movie é o filme já na lixeira, com a nova versão e deleted_at.
+type DeleteMovieResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Movie         *Movie                 `protobuf:"bytes,2,opt,name=movie,proto3" json:"movie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMovieResponse) Reset() {
+	*x = DeleteMovieResponse{}
+	mi := &file_moviespb_movies_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMovieResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMovieResponse) ProtoMessage() {}
+
+func (x *DeleteMovieResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMovieResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMovieResponse) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteMovieResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteMovieResponse) GetMovie() *Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+// RestoreMovie devolve um filme da lixeira ao catálogo; NOT_FOUND se ele
+// não está na lixeira. expected_version como no Update.
+type RestoreMovieRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RestoreMovieRequest) Reset() {
+	*x = RestoreMovieRequest{}
+	mi := &file_moviespb_movies_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreMovieRequest) ProtoMessage() {}
+
+func (x *RestoreMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreMovieRequest.ProtoReflect.Descriptor instead.
+func (*RestoreMovieRequest) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RestoreMovieRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RestoreMovieRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type RestoreMovieResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Movie         *Movie                 `protobuf:"bytes,1,opt,name=movie,proto3" json:"movie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreMovieResponse) Reset() {
+	*x = RestoreMovieResponse{}
+	mi := &file_moviespb_movies_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreMovieResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreMovieResponse) ProtoMessage() {}
+
+func (x *RestoreMovieResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreMovieResponse.ProtoReflect.Descriptor instead.
+func (*RestoreMovieResponse) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RestoreMovieResponse) GetMovie() *Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+// Acompanha mudanças no catálogo em tempo real. resume_token vazio começa
+// "de agora"; com o resume_token da última MovieChange recebida, continua
+// logo depois dela (sem lacunas). Token desconhecido ou expirado retorna
+// INVALID_ARGUMENT: o cliente deve recarregar o estado e assistir sem token.
+type WatchMoviesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ResumeToken   string                 `protobuf:"bytes,1,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchMoviesRequest) Reset() {
+	*x = WatchMoviesRequest{}
+	mi := &file_moviespb_movies_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchMoviesRequest) ProtoMessage() {}
+
+func (x *WatchMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchMoviesRequest.ProtoReflect.Descriptor instead.
+func (*WatchMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchMoviesRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type MovieChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  MovieChange_Type       `protobuf:"varint,1,opt,name=type,proto3,enum=moviespb.MovieChange_Type" json:"type,omitempty"`
+	// Estado depois da mudança; em DELETED, o filme como estava antes de ser
+	// removido (ou só o id, se o servidor não tiver o estado anterior).
+	// Ir para a lixeira é DELETED (com deleted_at) e restaurar é CREATED; o
+	// purge emite DELETED de novo, quando o filme deixa de existir.
+	Movie         *Movie `protobuf:"bytes,2,opt,name=movie,proto3" json:"movie,omitempty"`
+	ResumeToken   string `protobuf:"bytes,3,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MovieChange) Reset() {
+	*x = MovieChange{}
+	mi := &file_moviespb_movies_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MovieChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MovieChange) ProtoMessage() {}
+
+func (x *MovieChange) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MovieChange.ProtoReflect.Descriptor instead.
+func (*MovieChange) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MovieChange) GetType() MovieChange_Type {
+	if x != nil {
+		return x.Type
+	}
+	return MovieChange_TYPE_UNSPECIFIED
+}
+
+func (x *MovieChange) GetMovie() *Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+func (x *MovieChange) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// Busca textual em title, original_title, directors, cast, genres e
+// synopsis, sem diferenciar maiúsculas nem acentos ("lumiere" encontra
+// "Lumière"). Termos são combinados com OU e os resultados vêm por
+// relevância (score decrescente); "frase exata" e -termo seguem a sintaxe
+// do $text do Mongo. query vazia ou com mais de 200 caracteres retorna
+// INVALID_ARGUMENT.
+type SearchMoviesRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Query    string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	PageSize int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Só vale para a mesma query e filtros de ano que o geraram.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	MinYear       int32  `protobuf:"varint,4,opt,name=min_year,json=minYear,proto3" json:"min_year,omitempty"`
+	MaxYear       int32  `protobuf:"varint,5,opt,name=max_year,json=maxYear,proto3" json:"max_year,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMoviesRequest) Reset() {
+	*x = SearchMoviesRequest{}
+	mi := &file_moviespb_movies_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMoviesRequest) ProtoMessage() {}
+
+func (x *SearchMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMoviesRequest.ProtoReflect.Descriptor instead.
+func (*SearchMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SearchMoviesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchMoviesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetMinYear() int32 {
+	if x != nil {
+		return x.MinYear
+	}
+	return 0
+}
+
+func (x *SearchMoviesRequest) GetMaxYear() int32 {
+	if x != nil {
+		return x.MaxYear
+	}
+	return 0
+}
+
+type SearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Movie *Movie                 `protobuf:"bytes,1,opt,name=movie,proto3" json:"movie,omitempty"`
+	// Relevância (maior = mais relevante); só comparável dentro da mesma query.
+	Score         float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_moviespb_movies_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SearchResult) GetMovie() *Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+func (x *SearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type SearchMoviesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SearchResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMoviesResponse) Reset() {
+	*x = SearchMoviesResponse{}
+	mi := &file_moviespb_movies_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMoviesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMoviesResponse) ProtoMessage() {}
+
+func (x *SearchMoviesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMoviesResponse.ProtoReflect.Descriptor instead.
+func (*SearchMoviesResponse) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SearchMoviesResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SearchMoviesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Autocomplete de títulos, servido de um índice em memória do serviço.
+// Casa filmes com alguma palavra do título começando por prefix, sem
+// diferenciar maiúsculas nem acentos. Ordem: títulos que começam pelo
+// prefixo, depois os mais recentes. prefix vazio ou com mais de 100
+// caracteres retorna INVALID_ARGUMENT.
+type SuggestTitlesRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Prefix string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Default 10, máximo 50.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestTitlesRequest) Reset() {
+	*x = SuggestTitlesRequest{}
+	mi := &file_moviespb_movies_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestTitlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestTitlesRequest) ProtoMessage() {}
+
+func (x *SuggestTitlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestTitlesRequest.ProtoReflect.Descriptor instead.
+func (*SuggestTitlesRequest) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SuggestTitlesRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SuggestTitlesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type TitleSuggestion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Year          int32                  `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TitleSuggestion) Reset() {
+	*x = TitleSuggestion{}
+	mi := &file_moviespb_movies_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TitleSuggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TitleSuggestion) ProtoMessage() {}
+
+func (x *TitleSuggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TitleSuggestion.ProtoReflect.Descriptor instead.
+func (*TitleSuggestion) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TitleSuggestion) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TitleSuggestion) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TitleSuggestion) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+type SuggestTitlesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestions   []*TitleSuggestion     `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestTitlesResponse) Reset() {
+	*x = SuggestTitlesResponse{}
+	mi := &file_moviespb_movies_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestTitlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestTitlesResponse) ProtoMessage() {}
+
+func (x *SuggestTitlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moviespb_movies_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestTitlesResponse.ProtoReflect.Descriptor instead.
+func (*SuggestTitlesResponse) Descriptor() ([]byte, []int) {
+	return file_moviespb_movies_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SuggestTitlesResponse) GetSuggestions() []*TitleSuggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+var File_moviespb_movies_proto protoreflect.FileDescriptor
 
 const file_moviespb_movies_proto_rawDesc = "" +
 	"\n" +
-	"\x15moviespb/movies.proto\x12\bmoviespb\x1a\x1bgoogle/protobuf/empty.proto\"s\n" +
+	"\x15moviespb/movies.proto\x12\bmoviespb\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x98\x03\n" +
 	"\x05Movie\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1a\n" +
-	"\bdirector\x18\x03 \x01(\tR\bdirector\x12\x12\n" +
-	"\x04year\x18\x04 \x01(\x05R\x04year\x12\x14\n" +
-	"\x05genre\x18\x05 \x01(\tR\x05genre\"=\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04year\x18\x03 \x01(\x05R\x04year\x12\x16\n" +
+	"\x06genres\x18\x04 \x03(\tR\x06genres\x12'\n" +
+	"\x0fruntime_minutes\x18\x05 \x01(\x05R\x0eruntimeMinutes\x12\x1c\n" +
+	"\tdirectors\x18\x06 \x03(\tR\tdirectors\x12\x12\n" +
+	"\x04cast\x18\a \x03(\tR\x04cast\x12\x1a\n" +
+	"\bsynopsis\x18\b \x01(\tR\bsynopsis\x12+\n" +
+	"\x11original_language\x18\t \x01(\tR\x10originalLanguage\x12\x1d\n" +
+	"\n" +
+	"poster_url\x18\n" +
+	" \x01(\tR\tposterUrl\x12%\n" +
+	"\x0eoriginal_title\x18\v \x01(\tR\roriginalTitle\x12\x18\n" +
+	"\aversion\x18\f \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"deleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"\xe0\x01\n" +
+	"\x11ListMoviesRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x19\n" +
+	"\bmin_year\x18\x03 \x01(\x05R\aminYear\x12\x19\n" +
+	"\bmax_year\x18\x04 \x01(\x05R\amaxYear\x12!\n" +
+	"\ftitle_prefix\x18\x05 \x01(\tR\vtitlePrefix\x12\x17\n" +
+	"\asort_by\x18\x06 \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\a \x01(\tR\tsortOrder\"e\n" +
 	"\x12ListMoviesResponse\x12'\n" +
-	"\x06movies\x18\x01 \x03(\v2\x0f.moviespb.MovieR\x06movies\"!\n" +
+	"\x06movies\x18\x01 \x03(\v2\x0f.moviespb.MovieR\x06movies\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"!\n" +
 	"\x0fGetMovieRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"9\n" +
 	"\x10GetMovieResponse\x12%\n" +
-	"\x05movie\x18\x01 \x01(\v2\x0f.moviespb.MovieR\x05movie\"p\n" +
+	"\x05movie\x18\x01 \x01(\v2\x0f.moviespb.MovieR\x05movie\"\x99\x02\n" +
 	"\x12CreateMovieRequest\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1a\n" +
-	"\bdirector\x18\x02 \x01(\tR\bdirector\x12\x12\n" +
-	"\x04year\x18\x03 \x01(\x05R\x04year\x12\x14\n" +
-	"\x05genre\x18\x04 \x01(\tR\x05genre\"<\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04year\x18\x02 \x01(\x05R\x04year\x12\x16\n" +
+	"\x06genres\x18\x03 \x03(\tR\x06genres\x12'\n" +
+	"\x0fruntime_minutes\x18\x04 \x01(\x05R\x0eruntimeMinutes\x12\x1c\n" +
+	"\tdirectors\x18\x05 \x03(\tR\tdirectors\x12\x12\n" +
+	"\x04cast\x18\x06 \x03(\tR\x04cast\x12\x1a\n" +
+	"\bsynopsis\x18\a \x01(\tR\bsynopsis\x12+\n" +
+	"\x11original_language\x18\b \x01(\tR\x10originalLanguage\x12\x1d\n" +
+	"\n" +
+	"poster_url\x18\t \x01(\tR\tposterUrl\"<\n" +
 	"\x13CreateMovieResponse\x12%\n" +
-	"\x05movie\x18\x01 \x01(\v2\x0f.moviespb.MovieR\x05movie\"$\n" +
+	"\x05movie\x18\x01 \x01(\v2\x0f.moviespb.MovieR\x05movie\"\xb3\x01\n" +
+	"\x12UpdateMovieRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x05movie\x18\x02 \x01(\v2\x0f.moviespb.MovieR\x05movie\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x03R\x0fexpectedVersion\"<\n" +
+	"\x13UpdateMovieResponse\x12%\n" +
+	"\x05movie\x18\x01 \x01(\v2\x0f.moviespb.MovieR\x05movie\"O\n" +
 	"\x12DeleteMovieRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"/\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"V\n" +
 	"\x13DeleteMovieResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xad\x02\n" +
-	"\fMovieService\x12B\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12%\n" +
+	"\x05movie\x18\x02 \x01(\v2\x0f.moviespb.MovieR\x05movie\"P\n" +
+	"\x13RestoreMovieRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"=\n" +
+	"\x14RestoreMovieResponse\x12%\n" +
+	"\x05movie\x18\x01 \x01(\v2\x0f.moviespb.MovieR\x05movie\"7\n" +
+	"\x12WatchMoviesRequest\x12!\n" +
+	"\fresume_token\x18\x01 \x01(\tR\vresumeToken\"\xcc\x01\n" +
+	"\vMovieChange\x12.\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1a.moviespb.MovieChange.TypeR\x04type\x12%\n" +
+	"\x05movie\x18\x02 \x01(\v2\x0f.moviespb.MovieR\x05movie\x12!\n" +
+	"\fresume_token\x18\x03 \x01(\tR\vresumeToken\"C\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aCREATED\x10\x01\x12\v\n" +
+	"\aUPDATED\x10\x02\x12\v\n" +
+	"\aDELETED\x10\x03\"\x9d\x01\n" +
+	"\x13SearchMoviesRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x19\n" +
+	"\bmin_year\x18\x04 \x01(\x05R\aminYear\x12\x19\n" +
+	"\bmax_year\x18\x05 \x01(\x05R\amaxYear\"K\n" +
+	"\fSearchResult\x12%\n" +
+	"\x05movie\x18\x01 \x01(\v2\x0f.moviespb.MovieR\x05movie\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\"p\n" +
+	"\x14SearchMoviesResponse\x120\n" +
+	"\aresults\x18\x01 \x03(\v2\x16.moviespb.SearchResultR\aresults\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"D\n" +
+	"\x14SuggestTitlesRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"K\n" +
+	"\x0fTitleSuggestion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04year\x18\x03 \x01(\x05R\x04year\"T\n" +
+	"\x15SuggestTitlesResponse\x12;\n" +
+	"\vsuggestions\x18\x01 \x03(\v2\x19.moviespb.TitleSuggestionR\vsuggestions2\xce\x06\n" +
+	"\fMovieService\x12G\n" +
 	"\n" +
-	"ListMovies\x12\x16.google.protobuf.Empty\x1a\x1c.moviespb.ListMoviesResponse\x12A\n" +
+	"ListMovies\x12\x1b.moviespb.ListMoviesRequest\x1a\x1c.moviespb.ListMoviesResponse\x12A\n" +
 	"\bGetMovie\x12\x19.moviespb.GetMovieRequest\x1a\x1a.moviespb.GetMovieResponse\x12J\n" +
 	"\vCreateMovie\x12\x1c.moviespb.CreateMovieRequest\x1a\x1d.moviespb.CreateMovieResponse\x12J\n" +
-	"\vDeleteMovie\x12\x1c.moviespb.DeleteMovieRequest\x1a\x1d.moviespb.DeleteMovieResponseB>Z<github.com/caiqueborghese/sipubtech-challenge/proto/moviespbb\x06proto3"
+	"\vUpdateMovie\x12\x1c.moviespb.UpdateMovieRequest\x1a\x1d.moviespb.UpdateMovieResponse\x12J\n" +
+	"\vDeleteMovie\x12\x1c.moviespb.DeleteMovieRequest\x1a\x1d.moviespb.DeleteMovieResponse\x12M\n" +
+	"\fRestoreMovie\x12\x1d.moviespb.RestoreMovieRequest\x1a\x1e.moviespb.RestoreMovieResponse\x12N\n" +
+	"\x11ListDeletedMovies\x12\x1b.moviespb.ListMoviesRequest\x1a\x1c.moviespb.ListMoviesResponse\x12H\n" +
+	"\x0fGetDeletedMovie\x12\x19.moviespb.GetMovieRequest\x1a\x1a.moviespb.GetMovieResponse\x12D\n" +
+	"\vWatchMovies\x12\x1c.moviespb.WatchMoviesRequest\x1a\x15.moviespb.MovieChange0\x01\x12M\n" +
+	"\fSearchMovies\x12\x1d.moviespb.SearchMoviesRequest\x1a\x1e.moviespb.SearchMoviesResponse\x12P\n" +
+	"\rSuggestTitles\x12\x1e.moviespb.SuggestTitlesRequest\x1a\x1f.moviespb.SuggestTitlesResponseB>Z<github.com/caiqueborghese/sipubtech-challenge/proto/moviespbb\x06proto3"
 
 var (
 	file_moviespb_movies_proto_rawDescOnce sync.Once
@@ -477,35 +1533,76 @@ func file_moviespb_movies_proto_rawDescGZIP() []byte {
 	return file_moviespb_movies_proto_rawDescData
 }
 
-var file_moviespb_movies_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_moviespb_movies_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_moviespb_movies_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_moviespb_movies_proto_goTypes = []any{
-	(*Movie)(nil),               // 0: moviespb.Movie
-	(*ListMoviesResponse)(nil),  // 1: moviespb.ListMoviesResponse
-	(*GetMovieRequest)(nil),     // 2: moviespb.GetMovieRequest
-	(*GetMovieResponse)(nil),    // 3: moviespb.GetMovieResponse
-	(*CreateMovieRequest)(nil),  // 4: moviespb.CreateMovieRequest
-	(*CreateMovieResponse)(nil), // 5: moviespb.CreateMovieResponse
-	(*DeleteMovieRequest)(nil),  // 6: moviespb.DeleteMovieRequest
-	(*DeleteMovieResponse)(nil), // 7: moviespb.DeleteMovieResponse
-	(*emptypb.Empty)(nil),       // 8: google.protobuf.Empty
+	(MovieChange_Type)(0),         // 0: moviespb.MovieChange.Type
+	(*Movie)(nil),                 // 1: moviespb.Movie
+	(*ListMoviesRequest)(nil),     // 2: moviespb.ListMoviesRequest
+	(*ListMoviesResponse)(nil),    // 3: moviespb.ListMoviesResponse
+	(*GetMovieRequest)(nil),       // 4: moviespb.GetMovieRequest
+	(*GetMovieResponse)(nil),      // 5: moviespb.GetMovieResponse
+	(*CreateMovieRequest)(nil),    // 6: moviespb.CreateMovieRequest
+	(*CreateMovieResponse)(nil),   // 7: moviespb.CreateMovieResponse
+	(*UpdateMovieRequest)(nil),    // 8: moviespb.UpdateMovieRequest
+	(*UpdateMovieResponse)(nil),   // 9: moviespb.UpdateMovieResponse
+	(*DeleteMovieRequest)(nil),    // 10: moviespb.DeleteMovieRequest
+	(*DeleteMovieResponse)(nil),   // 11: moviespb.DeleteMovieResponse
+	(*RestoreMovieRequest)(nil),   // 12: moviespb.RestoreMovieRequest
+	(*RestoreMovieResponse)(nil),  // 13: moviespb.RestoreMovieResponse
+	(*WatchMoviesRequest)(nil),    // 14: moviespb.WatchMoviesRequest
+	(*MovieChange)(nil),           // 15: moviespb.MovieChange
+	(*SearchMoviesRequest)(nil),   // 16: moviespb.SearchMoviesRequest
+	(*SearchResult)(nil),          // 17: moviespb.SearchResult
+	(*SearchMoviesResponse)(nil),  // 18: moviespb.SearchMoviesResponse
+	(*SuggestTitlesRequest)(nil),  // 19: moviespb.SuggestTitlesRequest
+	(*TitleSuggestion)(nil),       // 20: moviespb.TitleSuggestion
+	(*SuggestTitlesResponse)(nil), // 21: moviespb.SuggestTitlesResponse
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil), // 23: google.protobuf.FieldMask
 }
 var file_moviespb_movies_proto_depIdxs = []int32{
-	0, // 0: moviespb.ListMoviesResponse.movies:type_name -> moviespb.Movie
-	0, // 1: moviespb.GetMovieResponse.movie:type_name -> moviespb.Movie
-	0, // 2: moviespb.CreateMovieResponse.movie:type_name -> moviespb.Movie
-	8, // 3: moviespb.MovieService.ListMovies:input_type -> google.protobuf.Empty
-	2, // 4: moviespb.MovieService.GetMovie:input_type -> moviespb.GetMovieRequest
-	4, // 5: moviespb.MovieService.CreateMovie:input_type -> moviespb.CreateMovieRequest
-	6, // 6: moviespb.MovieService.DeleteMovie:input_type -> moviespb.DeleteMovieRequest
-	1, // 7: moviespb.MovieService.ListMovies:output_type -> moviespb.ListMoviesResponse
-	3, // 8: moviespb.MovieService.GetMovie:output_type -> moviespb.GetMovieResponse
-	5, // 9: moviespb.MovieService.CreateMovie:output_type -> moviespb.CreateMovieResponse
-	7, // 10: moviespb.MovieService.DeleteMovie:output_type -> moviespb.DeleteMovieResponse
-	7, // [7:11] is the sub-list for method output_type
-	3, // [3:7] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	22, // 0: moviespb.Movie.deleted_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: moviespb.ListMoviesResponse.movies:type_name -> moviespb.Movie
+	1,  // 2: moviespb.GetMovieResponse.movie:type_name -> moviespb.Movie
+	1,  // 3: moviespb.CreateMovieResponse.movie:type_name -> moviespb.Movie
+	1,  // 4: moviespb.UpdateMovieRequest.movie:type_name -> moviespb.Movie
+	23, // 5: moviespb.UpdateMovieRequest.update_mask:type_name -> google.protobuf.FieldMask
+	1,  // 6: moviespb.UpdateMovieResponse.movie:type_name -> moviespb.Movie
+	1,  // 7: moviespb.DeleteMovieResponse.movie:type_name -> moviespb.Movie
+	1,  // 8: moviespb.RestoreMovieResponse.movie:type_name -> moviespb.Movie
+	0,  // 9: moviespb.MovieChange.type:type_name -> moviespb.MovieChange.Type
+	1,  // 10: moviespb.MovieChange.movie:type_name -> moviespb.Movie
+	1,  // 11: moviespb.SearchResult.movie:type_name -> moviespb.Movie
+	17, // 12: moviespb.SearchMoviesResponse.results:type_name -> moviespb.SearchResult
+	20, // 13: moviespb.SuggestTitlesResponse.suggestions:type_name -> moviespb.TitleSuggestion
+	2,  // 14: moviespb.MovieService.ListMovies:input_type -> moviespb.ListMoviesRequest
+	4,  // 15: moviespb.MovieService.GetMovie:input_type -> moviespb.GetMovieRequest
+	6,  // 16: moviespb.MovieService.CreateMovie:input_type -> moviespb.CreateMovieRequest
+	8,  // 17: moviespb.MovieService.UpdateMovie:input_type -> moviespb.UpdateMovieRequest
+	10, // 18: moviespb.MovieService.DeleteMovie:input_type -> moviespb.DeleteMovieRequest
+	12, // 19: moviespb.MovieService.RestoreMovie:input_type -> moviespb.RestoreMovieRequest
+	2,  // 20: moviespb.MovieService.ListDeletedMovies:input_type -> moviespb.ListMoviesRequest
+	4,  // 21: moviespb.MovieService.GetDeletedMovie:input_type -> moviespb.GetMovieRequest
+	14, // 22: moviespb.MovieService.WatchMovies:input_type -> moviespb.WatchMoviesRequest
+	16, // 23: moviespb.MovieService.SearchMovies:input_type -> moviespb.SearchMoviesRequest
+	19, // 24: moviespb.MovieService.SuggestTitles:input_type -> moviespb.SuggestTitlesRequest
+	3,  // 25: moviespb.MovieService.ListMovies:output_type -> moviespb.ListMoviesResponse
+	5,  // 26: moviespb.MovieService.GetMovie:output_type -> moviespb.GetMovieResponse
+	7,  // 27: moviespb.MovieService.CreateMovie:output_type -> moviespb.CreateMovieResponse
+	9,  // 28: moviespb.MovieService.UpdateMovie:output_type -> moviespb.UpdateMovieResponse
+	11, // 29: moviespb.MovieService.DeleteMovie:output_type -> moviespb.DeleteMovieResponse
+	13, // 30: moviespb.MovieService.RestoreMovie:output_type -> moviespb.RestoreMovieResponse
+	3,  // 31: moviespb.MovieService.ListDeletedMovies:output_type -> moviespb.ListMoviesResponse
+	5,  // 32: moviespb.MovieService.GetDeletedMovie:output_type -> moviespb.GetMovieResponse
+	15, // 33: moviespb.MovieService.WatchMovies:output_type -> moviespb.MovieChange
+	18, // 34: moviespb.MovieService.SearchMovies:output_type -> moviespb.SearchMoviesResponse
+	21, // 35: moviespb.MovieService.SuggestTitles:output_type -> moviespb.SuggestTitlesResponse
+	25, // [25:36] is the sub-list for method output_type
+	14, // [14:25] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_moviespb_movies_proto_init() }
@@ -518,13 +1615,14 @@ func file_moviespb_movies_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_moviespb_movies_proto_rawDesc), len(file_moviespb_movies_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      1,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_moviespb_movies_proto_goTypes,
 		DependencyIndexes: file_moviespb_movies_proto_depIdxs,
+		EnumInfos:         file_moviespb_movies_proto_enumTypes,
 		MessageInfos:      file_moviespb_movies_proto_msgTypes,
 	}.Build()
 	File_moviespb_movies_proto = out.File
@@ -11,7 +11,6 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -20,20 +19,38 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MovieService_ListMovies_FullMethodName  = "/moviespb.MovieService/ListMovies"
-	MovieService_GetMovie_FullMethodName    = "/moviespb.MovieService/GetMovie"
-	MovieService_CreateMovie_FullMethodName = "/moviespb.MovieService/CreateMovie"
-	MovieService_DeleteMovie_FullMethodName = "/moviespb.MovieService/DeleteMovie"
+	MovieService_ListMovies_FullMethodName        = "/moviespb.MovieService/ListMovies"
+	MovieService_GetMovie_FullMethodName          = "/moviespb.MovieService/GetMovie"
+	MovieService_CreateMovie_FullMethodName       = "/moviespb.MovieService/CreateMovie"
+	MovieService_UpdateMovie_FullMethodName       = "/moviespb.MovieService/UpdateMovie"
+	MovieService_DeleteMovie_FullMethodName       = "/moviespb.MovieService/DeleteMovie"
+	MovieService_RestoreMovie_FullMethodName      = "/moviespb.MovieService/RestoreMovie"
+	MovieService_ListDeletedMovies_FullMethodName = "/moviespb.MovieService/ListDeletedMovies"
+	MovieService_GetDeletedMovie_FullMethodName   = "/moviespb.MovieService/GetDeletedMovie"
+	MovieService_WatchMovies_FullMethodName       = "/moviespb.MovieService/WatchMovies"
+	MovieService_SearchMovies_FullMethodName      = "/moviespb.MovieService/SearchMovies"
+	MovieService_SuggestTitles_FullMethodName     = "/moviespb.MovieService/SuggestTitles"
 )
 
 // MovieServiceClient is the client API for MovieService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MovieServiceClient interface {
-	ListMovies(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListMoviesResponse, error)
+	ListMovies(ctx context.Context, in *ListMoviesRequest, opts ...grpc.CallOption) (*ListMoviesResponse, error)
 	GetMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*GetMovieResponse, error)
 	CreateMovie(ctx context.Context, in *CreateMovieRequest, opts ...grpc.CallOption) (*CreateMovieResponse, error)
+	UpdateMovie(ctx context.Context, in *UpdateMovieRequest, opts ...grpc.CallOption) (*UpdateMovieResponse, error)
 	DeleteMovie(ctx context.Context, in *DeleteMovieRequest, opts ...grpc.CallOption) (*DeleteMovieResponse, error)
+	RestoreMovie(ctx context.Context, in *RestoreMovieRequest, opts ...grpc.CallOption) (*RestoreMovieResponse, error)
+	// Lixeira: filmes apagados e ainda não purgados, com os mesmos filtros,
+	// ordenação e paginação do ListMovies (page_token de um não vale no outro).
+	ListDeletedMovies(ctx context.Context, in *ListMoviesRequest, opts ...grpc.CallOption) (*ListMoviesResponse, error)
+	// Filme na lixeira (NOT_FOUND se ele está no catálogo), com a versão
+	// para o expected_version do RestoreMovie.
+	GetDeletedMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*GetMovieResponse, error)
+	WatchMovies(ctx context.Context, in *WatchMoviesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MovieChange], error)
+	SearchMovies(ctx context.Context, in *SearchMoviesRequest, opts ...grpc.CallOption) (*SearchMoviesResponse, error)
+	SuggestTitles(ctx context.Context, in *SuggestTitlesRequest, opts ...grpc.CallOption) (*SuggestTitlesResponse, error)
 }
 
 type movieServiceClient struct {
@@ -44,7 +61,7 @@ func NewMovieServiceClient(cc grpc.ClientConnInterface) MovieServiceClient {
 	return &movieServiceClient{cc}
 }
 
-func (c *movieServiceClient) ListMovies(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListMoviesResponse, error) {
+func (c *movieServiceClient) ListMovies(ctx context.Context, in *ListMoviesRequest, opts ...grpc.CallOption) (*ListMoviesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListMoviesResponse)
 	err := c.cc.Invoke(ctx, MovieService_ListMovies_FullMethodName, in, out, cOpts...)
@@ -74,6 +91,16 @@ func (c *movieServiceClient) CreateMovie(ctx context.Context, in *CreateMovieReq
 	return out, nil
 }
 
+func (c *movieServiceClient) UpdateMovie(ctx context.Context, in *UpdateMovieRequest, opts ...grpc.CallOption) (*UpdateMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateMovieResponse)
+	err := c.cc.Invoke(ctx, MovieService_UpdateMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *movieServiceClient) DeleteMovie(ctx context.Context, in *DeleteMovieRequest, opts ...grpc.CallOption) (*DeleteMovieResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteMovieResponse)
@@ -84,14 +111,94 @@ func (c *movieServiceClient) DeleteMovie(ctx context.Context, in *DeleteMovieReq
 	return out, nil
 }
 
+func (c *movieServiceClient) RestoreMovie(ctx context.Context, in *RestoreMovieRequest, opts ...grpc.CallOption) (*RestoreMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreMovieResponse)
+	err := c.cc.Invoke(ctx, MovieService_RestoreMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) ListDeletedMovies(ctx context.Context, in *ListMoviesRequest, opts ...grpc.CallOption) (*ListMoviesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMoviesResponse)
+	err := c.cc.Invoke(ctx, MovieService_ListDeletedMovies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) GetDeletedMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*GetMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMovieResponse)
+	err := c.cc.Invoke(ctx, MovieService_GetDeletedMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) WatchMovies(ctx context.Context, in *WatchMoviesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MovieChange], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MovieService_ServiceDesc.Streams[0], MovieService_WatchMovies_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchMoviesRequest, MovieChange]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MovieService_WatchMoviesClient = grpc.ServerStreamingClient[MovieChange]
+
+func (c *movieServiceClient) SearchMovies(ctx context.Context, in *SearchMoviesRequest, opts ...grpc.CallOption) (*SearchMoviesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchMoviesResponse)
+	err := c.cc.Invoke(ctx, MovieService_SearchMovies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) SuggestTitles(ctx context.Context, in *SuggestTitlesRequest, opts ...grpc.CallOption) (*SuggestTitlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestTitlesResponse)
+	err := c.cc.Invoke(ctx, MovieService_SuggestTitles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MovieServiceServer is the server API for MovieService service.
 // All implementations must embed UnimplementedMovieServiceServer
 // for forward compatibility.
 type MovieServiceServer interface {
-	ListMovies(context.Context, *emptypb.Empty) (*ListMoviesResponse, error)
+	ListMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error)
 	GetMovie(context.Context, *GetMovieRequest) (*GetMovieResponse, error)
 	CreateMovie(context.Context, *CreateMovieRequest) (*CreateMovieResponse, error)
+	UpdateMovie(context.Context, *UpdateMovieRequest) (*UpdateMovieResponse, error)
 	DeleteMovie(context.Context, *DeleteMovieRequest) (*DeleteMovieResponse, error)
+	RestoreMovie(context.Context, *RestoreMovieRequest) (*RestoreMovieResponse, error)
+	// Lixeira: filmes apagados e ainda não purgados, com os mesmos filtros,
+	// ordenação e paginação do ListMovies (page_token de um não vale no outro).
+	ListDeletedMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error)
+	// Filme na lixeira (NOT_FOUND se ele está no catálogo), com a versão
+	// para o expected_version do RestoreMovie.
+	GetDeletedMovie(context.Context, *GetMovieRequest) (*GetMovieResponse, error)
+	WatchMovies(*WatchMoviesRequest, grpc.ServerStreamingServer[MovieChange]) error
+	SearchMovies(context.Context, *SearchMoviesRequest) (*SearchMoviesResponse, error)
+	SuggestTitles(context.Context, *SuggestTitlesRequest) (*SuggestTitlesResponse, error)
 	mustEmbedUnimplementedMovieServiceServer()
 }
 
@@ -102,7 +209,7 @@ type MovieServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedMovieServiceServer struct{}
 
-func (UnimplementedMovieServiceServer) ListMovies(context.Context, *emptypb.Empty) (*ListMoviesResponse, error) {
+func (UnimplementedMovieServiceServer) ListMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListMovies not implemented")
 }
 func (UnimplementedMovieServiceServer) GetMovie(context.Context, *GetMovieRequest) (*GetMovieResponse, error) {
@@ -111,9 +218,30 @@ func (UnimplementedMovieServiceServer) GetMovie(context.Context, *GetMovieReques
 func (UnimplementedMovieServiceServer) CreateMovie(context.Context, *CreateMovieRequest) (*CreateMovieResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateMovie not implemented")
 }
+func (UnimplementedMovieServiceServer) UpdateMovie(context.Context, *UpdateMovieRequest) (*UpdateMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMovie not implemented")
+}
 func (UnimplementedMovieServiceServer) DeleteMovie(context.Context, *DeleteMovieRequest) (*DeleteMovieResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteMovie not implemented")
 }
+func (UnimplementedMovieServiceServer) RestoreMovie(context.Context, *RestoreMovieRequest) (*RestoreMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreMovie not implemented")
+}
+func (UnimplementedMovieServiceServer) ListDeletedMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedMovies not implemented")
+}
+func (UnimplementedMovieServiceServer) GetDeletedMovie(context.Context, *GetMovieRequest) (*GetMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeletedMovie not implemented")
+}
+func (UnimplementedMovieServiceServer) WatchMovies(*WatchMoviesRequest, grpc.ServerStreamingServer[MovieChange]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchMovies not implemented")
+}
+func (UnimplementedMovieServiceServer) SearchMovies(context.Context, *SearchMoviesRequest) (*SearchMoviesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchMovies not implemented")
+}
+func (UnimplementedMovieServiceServer) SuggestTitles(context.Context, *SuggestTitlesRequest) (*SuggestTitlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestTitles not implemented")
+}
 func (UnimplementedMovieServiceServer) mustEmbedUnimplementedMovieServiceServer() {}
 func (UnimplementedMovieServiceServer) testEmbeddedByValue()                      {}
 
@@ -136,7 +264,7 @@ func RegisterMovieServiceServer(s grpc.ServiceRegistrar, srv MovieServiceServer)
 }
 
 func _MovieService_ListMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(ListMoviesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -148,7 +276,7 @@ func _MovieService_ListMovies_Handler(srv interface{}, ctx context.Context, dec
 		FullMethod: MovieService_ListMovies_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MovieServiceServer).ListMovies(ctx, req.(*emptypb.Empty))
+		return srv.(MovieServiceServer).ListMovies(ctx, req.(*ListMoviesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -189,6 +317,24 @@ func _MovieService_CreateMovie_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MovieService_UpdateMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).UpdateMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_UpdateMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).UpdateMovie(ctx, req.(*UpdateMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MovieService_DeleteMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteMovieRequest)
 	if err := dec(in); err != nil {
@@ -207,6 +353,107 @@ func _MovieService_DeleteMovie_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MovieService_RestoreMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).RestoreMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_RestoreMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).RestoreMovie(ctx, req.(*RestoreMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_ListDeletedMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMoviesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).ListDeletedMovies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_ListDeletedMovies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).ListDeletedMovies(ctx, req.(*ListMoviesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_GetDeletedMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).GetDeletedMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_GetDeletedMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).GetDeletedMovie(ctx, req.(*GetMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_WatchMovies_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchMoviesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MovieServiceServer).WatchMovies(m, &grpc.GenericServerStream[WatchMoviesRequest, MovieChange]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MovieService_WatchMoviesServer = grpc.ServerStreamingServer[MovieChange]
+
+func _MovieService_SearchMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchMoviesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).SearchMovies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_SearchMovies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).SearchMovies(ctx, req.(*SearchMoviesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_SuggestTitles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestTitlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).SuggestTitles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_SuggestTitles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).SuggestTitles(ctx, req.(*SuggestTitlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MovieService_ServiceDesc is the grpc.ServiceDesc for MovieService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -226,11 +473,41 @@ var MovieService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateMovie",
 			Handler:    _MovieService_CreateMovie_Handler,
 		},
+		{
+			MethodName: "UpdateMovie",
+			Handler:    _MovieService_UpdateMovie_Handler,
+		},
 		{
 			MethodName: "DeleteMovie",
 			Handler:    _MovieService_DeleteMovie_Handler,
 		},
+		{
+			MethodName: "RestoreMovie",
+			Handler:    _MovieService_RestoreMovie_Handler,
+		},
+		{
+			MethodName: "ListDeletedMovies",
+			Handler:    _MovieService_ListDeletedMovies_Handler,
+		},
+		{
+			MethodName: "GetDeletedMovie",
+			Handler:    _MovieService_GetDeletedMovie_Handler,
+		},
+		{
+			MethodName: "SearchMovies",
+			Handler:    _MovieService_SearchMovies_Handler,
+		},
+		{
+			MethodName: "SuggestTitles",
+			Handler:    _MovieService_SuggestTitles_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchMovies",
+			Handler:       _MovieService_WatchMovies_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "moviespb/movies.proto",
 }